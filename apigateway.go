@@ -0,0 +1,62 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+// APIGatewayResponse is the response structure expected by API Gateway proxy integrations.
+// It marshals to JSON identically to events.APIGatewayProxyResponse of the AWS Lambda SDK.
+type APIGatewayResponse struct {
+	StatusCode        int                 `json:"statusCode"`
+	Headers           map[string]string   `json:"headers"`
+	MultiValueHeaders map[string][]string `json:"multiValueHeaders"`
+	Body              string              `json:"body"`
+	IsBase64Encoded   bool                `json:"isBase64Encoded,omitempty"`
+}
+
+/*
+ToAPIGatewayResponse converts the error to an API Gateway proxy integration response.
+The body is the JSON representation of the error, wrapped in an envelope per SetJSONConfig.
+The mask policy set by SetMaskPolicy is applied to the error.
+The stack trace and suppressed errors are included only in debug mode.
+
+	func handler(ctx context.Context, req events.APIGatewayProxyRequest) (any, error) {
+		res, err := process(ctx, req)
+		if err != nil {
+			return errors.ToAPIGatewayResponse(err), nil
+		}
+		return res, nil
+	}
+*/
+func ToAPIGatewayResponse(err error) APIGatewayResponse {
+	if err == nil {
+		return APIGatewayResponse{
+			StatusCode: 200,
+		}
+	}
+	tracedErr := applyMaskPolicy(Convert(err))
+	body := responseBody(tracedErr)
+	headers := map[string]string{
+		"Content-Type": "application/json",
+	}
+	if tracedErr.Trace != "" && tracedErr.Trace != zeroTrace {
+		headers[HeaderErrorTrace] = escapeHeaderValue(tracedErr.Trace)
+	}
+	return APIGatewayResponse{
+		StatusCode: tracedErr.StatusCode,
+		Headers:    headers,
+		Body:       string(body),
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestErrors_ToAPIGatewayResponse(t *testing.T) {
+	// Not parallel because it modifies the package settings
+
+	err := New("user not found", 404, "0123456789abcdef0123456789abcdef", "userID", 123)
+	err = AddSuppressed(err, New("cleanup failed"))
+	res := ToAPIGatewayResponse(err)
+	assertEqual(t, 404, res.StatusCode)
+	assertEqual(t, "application/json", res.Headers["Content-Type"])
+	assertEqual(t, "0123456789abcdef0123456789abcdef", res.Headers[HeaderErrorTrace])
+
+	var body struct {
+		Err map[string]any `json:"err"`
+	}
+	assertNil(t, json.Unmarshal([]byte(res.Body), &body))
+	assertEqual(t, "user not found", body.Err["error"])
+	assertEqual(t, 404.0, body.Err["statusCode"])
+	assertEqual(t, 123.0, body.Err["userID"])
+	_, ok := body.Err["stack"]
+	assertTrue(t, !ok)
+	_, ok = body.Err["suppressed"]
+	assertTrue(t, !ok)
+
+	// The original error is not modified
+	assertTrue(t, len(Convert(err).Stack) > 0)
+
+	// Stack in debug mode
+	SetDebugMode(true)
+	defer SetDebugMode(false)
+	res = ToAPIGatewayResponse(err)
+	body.Err = nil
+	assertNil(t, json.Unmarshal([]byte(res.Body), &body))
+	_, ok = body.Err["stack"]
+	assertTrue(t, ok)
+	_, ok = body.Err["suppressed"]
+	assertTrue(t, ok)
+
+	// Unmarshalable properties
+	res = ToAPIGatewayResponse(New("oops", "func", func() {}))
+	assertEqual(t, 500, res.StatusCode)
+	assertContains(t, res.Body, `"error":"oops"`)
+
+	// No error
+	res = ToAPIGatewayResponse(nil)
+	assertEqual(t, 200, res.StatusCode)
+
+	// Marshals like the AWS SDK
+	b, _ := json.Marshal(ToAPIGatewayResponse(New("oops", 400)))
+	assertContains(t, string(b), `"statusCode":400,"headers":{"Content-Type":"application/json"},"multiValueHeaders":null,"body":"{\"err\":{`)
+}
@@ -0,0 +1,50 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+// asyncBoundary is the function name of the pseudo frame that separates the stacks of the producing and consuming goroutines.
+const asyncBoundary = "— async boundary —"
+
+/*
+Handoff prepares the error to be passed to another goroutine, for example over a channel.
+It appends the stack of the producing goroutine, followed by an async boundary marker.
+The consuming goroutine should call Resume on the error it receives.
+
+	go func() {
+		errs <- errors.Handoff(err)
+	}()
+	...
+	err := errors.Resume(<-errs)
+*/
+func Handoff(err error) error {
+	if err == nil {
+		return nil
+	}
+	tracedErr := deriveTraced(err)
+	n := len(tracedErr.Stack)
+	tracedErr = Convert(markSite(traceFull(tracedErr, 1), n, "handoff"))
+	tracedErr.Stack = appendFrame(tracedErr.Stack, &StackFrame{Function: asyncBoundary})
+	return tracedErr
+}
+
+// Resume appends the stack of the consuming goroutine to an error that was passed to it with Handoff.
+func Resume(err error) error {
+	if err == nil {
+		return nil
+	}
+	return traceFull(deriveTraced(err), 1)
+}
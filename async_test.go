@@ -0,0 +1,60 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestErrors_HandoffResume(t *testing.T) {
+	t.Parallel()
+
+	errs := make(chan error, 1)
+	go func() {
+		err := New("async")
+		errs <- Handoff(err)
+	}()
+	err := Resume(<-errs)
+	stack := Convert(err).Stack
+
+	boundary := -1
+	for i, frame := range stack {
+		if frame.Function == asyncBoundary {
+			boundary = i
+		}
+	}
+	assertTrue(t, boundary > 0)
+	assertTrue(t, boundary < len(stack)-1)
+	assertTrue(t, stack[boundary].isPseudo())
+	assertTrue(t, !stack[boundary].isElision())
+	for _, frame := range stack[:boundary] {
+		assertTrue(t, strings.HasPrefix(frame.Function, "errors.TestErrors_HandoffResume.func"))
+	}
+	assertEqual(t, "errors.TestErrors_HandoffResume", stack[boundary+1].Function)
+
+	s := Convert(err).String()
+	assertContains(t, s, "\n- "+asyncBoundary+"\n")
+
+	// Round trip through the string representation
+	parsed, parseErr := ParseString(s)
+	assertNil(t, parseErr)
+	assertEqual(t, asyncBoundary, parsed.Stack[boundary].Function)
+
+	assertNil(t, Handoff(nil))
+	assertNil(t, Resume(nil))
+}
@@ -0,0 +1,174 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Ensure interfaces
+var (
+	_ = json.Marshaler(&Batch{})
+)
+
+/*
+Batch records the failures of the individual items of a batch operation, keyed by their index or ID,
+and summarizes them in a single error while preserving the association of each failure with its item.
+It is safe for concurrent use.
+
+	batch := errors.NewBatch(len(orders))
+	for i, order := range orders {
+		batch.Fail(i, process(ctx, order))
+	}
+	if err := batch.Err(); err != nil {
+		return errors.Trace(err)
+	}
+*/
+type Batch struct {
+	mux      sync.Mutex
+	total    int
+	failures []batchFailure
+}
+
+// batchFailure is the failure of an item of a batch.
+type batchFailure struct {
+	id  any
+	err error
+}
+
+// NewBatch creates a new batch of the indicated number of items.
+// A total of zero indicates that the number of items is not known.
+func NewBatch(total int) *Batch {
+	return &Batch{total: max(total, 0)}
+}
+
+// Fail records the failure of the item identified by the index or ID.
+// The failure is traced and the ID is attached to it as the "index" property if it is an int,
+// or as the "key" property otherwise. A nil error is ignored, so that Fail can be called with the result of each item.
+func (b *Batch) Fail(id any, err error) {
+	if err == nil {
+		return
+	}
+	key := "key"
+	if _, ok := id.(int); ok {
+		key = "index"
+	}
+	err = Trace(err, key, id)
+	b.mux.Lock()
+	b.failures = append(b.failures, batchFailure{id: id, err: err})
+	b.mux.Unlock()
+}
+
+// Failed returns the number of items that failed.
+func (b *Batch) Failed() int {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	return len(b.failures)
+}
+
+// Failure returns the failure of the item identified by the index or ID, or nil if it did not fail.
+func (b *Batch) Failure(id any) error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	for _, f := range b.failures {
+		if f.id == id {
+			return f.err
+		}
+	}
+	return nil
+}
+
+/*
+Err returns a summary error of the failures, or nil if no item failed.
+The summary wraps the failures of the individual items so that Is and As match them,
+and has the properties "failed", "total" and "succeeded" if the total is known.
+Its status code is that shared by all failures, or 500 if they differ.
+
+	3 of 10 items failed
+*/
+func (b *Batch) Err() error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	n := len(b.failures)
+	if n == 0 {
+		return nil
+	}
+	errs := make([]error, 0, n)
+	statusCode := 0
+	for i, f := range b.failures {
+		errs = append(errs, f.err)
+		if i == 0 {
+			statusCode = StatusCode(f.err)
+		} else if statusCode != StatusCode(f.err) {
+			statusCode = 500
+		}
+	}
+	summary := &TracedError{
+		StatusCode: statusCode,
+		Properties: map[string]any{"failed": n},
+	}
+	if b.total > 0 {
+		summary.Err = &batchError{msg: fmt.Sprintf("%d of %d items failed", n, b.total), errs: errs}
+		summary.Properties["total"] = b.total
+		summary.Properties["succeeded"] = max(b.total-n, 0)
+	} else {
+		summary.Err = &batchError{msg: fmt.Sprintf("%d items failed", n), errs: errs}
+	}
+	return markSite(traceCaller(summary), 0, "new")
+}
+
+// MarshalJSON marshals the per-item breakdown of the batch to JSON.
+//
+//	{"total":10,"failed":3,"items":[{"id":4,"error":{...}},...]}
+func (b *Batch) MarshalJSON() ([]byte, error) {
+	type item struct {
+		ID    any          `json:"id"`
+		Error *TracedError `json:"error"`
+	}
+	b.mux.Lock()
+	j := struct {
+		Total  int    `json:"total,omitzero"`
+		Failed int    `json:"failed"`
+		Items  []item `json:"items,omitzero"`
+	}{
+		Total:  b.total,
+		Failed: len(b.failures),
+	}
+	for _, f := range b.failures {
+		j.Items = append(j.Items, item{ID: f.id, Error: Convert(f.err)})
+	}
+	b.mux.Unlock()
+	return json.Marshal(j)
+}
+
+// batchError is the summary of the failures of a batch, which wraps the individual failures.
+type batchError struct {
+	msg  string
+	errs []error
+}
+
+// Error returns the summary message.
+func (e *batchError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the individual failures.
+func (e *batchError) Unwrap() []error {
+	return e.errs
+}
@@ -0,0 +1,106 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+)
+
+func TestErrors_Batch(t *testing.T) {
+	t.Parallel()
+
+	batch := NewBatch(10)
+	assertNil(t, batch.Err())
+
+	notFound := New("not found", 404)
+	batch.Fail(2, notFound)
+	batch.Fail(3, nil)
+	batch.Fail(7, New("invalid", 404))
+	assertEqual(t, 2, batch.Failed())
+	assertNil(t, batch.Failure(3))
+	assertEqual(t, 2, Convert(batch.Failure(2)).Properties["index"])
+
+	err := batch.Err()
+	assertEqual(t, "2 of 10 items failed", err.Error())
+	assertEqual(t, 404, StatusCode(err))
+	props := Convert(err).Properties
+	assertEqual(t, 2, props["failed"])
+	assertEqual(t, 10, props["total"])
+	assertEqual(t, 8, props["succeeded"])
+	assertTrue(t, Is(err, notFound))
+	assertEqual(t, "errors.TestErrors_Batch", Convert(err).Stack[0].Function)
+
+	// Mixed status codes
+	batch.Fail("abc", New("oops"))
+	assertEqual(t, 500, StatusCode(batch.Err()))
+	assertEqual(t, "abc", Convert(batch.Failure("abc")).Properties["key"])
+
+	// Unknown total
+	batch = NewBatch(0)
+	batch.Fail(0, New("oops"))
+	err = batch.Err()
+	assertEqual(t, "1 items failed", err.Error())
+	_, ok := Convert(err).Properties["total"]
+	assertTrue(t, !ok)
+}
+
+func TestErrors_BatchConcurrent(t *testing.T) {
+	t.Parallel()
+
+	batch := NewBatch(100)
+	var wg sync.WaitGroup
+	for i := range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if i%2 == 0 {
+				batch.Fail(i, New("oops"))
+			}
+		}()
+	}
+	wg.Wait()
+	assertEqual(t, 50, batch.Failed())
+}
+
+func TestErrors_BatchJSON(t *testing.T) {
+	t.Parallel()
+
+	batch := NewBatch(3)
+	batch.Fail(1, New("not found", 404))
+	batch.Fail("xyz", New("invalid", 400))
+	b, err := json.Marshal(batch)
+	assertNil(t, err)
+
+	var decoded struct {
+		Total  int `json:"total"`
+		Failed int `json:"failed"`
+		Items  []struct {
+			ID    any         `json:"id"`
+			Error TracedError `json:"error"`
+		} `json:"items"`
+	}
+	assertNil(t, json.Unmarshal(b, &decoded))
+	assertEqual(t, 3, decoded.Total)
+	assertEqual(t, 2, decoded.Failed)
+	assertEqual(t, 2, len(decoded.Items))
+	assertEqual(t, float64(1), decoded.Items[0].ID)
+	assertEqual(t, 404, decoded.Items[0].Error.StatusCode)
+	assertEqual(t, "xyz", decoded.Items[1].ID)
+	assertEqual(t, "invalid", decoded.Items[1].Error.Error())
+}
@@ -0,0 +1,137 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ErrorDef defines an error that can be constructed by its code from a Catalog.
+type ErrorDef struct {
+	// Code uniquely identifies the error, e.g. "user.not_found". It is set as the "code" property of the error.
+	Code string `json:"code"`
+	// StatusCode is the default HTTP status code of the error. Zero indicates 500.
+	StatusCode int `json:"statusCode,omitzero"`
+	// Message is the default message template of the error. It may contain % signs as a pattern of New.
+	// If empty, the status text is used.
+	Message string `json:"message,omitzero"`
+	// DocsURL links to documentation of the error. It is set as the "help" property of the error.
+	DocsURL string `json:"docsURL,omitzero"`
+}
+
+/*
+Catalog is a registry of error definitions that are constructed by their code.
+It can enumerate the registered definitions at runtime, for example to generate documentation or client SDKs.
+
+	var catalog = errors.NewCatalog()
+
+	func init() {
+		catalog.Register(errors.ErrorDef{
+			Code:       "user.not_found",
+			StatusCode: http.StatusNotFound,
+			Message:    "user not found",
+		})
+	}
+
+	return catalog.New("user.not_found", "userID", id)
+*/
+type Catalog struct {
+	defs map[string]ErrorDef
+	mux  sync.RWMutex
+}
+
+// NewCatalog creates a new empty catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{
+		defs: map[string]ErrorDef{},
+	}
+}
+
+// Register adds an error definition to the catalog.
+// It is an error to register a definition with an empty code or a code that is already registered.
+func (c *Catalog) Register(def ErrorDef) error {
+	if def.Code == "" {
+		return New("missing error code")
+	}
+	if def.StatusCode == 0 {
+		def.StatusCode = 500
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if _, ok := c.defs[def.Code]; ok {
+		return New("error code '%s' is already registered", def.Code, http.StatusConflict)
+	}
+	c.defs[def.Code] = def
+	return nil
+}
+
+// Unregister removes the definition of the error code from the catalog, if registered.
+func (c *Catalog) Unregister(code string) {
+	c.mux.Lock()
+	delete(c.defs, code)
+	c.mux.Unlock()
+}
+
+// Lookup returns the definition of the error code, if registered.
+func (c *Catalog) Lookup(code string) (def ErrorDef, ok bool) {
+	c.mux.RLock()
+	def, ok = c.defs[code]
+	c.mux.RUnlock()
+	return def, ok
+}
+
+// Definitions returns all registered error definitions, sorted by their code.
+func (c *Catalog) Definitions() []ErrorDef {
+	c.mux.RLock()
+	defs := slices.Collect(maps.Values(c.defs))
+	c.mux.RUnlock()
+	slices.SortFunc(defs, func(a, b ErrorDef) int {
+		return strings.Compare(a.Code, b.Code)
+	})
+	return defs
+}
+
+/*
+New creates a new error per the definition of the error code.
+The arguments behave like those of New: the first arguments format the message template of the definition,
+and the rest are added to the error's property bag or override its status code.
+An unregistered code results in an error with a status code of 500.
+
+	return catalog.New("user.not_found", "userID", id)
+*/
+func (c *Catalog) New(code string, args ...any) error {
+	def, ok := c.Lookup(code)
+	if !ok {
+		return New("unregistered error code '%s'", append([]any{code, "code", code}, args...)...)
+	}
+	pctArgs := strings.Count(def.Message, `%`) - 2*strings.Count(def.Message, `%%`)
+	pctArgs = max(min(pctArgs, len(args)), 0)
+	defaults := []any{def.StatusCode, "code", def.Code}
+	if def.DocsURL != "" {
+		defaults = append(defaults, "help", def.DocsURL)
+	}
+	// The defaults precede the arguments so that they can be overridden by them
+	newArgs := make([]any, 0, len(args)+len(defaults))
+	newArgs = append(newArgs, args[:pctArgs]...)
+	newArgs = append(newArgs, defaults...)
+	newArgs = append(newArgs, args[pctArgs:]...)
+	return New(def.Message, newArgs...)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestErrors_Catalog(t *testing.T) {
+	t.Parallel()
+
+	catalog := NewCatalog()
+	err := catalog.Register(ErrorDef{
+		Code:       "user.not_found",
+		StatusCode: http.StatusNotFound,
+		Message:    "user %d not found",
+		DocsURL:    "https://example.com/errors/user.not_found",
+	})
+	assertNil(t, err)
+	err = catalog.Register(ErrorDef{
+		Code: "internal",
+	})
+	assertNil(t, err)
+	err = catalog.Register(ErrorDef{
+		Code: "internal",
+	})
+	assertError(t, err)
+	assertEqual(t, http.StatusConflict, StatusCode(err))
+	assertError(t, catalog.Register(ErrorDef{}))
+
+	err = catalog.New("user.not_found", 123, "key", "value")
+	assertEqual(t, "user 123 not found", err.Error())
+	tracedErr := Convert(err)
+	assertEqual(t, 404, tracedErr.StatusCode)
+	assertEqual(t, "user.not_found", tracedErr.Properties["code"])
+	assertEqual(t, "https://example.com/errors/user.not_found", Help(err))
+	assertEqual(t, "value", tracedErr.Properties["key"])
+	assertEqual(t, 1, len(tracedErr.Stack))
+	assertEqual(t, "errors.TestErrors_Catalog", tracedErr.Stack[0].Function)
+
+	// Arguments override the defaults
+	err = catalog.New("user.not_found", 123, http.StatusGone)
+	assertEqual(t, 410, StatusCode(err))
+
+	// Empty message template
+	err = catalog.New("internal")
+	assertEqual(t, 500, StatusCode(err))
+	assertEqual(t, "internal server error", err.Error())
+
+	// Unregistered code
+	err = catalog.New("unknown", "key", "value")
+	assertEqual(t, 500, StatusCode(err))
+	assertEqual(t, "unknown", Convert(err).Properties["code"])
+	assertContains(t, err.Error(), "unknown")
+
+	defs := catalog.Definitions()
+	assertEqual(t, 2, len(defs))
+	assertEqual(t, "internal", defs[0].Code)
+	assertEqual(t, 500, defs[0].StatusCode)
+	assertEqual(t, "user.not_found", defs[1].Code)
+
+	def, ok := catalog.Lookup("user.not_found")
+	assertTrue(t, ok)
+	assertEqual(t, "user %d not found", def.Message)
+	_, ok = catalog.Lookup("unknown")
+	assertTrue(t, !ok)
+
+	// Unregistered definitions can be registered again
+	catalog.Unregister("internal")
+	catalog.Unregister("unknown")
+	_, ok = catalog.Lookup("internal")
+	assertTrue(t, !ok)
+	assertEqual(t, 1, len(catalog.Definitions()))
+	assertNil(t, catalog.Register(ErrorDef{Code: "internal"}))
+}
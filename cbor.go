@@ -0,0 +1,288 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/binary"
+	"maps"
+	"math"
+	"slices"
+)
+
+// CBOR major types
+const (
+	cborUint   = 0
+	cborNegInt = 1
+	cborBytes  = 2
+	cborText   = 3
+	cborArray  = 4
+	cborMap    = 5
+	cborTag    = 6
+	cborSimple = 7
+)
+
+/*
+MarshalCBOR marshals the error to CBOR (RFC 8949).
+The layout mirrors that of the JSON encoding: a map with the error, statusCode, trace, span, origin, stack and suppressed keys,
+alongside the properties of the error.
+Property values of types that have no native CBOR representation are encoded following their JSON representation.
+*/
+func (e *TracedError) MarshalCBOR() ([]byte, error) {
+	var visited visitedSet
+	return cborAppend(nil, e.scrubbed().binaryMap(&visited)), nil
+}
+
+// UnmarshalCBOR unmarshals the error from CBOR.
+// Integer properties are restored as int64 or uint64.
+// Neither the type of the error nor any errors it wraps can be restored.
+func (e *TracedError) UnmarshalCBOR(data []byte) error {
+	v, rest, err := cborDecode(data, 0)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return New("trailing data after cbor value")
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return New("cbor value is not a map")
+	}
+	return e.fromBinaryMap(m)
+}
+
+// cborAppend appends the CBOR encoding of a value.
+func cborAppend(b []byte, v any) []byte {
+	switch x := v.(type) {
+	case nil:
+		return append(b, 0xf6)
+	case bool:
+		if x {
+			return append(b, 0xf5)
+		}
+		return append(b, 0xf4)
+	case int:
+		return cborAppendInt(b, int64(x))
+	case int8:
+		return cborAppendInt(b, int64(x))
+	case int16:
+		return cborAppendInt(b, int64(x))
+	case int32:
+		return cborAppendInt(b, int64(x))
+	case int64:
+		return cborAppendInt(b, x)
+	case uint:
+		return cborAppendHeader(b, cborUint, uint64(x))
+	case uint8:
+		return cborAppendHeader(b, cborUint, uint64(x))
+	case uint16:
+		return cborAppendHeader(b, cborUint, uint64(x))
+	case uint32:
+		return cborAppendHeader(b, cborUint, uint64(x))
+	case uint64:
+		return cborAppendHeader(b, cborUint, x)
+	case float32:
+		b = append(b, 0xfa)
+		return binary.BigEndian.AppendUint32(b, math.Float32bits(x))
+	case float64:
+		b = append(b, 0xfb)
+		return binary.BigEndian.AppendUint64(b, math.Float64bits(x))
+	case string:
+		b = cborAppendHeader(b, cborText, uint64(len(x)))
+		return append(b, x...)
+	case []byte:
+		b = cborAppendHeader(b, cborBytes, uint64(len(x)))
+		return append(b, x...)
+	case []any:
+		b = cborAppendHeader(b, cborArray, uint64(len(x)))
+		for _, item := range x {
+			b = cborAppend(b, item)
+		}
+		return b
+	case map[string]any:
+		b = cborAppendHeader(b, cborMap, uint64(len(x)))
+		for _, k := range slices.Sorted(maps.Keys(x)) {
+			b = cborAppend(b, k)
+			b = cborAppend(b, x[k])
+		}
+		return b
+	}
+	// Fall back to the JSON representation
+	normalized := normalizeProperties(map[string]any{"": v})
+	return cborAppend(b, normalized[""])
+}
+
+// cborAppendInt appends a signed integer.
+func cborAppendInt(b []byte, v int64) []byte {
+	if v >= 0 {
+		return cborAppendHeader(b, cborUint, uint64(v))
+	}
+	return cborAppendHeader(b, cborNegInt, uint64(-1-v))
+}
+
+// cborAppendHeader appends the initial byte of a data item of the major type, followed by its argument in its most compact form.
+func cborAppendHeader(b []byte, major byte, arg uint64) []byte {
+	major <<= 5
+	switch {
+	case arg < 24:
+		return append(b, major|byte(arg))
+	case arg <= math.MaxUint8:
+		return append(b, major|24, byte(arg))
+	case arg <= math.MaxUint16:
+		b = append(b, major|25)
+		return binary.BigEndian.AppendUint16(b, uint16(arg))
+	case arg <= math.MaxUint32:
+		b = append(b, major|26)
+		return binary.BigEndian.AppendUint32(b, uint32(arg))
+	default:
+		b = append(b, major|27)
+		return binary.BigEndian.AppendUint64(b, arg)
+	}
+}
+
+// cborDecode decodes a single data item and returns the remaining data.
+// Maps are decoded as map[string]any, arrays as []any and integers as int64 or uint64.
+// Tags are ignored and indefinite-length items are not supported.
+func cborDecode(data []byte, depth int) (v any, rest []byte, err error) {
+	if depth > 32 {
+		return nil, nil, New("cbor value nested too deeply")
+	}
+	if len(data) == 0 {
+		return nil, nil, New("unexpected end of cbor data")
+	}
+	major := data[0] >> 5
+	info := data[0] & 0x1f
+	data = data[1:]
+
+	// Read the argument
+	var arg uint64
+	switch {
+	case info < 24:
+		arg = uint64(info)
+	case info <= 27:
+		size := 1 << (info - 24)
+		if len(data) < size {
+			return nil, nil, New("truncated cbor data")
+		}
+		for _, x := range data[:size] {
+			arg = arg<<8 | uint64(x)
+		}
+		data = data[size:]
+	default:
+		return nil, nil, New("unsupported cbor additional info %d", info)
+	}
+
+	switch major {
+	case cborUint:
+		if arg <= math.MaxInt64 {
+			return int64(arg), data, nil
+		}
+		return arg, data, nil
+	case cborNegInt:
+		if arg > math.MaxInt64 {
+			return nil, nil, New("cbor negative integer overflows")
+		}
+		return -1 - int64(arg), data, nil
+	case cborBytes, cborText:
+		if arg > uint64(len(data)) {
+			return nil, nil, New("truncated cbor data")
+		}
+		p := data[:arg]
+		data = data[arg:]
+		if major == cborText {
+			return string(p), data, nil
+		}
+		return slices.Clone(p), data, nil
+	case cborArray:
+		if arg > uint64(len(data)) {
+			return nil, nil, New("truncated cbor data")
+		}
+		arr := make([]any, 0, arg)
+		for range arg {
+			var item any
+			item, data, err = cborDecode(data, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			arr = append(arr, item)
+		}
+		return arr, data, nil
+	case cborMap:
+		if arg > uint64(len(data)) {
+			return nil, nil, New("truncated cbor data")
+		}
+		m := make(map[string]any, arg)
+		for range arg {
+			var k, item any
+			k, data, err = cborDecode(data, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			key, ok := k.(string)
+			if !ok {
+				return nil, nil, New("cbor map key is not a string")
+			}
+			item, data, err = cborDecode(data, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			m[key] = item
+		}
+		return m, data, nil
+	case cborTag:
+		return cborDecode(data, depth+1)
+	}
+
+	// Simple values and floats
+	switch info {
+	case 20:
+		return false, data, nil
+	case 21:
+		return true, data, nil
+	case 22, 23:
+		return nil, data, nil
+	case 25:
+		return float16ToFloat64(uint16(arg)), data, nil
+	case 26:
+		return float64(math.Float32frombits(uint32(arg))), data, nil
+	case 27:
+		return math.Float64frombits(arg), data, nil
+	}
+	return nil, nil, New("unsupported cbor simple value %d", arg)
+}
+
+// float16ToFloat64 converts an IEEE 754 half-precision float.
+func float16ToFloat64(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 0x1f:
+		if mant == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		f = -f
+	}
+	return f
+}
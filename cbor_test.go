@@ -0,0 +1,100 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestErrors_CBOR(t *testing.T) {
+	t.Parallel()
+
+	err := New("oops", 409, "00-0123456789abcdef0123456789abcdef-0123456789abcdef-01",
+		"str", "value",
+		"small", 5,
+		"negative", -300,
+		"big", uint64(1<<63),
+		"float", 1.5,
+		"flag", true,
+		"nothing", nil,
+		"bytes", []byte{1, 2, 3},
+		"list", []any{"a", 1},
+		"nested", map[string]any{"x": "y"},
+		"long", strings.Repeat("x", 300),
+		"struct", struct{ A int }{A: 1},
+	)
+	err = Trace(err)
+	err = AddSuppressed(err, New("rollback failed", "table", "users"))
+	original := Convert(err)
+
+	b, marshalErr := original.MarshalCBOR()
+	assertNil(t, marshalErr)
+	var decoded TracedError
+	marshalErr = decoded.UnmarshalCBOR(b)
+	assertNil(t, marshalErr)
+	assertEqual(t, "oops", decoded.Error())
+	assertEqual(t, 409, decoded.StatusCode)
+	assertEqual(t, "0123456789abcdef0123456789abcdef", decoded.Trace)
+	assertEqual(t, "0123456789abcdef", decoded.Span)
+	assertEqual(t, original.Stack, decoded.Stack)
+	assertEqual(t, "value", decoded.Properties["str"])
+	assertEqual(t, int64(5), decoded.Properties["small"])
+	assertEqual(t, int64(-300), decoded.Properties["negative"])
+	assertEqual(t, uint64(1<<63), decoded.Properties["big"])
+	assertEqual(t, 1.5, decoded.Properties["float"])
+	assertEqual(t, true, decoded.Properties["flag"])
+	assertEqual(t, nil, decoded.Properties["nothing"])
+	assertEqual(t, []byte{1, 2, 3}, decoded.Properties["bytes"])
+	assertEqual(t, []any{"a", int64(1)}, decoded.Properties["list"])
+	assertEqual(t, map[string]any{"x": "y"}, decoded.Properties["nested"])
+	assertEqual(t, strings.Repeat("x", 300), decoded.Properties["long"])
+	assertEqual(t, map[string]any{"A": 1.0}, decoded.Properties["struct"])
+	assertEqual(t, 1, len(decoded.Suppressed))
+	assertEqual(t, "rollback failed", decoded.Suppressed[0].Error())
+	assertEqual(t, "users", Convert(decoded.Suppressed[0]).Properties["table"])
+
+	// Smaller than JSON
+	j, _ := json.Marshal(original)
+	assertTrue(t, len(b) < len(j))
+}
+
+func TestErrors_CBORDecode(t *testing.T) {
+	t.Parallel()
+
+	// {"error": "x", "f": 1.5 as float16, "t": tag 1 wrapping 0}
+	data := []byte{0xa3,
+		0x65, 'e', 'r', 'r', 'o', 'r', 0x61, 'x',
+		0x61, 'f', 0xf9, 0x3e, 0x00,
+		0x61, 't', 0xc1, 0x00,
+	}
+	var decoded TracedError
+	err := decoded.UnmarshalCBOR(data)
+	assertNil(t, err)
+	assertEqual(t, "x", decoded.Error())
+	assertEqual(t, 1.5, decoded.Properties["f"])
+	assertEqual(t, int64(0), decoded.Properties["t"])
+
+	// Malformed
+	assertError(t, decoded.UnmarshalCBOR(nil))
+	assertError(t, decoded.UnmarshalCBOR([]byte{0x01}))
+	assertError(t, decoded.UnmarshalCBOR([]byte{0xa1, 0x61}))
+	assertError(t, decoded.UnmarshalCBOR([]byte{0xa1, 0x01, 0x01}))
+	assertError(t, decoded.UnmarshalCBOR([]byte{0xbf}))
+	assertError(t, decoded.UnmarshalCBOR([]byte{0xa0, 0xa0}))
+}
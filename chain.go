@@ -0,0 +1,157 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+/*
+Messages returns the messages of the layers of the wrap chain of the error, outermost first.
+Unlike Error, which concatenates the messages, each layer contributes only its own annotation,
+so that the chain of causes can be rendered as a list.
+New records its own annotation separately from the errors it wraps, whether they are wrapped with %w or passed as arguments.
+Layers added by other means, such as fmt.Errorf("...: %w", err), are separated at the colon that precedes the message of the wrapped error.
+A foreign layer that formats the wrapped error differently, e.g. "... (%w)", is returned with its full message and ends the chain.
+
+	err := errors.New("failed to save", fmt.Errorf("writing file: %w", fs.ErrPermission))
+	errors.Messages(err) // ["failed to save", "writing file", "permission denied"]
+*/
+func Messages(err error) []string {
+	if err == nil {
+		return nil
+	}
+	return chainMessages(err)
+}
+
+// chainLayer is a layer of the wrap chain composed by New.
+type chainLayer struct {
+	err        error   // The composed error that the annotation pertains to
+	annotation string  // The message of the layer, excluding the messages of the errors it wraps
+	causes     []error // The errors wrapped by the layer, in order
+}
+
+// newChainLayer records the own annotation of the error composed by New, and the errors it wraps.
+// The annotation is the message of the pattern, with its %w arguments omitted, or the status text.
+func newChainLayer(err error, own error, pattern string, args []any, causes []error) *chainLayer {
+	layer := &chainLayer{
+		err:    err,
+		causes: causes,
+	}
+	switch {
+	case own == nil:
+	case pattern != "" && strings.Contains(pattern, "%w"):
+		// Format the pattern with the wrapped errors rendered as empty strings
+		p := strings.ReplaceAll(pattern, "%%", "\x00")
+		p = strings.ReplaceAll(p, "%w", "%.0v")
+		p = strings.ReplaceAll(p, "\x00", "%%")
+		layer.annotation = strings.Trim(fmt.Sprintf(p, args...), ": ")
+	default:
+		layer.annotation = own.Error()
+	}
+	return layer
+}
+
+// unwrapCauses returns the errors wrapped by the error.
+func unwrapCauses(err error) []error {
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		if inner := u.Unwrap(); inner != nil {
+			return []error{inner}
+		}
+	case interface{ Unwrap() []error }:
+		return slices.Clone(u.Unwrap())
+	}
+	return nil
+}
+
+// chainMessages returns the messages of the layers of the wrap chain of the error, outermost first.
+// Layers composed by New contribute their recorded annotation.
+// The message of other layers excludes the messages of the errors they wrap, as long as the layer
+// appends the message of the wrapped error after a colon, as does fmt.Errorf("...: %w", err).
+// Otherwise, the layer's message is its full message and the chain ends there.
+// Traced errors that were not composed by New, and wrappers that add no message of their own, do not constitute layers.
+func chainMessages(err error) []string {
+	var visited visitedSet
+	return appendChainMessages(nil, err, &visited)
+}
+
+// appendChainMessages appends the messages of the layers of the wrap chain of the error.
+func appendChainMessages(msgs []string, err error, visited *visitedSet) []string {
+	for err != nil && visited.visit(err) {
+		if tracedErr, ok := err.(*TracedError); ok {
+			// The recorded layer is disregarded if the error was modified since it was composed
+			if layer := tracedErr.layer; layer != nil && layer.err == tracedErr.Err {
+				if layer.annotation != "" {
+					msgs = append(msgs, layer.annotation)
+				}
+				for _, cause := range layer.causes {
+					msgs = appendChainMessages(msgs, cause, visited)
+				}
+				return msgs
+			}
+			err = tracedErr.Err
+			continue
+		}
+		msg := err.Error()
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			inner := u.Unwrap()
+			if inner == nil {
+				break
+			}
+			innerMsg := inner.Error()
+			if msg == innerMsg {
+				err = inner
+				continue
+			}
+			if own, ok := strings.CutSuffix(msg, ": "+innerMsg); ok {
+				msgs = append(msgs, own)
+				err = inner
+				continue
+			}
+		case interface{ Unwrap() []error }:
+			children := u.Unwrap()
+			childMsgs := make([]string, 0, len(children))
+			for _, child := range children {
+				if child != nil {
+					childMsgs = append(childMsgs, child.Error())
+				}
+			}
+			if len(childMsgs) == len(children) && len(children) > 0 && msg == strings.Join(childMsgs, ": ") {
+				for _, child := range children {
+					msgs = appendChainMessages(msgs, child, visited)
+				}
+				return msgs
+			}
+		}
+		return append(msgs, msg)
+	}
+	return msgs
+}
+
+// elideChain limits the message of the error to the configured maximum number of layers of its wrap chain.
+// The omitted layers are indicated by "... (+N more)".
+func elideChain(msg string, err error, maxDepth int) string {
+	msgs := chainMessages(err)
+	if len(msgs) <= maxDepth {
+		return msg
+	}
+	return fmt.Sprintf("%s: ... (+%d more)", strings.Join(msgs[:maxDepth], ": "), len(msgs)-maxDepth)
+}
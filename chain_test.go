@@ -0,0 +1,85 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io/fs"
+	"testing"
+)
+
+func TestErrors_Messages(t *testing.T) {
+	t.Parallel()
+
+	err := New("failed to save", fmt.Errorf("writing file: %w", fs.ErrPermission))
+	assertEqual(t, []string{"failed to save", "writing file", "permission denied"}, Messages(err))
+
+	// Traced errors add no layers of their own
+	err = Trace(Trace(err))
+	assertEqual(t, []string{"failed to save", "writing file", "permission denied"}, Messages(err))
+
+	// Pattern with %w
+	err = New("failed to open %s: %w", "file.txt", fs.ErrNotExist)
+	assertEqual(t, []string{"failed to open file.txt", "file does not exist"}, Messages(err))
+
+	// Multiple wrapped errors
+	err = New("failed", stderrors.New("a"), New("b", stderrors.New("c")))
+	assertEqual(t, []string{"failed", "a", "b", "c"}, Messages(err))
+
+	// Status text
+	err = New("", 404, New("missing"))
+	assertEqual(t, []string{"not found", "missing"}, Messages(err))
+	err = New("", New("missing"), 404)
+	assertEqual(t, []string{"missing"}, Messages(err))
+
+	// Foreign formats end the chain
+	err = New("a", fmt.Errorf("b (%w)", stderrors.New("c")))
+	assertEqual(t, []string{"a", "b (c)"}, Messages(err))
+
+	// Joined errors are not a chain
+	err = New("a", stderrors.Join(stderrors.New("b"), stderrors.New("c")))
+	assertEqual(t, []string{"a", "b\nc"}, Messages(err))
+
+	assertNil(t, Messages(nil))
+	assertEqual(t, []string{"oops"}, Messages(stderrors.New("oops")))
+}
+
+func TestErrors_MessagesRecorded(t *testing.T) {
+	t.Parallel()
+
+	// Messages that contain the separator do not collapse the chain
+	err := New("step: one", New("step: two", stderrors.New("cause: three")))
+	assertEqual(t, []string{"step: one", "step: two", "cause: three"}, Messages(err))
+
+	// Pattern with %w that is not at the end
+	err = New("failed (%w) to open %s", fs.ErrNotExist, "file.txt")
+	assertEqual(t, []string{"failed () to open file.txt", "file does not exist"}, Messages(err))
+
+	// Pattern with %w and additional wrapped errors
+	err = New("failed to open %s: %w", "file.txt", fs.ErrNotExist, stderrors.New("retry failed"))
+	assertEqual(t, []string{"failed to open file.txt", "file does not exist", "retry failed"}, Messages(err))
+
+	// Escaped percent signs
+	err = New("100%% %w", stderrors.New("full"))
+	assertEqual(t, []string{"100%", "full"}, Messages(err))
+
+	// A modified message disregards the recorded layer
+	tracedErr := Convert(New("outer", stderrors.New("inner"))).derive()
+	tracedErr.Err = stderrors.New("replaced")
+	assertEqual(t, []string{"replaced"}, Messages(tracedErr))
+}
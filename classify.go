@@ -0,0 +1,124 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import "sync"
+
+// Class is the classification of an error, for use by circuit breakers, bulkheads and the like.
+type Class int
+
+const (
+	// Unclassified is the class of a nil error.
+	Unclassified Class = iota
+	// Transient errors may succeed if retried, such as timeouts and overloaded upstreams.
+	Transient
+	// Permanent errors will not succeed no matter how many times they are retried or by whom, such as unimplemented operations.
+	Permanent
+	// ClientFault errors are caused by the caller, such as invalid input or missing permissions,
+	// and are not an indication of the health of the service.
+	ClientFault
+	// ServerFault errors are caused by the service and are an indication of its health.
+	ServerFault
+)
+
+// String returns the name of the class.
+func (c Class) String() string {
+	switch c {
+	case Transient:
+		return "transient"
+	case Permanent:
+		return "permanent"
+	case ClientFault:
+		return "clientFault"
+	case ServerFault:
+		return "serverFault"
+	default:
+		return "unclassified"
+	}
+}
+
+// Classifier classifies an error. It returns false if it does not recognize the error.
+type Classifier func(err error) (class Class, ok bool)
+
+var (
+	classifiers    []*Classifier
+	classifiersMux sync.RWMutex
+)
+
+/*
+RegisterClassifier registers a classifier that takes precedence over the built-in classification of Classify.
+Classifiers are consulted in the order of their registration.
+The returned function unregisters the classifier, for example at the end of a test.
+
+	errors.RegisterClassifier(func(err error) (errors.Class, bool) {
+		if errors.Is(err, sql.ErrConnDone) {
+			return errors.Transient, true
+		}
+		return 0, false
+	})
+*/
+func RegisterClassifier(classifier Classifier) (unregister func()) {
+	if classifier == nil {
+		return func() {}
+	}
+	entry := &classifier
+	classifiersMux.Lock()
+	classifiers = append(classifiers, entry)
+	classifiersMux.Unlock()
+	return func() {
+		classifiersMux.Lock()
+		classifiers = withoutEntry(classifiers, entry)
+		classifiersMux.Unlock()
+	}
+}
+
+/*
+Classify classifies the error, so that circuit breakers and bulkheads across services share one source of truth.
+The registered classifiers are consulted first.
+Otherwise, errors that are retryable per IsRetryable are transient,
+errors with the status codes 501, 505 or 510 are permanent,
+other errors with a 4xx status code are client faults,
+and all other errors are server faults.
+
+	if errors.Classify(err) == errors.ServerFault {
+		breaker.RecordFailure()
+	}
+*/
+func Classify(err error) Class {
+	if err == nil {
+		return Unclassified
+	}
+	classifiersMux.RLock()
+	registered := classifiers
+	classifiersMux.RUnlock()
+	for _, classifier := range registered {
+		if class, ok := (*classifier)(err); ok && class != Unclassified {
+			return class
+		}
+	}
+	if IsRetryable(err) {
+		return Transient
+	}
+	switch statusCode := StatusCode(err); {
+	case statusCode == 501, statusCode == 505, statusCode == 510:
+		return Permanent
+	case statusCode >= 400 && statusCode < 500:
+		return ClientFault
+	default:
+		return ServerFault
+	}
+}
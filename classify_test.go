@@ -0,0 +1,65 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	"io/fs"
+	"testing"
+	"time"
+)
+
+func TestErrors_Classify(t *testing.T) {
+	t.Parallel()
+
+	assertEqual(t, Unclassified, Classify(nil))
+	assertEqual(t, Transient, Classify(New("unavailable", 503)))
+	assertEqual(t, Transient, Classify(context.DeadlineExceeded))
+	assertEqual(t, Transient, Classify(New("busy", "retryable", true)))
+	assertEqual(t, Transient, Classify(WithRetryAfter(New("slow down", 400), time.Second)))
+	assertEqual(t, Permanent, Classify(New("not implemented", 501)))
+	assertEqual(t, ClientFault, Classify(New("bad input", 400)))
+	assertEqual(t, ClientFault, Classify(fs.ErrNotExist))
+	assertEqual(t, ClientFault, Classify(New("canceled", 499, context.Canceled)))
+	assertEqual(t, ServerFault, Classify(New("oops")))
+	assertEqual(t, ServerFault, Classify(New("unavailable", 503, "retryable", false)))
+
+	assertEqual(t, "transient", Transient.String())
+	assertEqual(t, "serverFault", ServerFault.String())
+	assertEqual(t, "unclassified", Class(99).String())
+}
+
+type classifiedError struct{}
+
+func (classifiedError) Error() string { return "classified" }
+
+func TestErrors_RegisterClassifier(t *testing.T) {
+	t.Parallel()
+
+	unregister := RegisterClassifier(func(err error) (Class, bool) {
+		if Is(err, classifiedError{}) {
+			return Permanent, true
+		}
+		return Unclassified, false
+	})
+	assertEqual(t, Permanent, Classify(New("wrapped", classifiedError{})))
+	assertEqual(t, ServerFault, Classify(New("oops")))
+
+	unregister()
+	assertEqual(t, ServerFault, Classify(New("wrapped", classifiedError{})))
+	RegisterClassifier(nil)()
+}
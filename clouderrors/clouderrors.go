@@ -0,0 +1,247 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package clouderrors maps the errors of cloud SDKs to traced errors, so that infrastructure code produces consistent errors.
+The errors of the AWS SDK (smithy), of Google Cloud client libraries (googleapi and apierror)
+and of the Kubernetes API (apierrors) are recognized.
+
+The status code of the error is that of the HTTP response of the cloud API, and the following properties are attached, if known:
+
+  - "code" is the error code, e.g. "ThrottlingException", "RATE_LIMIT_EXCEEDED" or "AlreadyExists"
+  - "requestID" is the ID of the request, for correlation with the logs of the cloud provider
+  - "retryable" indicates whether the cloud SDK considers the operation to be retryable
+  - "retryAfter" is the duration to wait before retrying, per errors.RetryAfter
+
+Cloud errors are mapped by Trace, or by New and Trace of the errors package once Register is called.
+
+	out, err := s3Client.GetObject(ctx, input)
+	if err != nil {
+		return clouderrors.Trace(err)
+	}
+*/
+package clouderrors
+
+import (
+	stderrors "errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"github.com/googleapis/gax-go/v2/apierror"
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/errors/internal/rpccode"
+	"google.golang.org/api/googleapi"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Properties attached to cloud errors
+const (
+	propCode       = "code"
+	propRequestID  = "requestID"
+	propRetryable  = "retryable"
+	propRetryAfter = "retryAfter"
+)
+
+// throttlingCodes are the error codes of the AWS SDK that indicate throttling.
+var throttlingCodes = map[string]bool{
+	"Throttling":                             true,
+	"ThrottlingException":                    true,
+	"ThrottledException":                     true,
+	"RequestThrottledException":              true,
+	"TooManyRequestsException":               true,
+	"ProvisionedThroughputExceededException": true,
+	"TransactionInProgressException":         true,
+	"RequestLimitExceeded":                   true,
+	"BandwidthLimitExceeded":                 true,
+	"LimitExceededException":                 true,
+	"RequestThrottled":                       true,
+	"SlowDown":                               true,
+	"PriorRequestNotComplete":                true,
+	"EC2ThrottledException":                  true,
+}
+
+// cloudError is the information extracted from the error of a cloud SDK.
+type cloudError struct {
+	statusCode int
+	code       string
+	requestID  string
+	retryable  *bool
+	retryAfter time.Duration
+}
+
+// inspect extracts the information of the error of a cloud SDK, if the error is recognized.
+func inspect(err error) (info cloudError, ok bool) {
+	if err == nil {
+		return info, false
+	}
+	if info, ok = inspectKubernetes(err); ok {
+		return info, true
+	}
+	if info, ok = inspectGoogle(err); ok {
+		return info, true
+	}
+	return inspectAWS(err)
+}
+
+// inspectAWS extracts the information of an error of the AWS SDK.
+// The HTTP response, request ID and retryability are accessed through the interfaces implemented by the errors of the SDK,
+// so that the SDK itself is not a dependency.
+func inspectAWS(err error) (info cloudError, ok bool) {
+	var apiErr smithy.APIError
+	if !stderrors.As(err, &apiErr) {
+		return info, false
+	}
+	info.code = apiErr.ErrorCode()
+	var httpErr interface{ HTTPStatusCode() int }
+	if stderrors.As(err, &httpErr) {
+		info.statusCode = httpErr.HTTPStatusCode()
+	}
+	var requestErr interface{ ServiceRequestID() string }
+	if stderrors.As(err, &requestErr) {
+		info.requestID = requestErr.ServiceRequestID()
+	}
+	var retryableErr interface{ RetryableError() bool }
+	if stderrors.As(err, &retryableErr) {
+		retryable := retryableErr.RetryableError()
+		info.retryable = &retryable
+	} else if throttlingCodes[info.code] {
+		retryable := true
+		info.retryable = &retryable
+	}
+	if info.statusCode == 0 {
+		switch {
+		case throttlingCodes[info.code]:
+			info.statusCode = http.StatusTooManyRequests
+		case apiErr.ErrorFault() == smithy.FaultClient:
+			info.statusCode = http.StatusBadRequest
+		default:
+			info.statusCode = http.StatusInternalServerError
+		}
+	}
+	return info, true
+}
+
+// inspectGoogle extracts the information of an error of a Google Cloud client library,
+// either REST-based (googleapi) or gRPC-based (apierror).
+func inspectGoogle(err error) (info cloudError, ok bool) {
+	var apiErr *apierror.APIError
+	if stderrors.As(err, &apiErr) {
+		ok = true
+		info.code = apiErr.Reason()
+		if httpCode := apiErr.HTTPCode(); httpCode > 0 {
+			info.statusCode = httpCode
+		} else if st := apiErr.GRPCStatus(); st != nil {
+			info.statusCode = rpccode.ToHTTP(uint32(st.Code()))
+		}
+		details := apiErr.Details()
+		if details.RequestInfo != nil {
+			info.requestID = details.RequestInfo.GetRequestId()
+		}
+		if details.RetryInfo != nil && details.RetryInfo.GetRetryDelay() != nil {
+			info.retryAfter = details.RetryInfo.GetRetryDelay().AsDuration()
+		}
+	}
+	var googleErr *googleapi.Error
+	if stderrors.As(err, &googleErr) {
+		ok = true
+		if info.statusCode == 0 {
+			info.statusCode = googleErr.Code
+		}
+		if info.code == "" && len(googleErr.Errors) > 0 {
+			info.code = googleErr.Errors[0].Reason
+		}
+	}
+	return info, ok
+}
+
+// inspectKubernetes extracts the information of an error of the Kubernetes API.
+func inspectKubernetes(err error) (info cloudError, ok bool) {
+	var statusErr apierrors.APIStatus
+	if !stderrors.As(err, &statusErr) {
+		return info, false
+	}
+	status := statusErr.Status()
+	info.statusCode = int(status.Code)
+	info.code = string(status.Reason)
+	if seconds, ok := apierrors.SuggestsClientDelay(err); ok {
+		info.retryAfter = time.Duration(seconds) * time.Second
+	}
+	if apierrors.IsServerTimeout(err) || apierrors.IsTimeout(err) || apierrors.IsTooManyRequests(err) || apierrors.IsConflict(err) {
+		retryable := true
+		info.retryable = &retryable
+	}
+	return info, true
+}
+
+/*
+StatusMapper is an errors.StatusMapper that maps the errors of cloud SDKs to status codes.
+It is registered by Register.
+*/
+func StatusMapper(err error) (statusCode int, ok bool) {
+	info, ok := inspect(err)
+	if !ok || info.statusCode < 100 || info.statusCode > 599 {
+		return 0, false
+	}
+	return info.statusCode, true
+}
+
+var registerOnce sync.Once
+
+// Register registers StatusMapper with the status mapper registry of the errors package,
+// so that the status codes of cloud errors are determined by New and Trace. It is safe to call more than once.
+func Register() {
+	registerOnce.Do(func() {
+		errors.RegisterStatusMapper(StatusMapper)
+	})
+}
+
+/*
+Trace traces the error, attaching the status code of the cloud error, its error code, the ID of the request and its retryability.
+Errors that are not recognized are traced as they are. The variadic arguments behave like those of errors.Trace.
+
+	_, err := clientset.CoreV1().Pods(ns).Create(ctx, pod, metav1.CreateOptions{})
+	if err != nil {
+		return clouderrors.Trace(err)
+	}
+*/
+func Trace(err error, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	info, ok := inspect(err)
+	if !ok {
+		return errors.Trace(err, args...)
+	}
+	var cloudArgs []any
+	if info.statusCode >= 100 && info.statusCode <= 599 {
+		cloudArgs = append(cloudArgs, info.statusCode)
+	}
+	if info.code != "" {
+		cloudArgs = append(cloudArgs, propCode, info.code)
+	}
+	if info.requestID != "" {
+		cloudArgs = append(cloudArgs, propRequestID, info.requestID)
+	}
+	if info.retryable != nil {
+		cloudArgs = append(cloudArgs, propRetryable, *info.retryable)
+	}
+	if info.retryAfter > 0 {
+		cloudArgs = append(cloudArgs, propRetryAfter, info.retryAfter)
+	}
+	return errors.Trace(err, append(cloudArgs, args...)...)
+}
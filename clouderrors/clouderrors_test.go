@@ -0,0 +1,121 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clouderrors
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"github.com/microbus-io/errors"
+	"google.golang.org/api/googleapi"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// awsResponseError mimics the response error of the AWS SDK, which wraps the API error.
+type awsResponseError struct {
+	statusCode int
+	requestID  string
+	err        error
+}
+
+func (e *awsResponseError) Error() string            { return e.err.Error() }
+func (e *awsResponseError) Unwrap() error            { return e.err }
+func (e *awsResponseError) HTTPStatusCode() int      { return e.statusCode }
+func (e *awsResponseError) ServiceRequestID() string { return e.requestID }
+
+func TestCloudErrors_AWS(t *testing.T) {
+	t.Parallel()
+
+	apiErr := &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded", Fault: smithy.FaultClient}
+	err := Trace(&awsResponseError{statusCode: 400, requestID: "req-123", err: apiErr})
+	if errors.StatusCode(err) != 400 {
+		t.Errorf("got status %d, want 400", errors.StatusCode(err))
+	}
+	props := errors.Convert(err).Properties
+	if props["code"] != "ThrottlingException" || props["requestID"] != "req-123" || props["retryable"] != true {
+		t.Errorf("unexpected properties %v", props)
+	}
+	if !errors.IsRetryable(err) {
+		t.Error("expected retryable")
+	}
+
+	// Without an HTTP response
+	err = Trace(&smithy.GenericAPIError{Code: "ValidationException", Fault: smithy.FaultClient})
+	if errors.StatusCode(err) != 400 {
+		t.Errorf("got status %d, want 400", errors.StatusCode(err))
+	}
+}
+
+func TestCloudErrors_Google(t *testing.T) {
+	t.Parallel()
+
+	err := Trace(&googleapi.Error{
+		Code:    http.StatusServiceUnavailable,
+		Message: "backend unavailable",
+		Errors:  []googleapi.ErrorItem{{Reason: "backendError"}},
+	})
+	if errors.StatusCode(err) != 503 {
+		t.Errorf("got status %d, want 503", errors.StatusCode(err))
+	}
+	if code := errors.Convert(err).Properties["code"]; code != "backendError" {
+		t.Errorf("got code %v, want backendError", code)
+	}
+	if !errors.IsRetryable(err) {
+		t.Error("expected retryable")
+	}
+}
+
+func TestCloudErrors_Kubernetes(t *testing.T) {
+	t.Parallel()
+
+	gr := schema.GroupResource{Resource: "pods"}
+	err := Trace(apierrors.NewNotFound(gr, "web-0"))
+	if errors.StatusCode(err) != 404 {
+		t.Errorf("got status %d, want 404", errors.StatusCode(err))
+	}
+	if code := errors.Convert(err).Properties["code"]; code != "NotFound" {
+		t.Errorf("got code %v, want NotFound", code)
+	}
+
+	err = Trace(apierrors.NewTooManyRequests("slow down", 7))
+	if errors.StatusCode(err) != 429 {
+		t.Errorf("got status %d, want 429", errors.StatusCode(err))
+	}
+	if d, ok := errors.RetryAfter(err); !ok || d != 7*time.Second {
+		t.Errorf("got retry after %v, want 7s", d)
+	}
+}
+
+func TestCloudErrors_Register(t *testing.T) {
+	t.Parallel()
+
+	Register()
+	Register()
+	err := errors.Trace(apierrors.NewConflict(schema.GroupResource{Resource: "pods"}, "web-0", errors.New("modified")))
+	if errors.StatusCode(err) != 409 {
+		t.Errorf("got status %d, want 409", errors.StatusCode(err))
+	}
+	if Trace(nil) != nil {
+		t.Error("expected nil")
+	}
+	if err := Trace(errors.New("oops", 418)); errors.StatusCode(err) != 418 {
+		t.Errorf("got status %d, want 418", errors.StatusCode(err))
+	}
+}
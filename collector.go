@@ -0,0 +1,122 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	"sync"
+)
+
+// CollectPolicy determines how a Collector handles the errors of its goroutines.
+type CollectPolicy int
+
+const (
+	// FailFast cancels the sibling goroutines upon the first error and returns only that error.
+	FailFast CollectPolicy = iota
+	// FailAtEnd lets all goroutines run to completion and joins all errors.
+	FailAtEnd
+)
+
+// String returns the name of the policy, as recorded in the "collectPolicy" property of the errors returned by Wait.
+func (p CollectPolicy) String() string {
+	switch p {
+	case FailFast:
+		return "failFast"
+	case FailAtEnd:
+		return "failAtEnd"
+	default:
+		return "unknown"
+	}
+}
+
+/*
+Collector runs functions in goroutines and collects their errors per its policy.
+Panics are recovered and returned as errors, and the stacks of the goroutines are joined to that of the caller of Wait by an async boundary.
+
+	c, ctx := errors.NewCollector(ctx, errors.FailAtEnd)
+	for _, url := range urls {
+		c.Go(func() error {
+			return fetch(ctx, url)
+		})
+	}
+	err := c.Wait()
+*/
+type Collector struct {
+	policy CollectPolicy
+	cancel context.CancelCauseFunc
+	wg     sync.WaitGroup
+	mux    sync.Mutex
+	errs   []error
+}
+
+// NewCollector creates a new collector with the policy.
+// The returned context is derived from ctx. It is canceled upon the first error if the policy is FailFast,
+// and in any case when Wait returns.
+func NewCollector(ctx context.Context, policy CollectPolicy) (*Collector, context.Context) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Collector{
+		policy: policy,
+		cancel: cancel,
+	}, ctx
+}
+
+// Go runs the function in a new goroutine.
+func (c *Collector) Go(f func() error) {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		err := CatchPanic(f)
+		if err == nil {
+			return
+		}
+		err = Handoff(err)
+		c.mux.Lock()
+		c.errs = append(c.errs, err)
+		first := len(c.errs) == 1
+		c.mux.Unlock()
+		if first && c.policy == FailFast {
+			c.cancel(err)
+		}
+	}()
+}
+
+// Wait waits for all goroutines to complete and returns the first error if the policy is FailFast,
+// or all errors joined if the policy is FailAtEnd, or nil if there are none.
+// The policy is recorded in the "collectPolicy" property of the returned error.
+func (c *Collector) Wait() error {
+	c.wg.Wait()
+	c.cancel(nil)
+	c.mux.Lock()
+	errs := make([]error, 0, len(c.errs))
+	for _, err := range c.errs {
+		errs = append(errs, Resume(err))
+	}
+	c.mux.Unlock()
+	if len(errs) == 0 {
+		return nil
+	}
+	var err error
+	if c.policy == FailFast || len(errs) == 1 {
+		err = errs[0]
+	} else {
+		err = Join(errs...)
+	}
+	return With(err, "collectPolicy", c.policy.String())
+}
@@ -0,0 +1,87 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestErrors_CollectorFailFast(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := NewCollector(context.Background(), FailFast)
+	c.Go(func() error {
+		return New("first", 400)
+	})
+	c.Go(func() error {
+		select {
+		case <-ctx.Done():
+			return New("canceled", context.Cause(ctx))
+		case <-time.After(5 * time.Second):
+			return nil
+		}
+	})
+	err := c.Wait()
+	assertError(t, err)
+	assertEqual(t, "first", err.Error())
+	assertEqual(t, 400, StatusCode(err))
+	assertEqual(t, "failFast", Convert(err).Properties["collectPolicy"])
+	assertError(t, ctx.Err())
+	assertContains(t, Convert(err).String(), asyncBoundary)
+}
+
+func TestErrors_CollectorFailAtEnd(t *testing.T) {
+	t.Parallel()
+
+	c, ctx := NewCollector(context.Background(), FailAtEnd)
+	c.Go(func() error {
+		return New("first")
+	})
+	c.Go(func() error {
+		time.Sleep(10 * time.Millisecond)
+		if ctx.Err() != nil {
+			return New("unexpectedly canceled")
+		}
+		return New("second")
+	})
+	c.Go(func() error {
+		return nil
+	})
+	c.Go(func() error {
+		panic("third")
+	})
+	err := c.Wait()
+	assertError(t, err)
+	assertContains(t, err.Error(), "first")
+	assertContains(t, err.Error(), "second")
+	assertContains(t, err.Error(), "third")
+	assertEqual(t, "failAtEnd", Convert(err).Properties["collectPolicy"])
+	assertError(t, ctx.Err())
+}
+
+func TestErrors_CollectorNoErrors(t *testing.T) {
+	t.Parallel()
+
+	c, _ := NewCollector(context.Background(), FailFast)
+	c.Go(func() error {
+		return nil
+	})
+	assertNil(t, c.Wait())
+	assertEqual(t, "failAtEnd", FailAtEnd.String())
+}
@@ -0,0 +1,272 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// settings are the package-level settings.
+// They are replaced as a whole when changed so that they can be read without locking.
+type settings struct {
+	stackFilePrefixTrim []string
+	stackFileAutoTrim   bool
+	stackFilters        []StackFilter
+	maxStackDepth       int
+	maxStackFrames      int
+	maxMessageLen       int
+	maxPropertyLen      int
+	maxProperties       int
+	maxSerializedFrames int
+	maxChainDepth       int
+	debugMode           bool
+	httpSerializer      HTTPSerializer
+	buildInfoEnrichment bool
+	pprofLabelCapture   bool
+	goroutineCapture    bool
+	jsonConfig          JSONConfig
+	mergeStrategy       MergeStrategy
+	pooling             bool
+	stackSampler        func() bool
+	disableStacks       bool
+	redactedProperties  []string
+	renderMode          RenderMode
+	maskPolicy          MaskPolicy
+	scrubRules          []*scrubRule
+}
+
+var (
+	currentSettings atomic.Pointer[settings]
+	settingsMux     sync.Mutex
+)
+
+func init() {
+	currentSettings.Store(&settings{
+		stackFilters: []StackFilter{SkipRuntime},
+	})
+	if hasEnvConfig() {
+		Configure(Config{})
+	}
+}
+
+// loadSettings returns the current package-level settings.
+// The returned settings must not be modified.
+func loadSettings() *settings {
+	return currentSettings.Load()
+}
+
+// updateSettings applies the modifier to a copy of the current settings and stores the copy.
+func updateSettings(modifier func(s *settings)) {
+	settingsMux.Lock()
+	defer settingsMux.Unlock()
+	s := *currentSettings.Load()
+	modifier(&s)
+	currentSettings.Store(&s)
+}
+
+/*
+SetStackFilePrefixTrim sets prefixes to trim from the file paths of stack frames captured from this point on.
+The longest matching prefix is trimmed.
+Calling it with no arguments disables prefix trimming.
+
+	errors.SetStackFilePrefixTrim("/home/builder/src/github.com/my-org/my-app/")
+*/
+func SetStackFilePrefixTrim(prefixes ...string) {
+	prefixes = sortPrefixes(prefixes)
+	updateSettings(func(s *settings) {
+		s.stackFilePrefixTrim = prefixes
+	})
+}
+
+// sortPrefixes returns a copy of the prefixes sorted from longest to shortest, so that the longest matching prefix is found first.
+func sortPrefixes(prefixes []string) []string {
+	prefixes = slices.Clone(prefixes)
+	slices.SortFunc(prefixes, func(a, b string) int {
+		return len(b) - len(a)
+	})
+	return prefixes
+}
+
+// SetStackFileAutoTrim enables or disables the automatic trimming of the file paths of stack frames captured from this point on.
+// When enabled, files of the main module are made relative to the module's root using the build info,
+// files in the module cache are made relative to the module cache,
+// and files of the standard library are made relative to the Go root.
+// Builds with -trimpath are supported.
+func SetStackFileAutoTrim(enabled bool) {
+	updateSettings(func(s *settings) {
+		s.stackFileAutoTrim = enabled
+	})
+}
+
+// SetDebugMode enables or disables the debug mode, which is intended for local development.
+// In debug mode, String and the %+v verb include the lines of source code surrounding each stack frame,
+// and error responses such as those written by WriteHTTP include the stack trace.
+func SetDebugMode(enabled bool) {
+	updateSettings(func(s *settings) {
+		s.debugMode = enabled
+	})
+}
+
+// Config is the package-level configuration.
+// Its zero value is the default configuration.
+type Config struct {
+	// DisableStacks disables the capture of stack locations altogether.
+	DisableStacks bool
+	// MaxStackDepth limits the number of frames captured by a full stack capture, such as by CatchPanic.
+	// Omitted frames are indicated by a "... N more" pseudo frame. Zero indicates no limit.
+	MaxStackDepth int
+	// MaxStackFrames limits the total number of frames an error accumulates across repeated calls to Trace.
+	// Omitted frames are indicated by a "... N more" pseudo frame. Zero indicates no limit.
+	MaxStackFrames int
+	// StackFilePrefixTrim are prefixes to trim from the file paths of stack frames, as set by SetStackFilePrefixTrim.
+	StackFilePrefixTrim []string
+	// StackFileAutoTrim enables the automatic trimming of the file paths of stack frames, as set by SetStackFileAutoTrim.
+	StackFileAutoTrim bool
+	// MaxMessageLen limits the length in bytes of the message of serialized errors.
+	// Truncation is indicated by an ellipsis. Zero indicates no limit.
+	MaxMessageLen int
+	// MaxPropertyLen limits the length in bytes of the value of each property of serialized errors, as measured in JSON.
+	// Longer values are replaced by their truncated JSON, followed by an ellipsis. Zero indicates no limit.
+	MaxPropertyLen int
+	// MaxProperties limits the number of properties of serialized errors. Properties are retained in order of their names
+	// and the count of dropped properties is indicated by the "!DROPPED" property. Zero indicates no limit.
+	MaxProperties int
+	// MaxSerializedFrames limits the number of stack frames of serialized errors.
+	// Omitted frames are indicated by a "... N more" pseudo frame. Zero indicates no limit.
+	MaxSerializedFrames int
+	// MaxChainDepth limits the number of layers of the wrap chain whose messages are concatenated by Error,
+	// e.g. "a: b: c: ... (+4 more)". Zero indicates no limit.
+	MaxChainDepth int
+	// RedactedProperties are the names of properties whose values are replaced by "!REDACTED" in serialized errors.
+	// The values of command line arguments and query arguments of the same names are also redacted by FromExec and FromRequest.
+	RedactedProperties []string
+	// DebugMode enables the debug mode, as set by SetDebugMode.
+	DebugMode bool
+}
+
+/*
+Configure sets the package-level configuration.
+The serialization limits and redaction are enforced by MarshalJSON, ToHeader and EncodeToMap,
+so that errors fit within the limits of message headers and log lines.
+
+Configure is a full reset of the settings covered by Config: fields left at their zero value
+restore the default, overriding any value set earlier by SetStackFilePrefixTrim, SetStackFileAutoTrim or SetDebugMode.
+Those setters change a single setting each and may be called after Configure to adjust it.
+Settings not covered by Config, such as the stack filters or the HTTP serializer, are left unchanged.
+
+Environment variables override the configuration, so that operators can tune it without code changes.
+They are also applied at startup. Invalid values are ignored.
+
+	ERRORS_STACKS=off
+	ERRORS_MAXSTACKDEPTH=32
+	ERRORS_MAXFRAMES=32
+	ERRORS_TRIM=/home/builder/src/,/go/pkg/mod/
+	ERRORS_AUTOTRIM=true
+	ERRORS_MAXMESSAGELEN=1024
+	ERRORS_MAXPROPERTYLEN=256
+	ERRORS_MAXPROPERTIES=32
+	ERRORS_MAXSERIALIZEDFRAMES=16
+	ERRORS_MAXCHAINDEPTH=5
+	ERRORS_REDACT=password,token
+	ERRORS_DEBUG=true
+*/
+func Configure(cfg Config) {
+	cfg = overrideFromEnv(cfg)
+	prefixes := sortPrefixes(cfg.StackFilePrefixTrim)
+	redacted := slices.Clone(cfg.RedactedProperties)
+	updateSettings(func(s *settings) {
+		s.disableStacks = cfg.DisableStacks
+		s.maxStackDepth = max(cfg.MaxStackDepth, 0)
+		s.maxStackFrames = max(cfg.MaxStackFrames, 0)
+		s.stackFilePrefixTrim = prefixes
+		s.stackFileAutoTrim = cfg.StackFileAutoTrim
+		s.maxMessageLen = max(cfg.MaxMessageLen, 0)
+		s.maxPropertyLen = max(cfg.MaxPropertyLen, 0)
+		s.maxProperties = max(cfg.MaxProperties, 0)
+		s.maxSerializedFrames = max(cfg.MaxSerializedFrames, 0)
+		s.maxChainDepth = max(cfg.MaxChainDepth, 0)
+		s.redactedProperties = redacted
+		s.debugMode = cfg.DebugMode
+	})
+}
+
+// envConfigPrefix is the prefix of the environment variables that override the configuration.
+const envConfigPrefix = "ERRORS_"
+
+// hasEnvConfig indicates if any environment variable overrides the configuration.
+func hasEnvConfig() bool {
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, envConfigPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// overrideFromEnv overrides the configuration with the values of the environment variables, if set and valid.
+func overrideFromEnv(cfg Config) Config {
+	envBool := func(name string, target *bool) {
+		v, ok := os.LookupEnv(envConfigPrefix + name)
+		if !ok {
+			return
+		}
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "1", "true", "on", "yes":
+			*target = true
+		case "0", "false", "off", "no":
+			*target = false
+		}
+	}
+	envInt := func(name string, target *int) {
+		if v, ok := os.LookupEnv(envConfigPrefix + name); ok {
+			if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil && n >= 0 {
+				*target = n
+			}
+		}
+	}
+	envList := func(name string, target *[]string) {
+		if v, ok := os.LookupEnv(envConfigPrefix + name); ok {
+			var list []string
+			for item := range strings.SplitSeq(v, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					list = append(list, item)
+				}
+			}
+			*target = list
+		}
+	}
+	stacks := !cfg.DisableStacks
+	envBool("STACKS", &stacks)
+	cfg.DisableStacks = !stacks
+	envInt("MAXSTACKDEPTH", &cfg.MaxStackDepth)
+	envInt("MAXFRAMES", &cfg.MaxStackFrames)
+	envList("TRIM", &cfg.StackFilePrefixTrim)
+	envBool("AUTOTRIM", &cfg.StackFileAutoTrim)
+	envInt("MAXMESSAGELEN", &cfg.MaxMessageLen)
+	envInt("MAXPROPERTYLEN", &cfg.MaxPropertyLen)
+	envInt("MAXPROPERTIES", &cfg.MaxProperties)
+	envInt("MAXSERIALIZEDFRAMES", &cfg.MaxSerializedFrames)
+	envInt("MAXCHAINDEPTH", &cfg.MaxChainDepth)
+	envList("REDACT", &cfg.RedactedProperties)
+	envBool("DEBUG", &cfg.DebugMode)
+	return cfg
+}
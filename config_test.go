@@ -0,0 +1,248 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestErrors_MaxStackDepth(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	Configure(Config{MaxStackDepth: 3})
+	defer Configure(Config{})
+
+	err := CatchPanic(func() error {
+		var f func(n int) error
+		f = func(n int) error {
+			if n == 0 {
+				panic("deep")
+			}
+			return f(n - 1)
+		}
+		return f(10)
+	})
+	stack := Convert(err).Stack
+	assertEqual(t, 4, len(stack))
+	assertContains(t, stack[3].Function, "... ")
+	assertContains(t, stack[3].Function, " more")
+	assertEqual(t, "- "+stack[3].Function, stack[3].String())
+}
+
+func TestErrors_MaxStackFrames(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	Configure(Config{MaxStackFrames: 3})
+	defer Configure(Config{})
+
+	err := New("oops")
+	err = Trace(err)
+	err = Trace(err)
+	err = Trace(err)
+	err = Trace(err)
+	err = Trace(err)
+	stack := Convert(err).Stack
+	assertEqual(t, 4, len(stack))
+	assertEqual(t, "... 3 more", stack[3].Function)
+	assertTrue(t, stack[3].isElision())
+	assertEqual(t, 3, stack[3].elided())
+}
+
+func TestErrors_StackDepth(t *testing.T) {
+	t.Parallel()
+
+	err := New("oops", StackDepth(1))
+	stack := Convert(err).Stack
+	assertEqual(t, 2, len(stack))
+	assertEqual(t, "errors.TestErrors_StackDepth", stack[0].Function)
+	assertEqual(t, "... 1 more", stack[1].Function)
+
+	err = New("oops", StackDepth(8))
+	stack = Convert(err).Stack
+	assertEqual(t, 2, len(stack))
+	assertEqual(t, "errors.TestErrors_StackDepth", stack[0].Function)
+	assertEqual(t, "testing.tRunner", stack[1].Function)
+	assertEqual(t, 0, len(Convert(err).Properties))
+}
+
+func TestErrors_SerializationLimits(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	Configure(Config{
+		MaxMessageLen:       10,
+		MaxPropertyLen:      8,
+		MaxProperties:       2,
+		MaxSerializedFrames: 2,
+	})
+	defer Configure(Config{})
+
+	err := New("a message that is too long", "a", "short", "b", strings.Repeat("x", 20), "c", "dropped", StackDepth(16))
+	err = Trace(err)
+	tracedErr := Convert(err)
+	stackLen := len(tracedErr.Stack)
+	assertTrue(t, stackLen > 2)
+
+	b, jsonErr := json.Marshal(err)
+	assertNil(t, jsonErr)
+	var m map[string]any
+	assertNil(t, json.Unmarshal(b, &m))
+	assertEqual(t, "a messa...", m["error"])
+	assertEqual(t, "short", m["a"])
+	assertEqual(t, "xxxxx...", m["b"])
+	_, ok := m["c"]
+	assertTrue(t, !ok)
+	assertEqual(t, 1.0, m["!DROPPED"])
+	stack := m["stack"].([]any)
+	assertEqual(t, 3, len(stack))
+	assertEqual(t, fmt.Sprintf("... %d more", stackLen-2), stack[2].(map[string]any)["func"])
+
+	// The error itself is not modified
+	assertEqual(t, "a message that is too long", err.Error())
+	assertEqual(t, 3, len(tracedErr.Properties))
+	assertEqual(t, stackLen, len(tracedErr.Stack))
+
+	// Headers
+	h := http.Header{}
+	ToHeader(h, err)
+	assertEqual(t, "a messa...", h.Get(HeaderErrorMessage))
+
+	// Map
+	encoded := EncodeToMap(err)
+	assertEqual(t, "a messa...", encoded["error"])
+	_, ok = encoded["prop.c"]
+	assertTrue(t, !ok)
+	assertEqual(t, `"xxxxx..."`, encoded["prop.b"])
+
+	// Non-string property values are truncated as JSON
+	b, _ = json.Marshal(New("oops", "list", []int{1, 2, 3, 4, 5}))
+	m = nil
+	assertNil(t, json.Unmarshal(b, &m))
+	assertEqual(t, "[1,2,...", m["list"])
+}
+
+func TestErrors_MaxChainDepth(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	Configure(Config{MaxChainDepth: 3})
+	defer Configure(Config{})
+
+	err := New("f")
+	for _, layer := range []string{"e", "d", "c", "b"} {
+		err = fmt.Errorf("%s: %w", layer, err)
+	}
+	err = New("a", err)
+	assertEqual(t, "a: b: c: ... (+3 more)", err.Error())
+
+	// Traced errors and fmt.Errorf along the chain
+	err = fmt.Errorf("x: %w", err)
+	err = New("failed to %s", "w", err)
+	assertEqual(t, "failed to w: x: a: ... (+5 more)", err.Error())
+
+	// Within the limit
+	err = New("a", New("b", New("c")))
+	assertEqual(t, "a: b: c", err.Error())
+
+	// Layers that do not append the wrapped message end the chain
+	err = New("a", New("b", fmt.Errorf("c (%w)", fmt.Errorf("d: %w", stderrors.New("e")))))
+	assertEqual(t, "a: b: c (d: e)", err.Error())
+	err = New("z", New("y", err))
+	assertEqual(t, "z: y: a: ... (+2 more)", err.Error())
+}
+
+func TestErrors_Configure(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	Configure(Config{
+		StackFilePrefixTrim: []string{"/short/", "/short/longer/"},
+		StackFileAutoTrim:   true,
+		DebugMode:           true,
+		RedactedProperties:  []string{"password"},
+	})
+	defer Configure(Config{})
+
+	s := loadSettings()
+	assertEqual(t, []string{"/short/longer/", "/short/"}, s.stackFilePrefixTrim)
+	assertTrue(t, s.stackFileAutoTrim)
+	assertTrue(t, s.debugMode)
+
+	err := New("login failed", "user", "alice", "password", "hunter2")
+	data, jsonErr := json.Marshal(err)
+	assertNil(t, jsonErr)
+	assertContains(t, string(data), `"password":"!REDACTED"`)
+	assertContains(t, string(data), `"user":"alice"`)
+	assertEqual(t, "hunter2", Convert(err).Properties["password"])
+
+	Configure(Config{DisableStacks: true})
+	assertEqual(t, 0, len(Convert(New("oops")).Stack))
+	assertEqual(t, 0, len(Convert(Trace(New("oops"))).Stack))
+	err = CatchPanic(func() error {
+		panic("oops")
+	})
+	assertEqual(t, 0, len(Convert(err).Stack))
+	assertTrue(t, !loadSettings().debugMode)
+}
+
+func TestErrors_ConfigureResets(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	defer Configure(Config{})
+
+	// Configure resets the values set by the individual setters
+	SetDebugMode(true)
+	SetStackFilePrefixTrim("/short/")
+	SetStackFileAutoTrim(true)
+	Configure(Config{MaxMessageLen: 100})
+	s := loadSettings()
+	assertTrue(t, !s.debugMode)
+	assertEqual(t, 0, len(s.stackFilePrefixTrim))
+	assertTrue(t, !s.stackFileAutoTrim)
+	assertEqual(t, 100, s.maxMessageLen)
+
+	// The setters adjust a single setting after Configure
+	SetDebugMode(true)
+	s = loadSettings()
+	assertTrue(t, s.debugMode)
+	assertEqual(t, 100, s.maxMessageLen)
+}
+
+func TestErrors_ConfigureEnv(t *testing.T) {
+	// Not parallel because it modifies the package settings and environment
+	t.Cleanup(func() {
+		Configure(Config{})
+	})
+	t.Setenv("ERRORS_STACKS", "off")
+	t.Setenv("ERRORS_MAXFRAMES", "32")
+	t.Setenv("ERRORS_MAXSTACKDEPTH", "invalid")
+	t.Setenv("ERRORS_TRIM", " /a/ , /a/b/ ,")
+	t.Setenv("ERRORS_REDACT", "token")
+	t.Setenv("ERRORS_DEBUG", "true")
+	assertTrue(t, hasEnvConfig())
+
+	// Environment variables override the configuration
+	Configure(Config{MaxStackFrames: 8, MaxStackDepth: 16, MaxMessageLen: 100})
+	s := loadSettings()
+	assertTrue(t, s.disableStacks)
+	assertEqual(t, 32, s.maxStackFrames)
+	assertEqual(t, 16, s.maxStackDepth)
+	assertEqual(t, 100, s.maxMessageLen)
+	assertEqual(t, []string{"/a/b/", "/a/"}, s.stackFilePrefixTrim)
+	assertEqual(t, []string{"token"}, s.redactedProperties)
+	assertTrue(t, s.debugMode)
+
+	t.Setenv("ERRORS_STACKS", "on")
+	Configure(Config{DisableStacks: true})
+	assertTrue(t, !loadSettings().disableStacks)
+}
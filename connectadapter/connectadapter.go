@@ -0,0 +1,113 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package connectadapter converts traced errors to and from connect-go errors.
+
+	if err != nil {
+		return nil, connectadapter.ToConnectError(err)
+	}
+*/
+package connectadapter
+
+import (
+	"encoding/json"
+	stderrors "errors"
+
+	"connectrpc.com/connect"
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/errors/internal/rpccode"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// structDetailType is the fully-qualified name of the detail that carries the traced error.
+const structDetailType = "google.protobuf.Struct"
+
+// ToConnectError converts the error to a connect error.
+// The status code is mapped to a connect code, and the properties, stack and trace ID of the error
+// are carried in a google.protobuf.Struct error detail in the same shape as the JSON representation of the error.
+// Connect errors are returned as-is.
+func ToConnectError(err error) *connect.Error {
+	if err == nil {
+		return nil
+	}
+	var connectErr *connect.Error
+	if stderrors.As(err, &connectErr) {
+		return connectErr
+	}
+	tracedErr := errors.Convert(err)
+	connectErr = connect.NewError(connect.Code(rpccode.FromHTTP(tracedErr.StatusCode)), tracedErr)
+	b, marshalErr := tracedErr.MarshalJSON()
+	if marshalErr != nil {
+		return connectErr
+	}
+	var m map[string]any
+	if json.Unmarshal(b, &m) != nil {
+		return connectErr
+	}
+	s, structErr := structpb.NewStruct(m)
+	if structErr != nil {
+		return connectErr
+	}
+	detail, detailErr := connect.NewErrorDetail(s)
+	if detailErr != nil {
+		return connectErr
+	}
+	connectErr.AddDetail(detail)
+	return connectErr
+}
+
+// FromConnectError converts a connect error to a traced error.
+// The properties, stack and trace ID of the error are restored from the error detail added by ToConnectError, if present.
+// Otherwise, the connect code is mapped to a status code.
+// Connect errors are attributed to the upstream origin.
+// Errors that are not connect errors are converted as-is.
+func FromConnectError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var connectErr *connect.Error
+	if !stderrors.As(err, &connectErr) {
+		return errors.Convert(err)
+	}
+	for _, detail := range connectErr.Details() {
+		if detail.Type() != structDetailType {
+			continue
+		}
+		value, valueErr := detail.Value()
+		if valueErr != nil {
+			continue
+		}
+		s, ok := value.(*structpb.Struct)
+		if !ok {
+			continue
+		}
+		b, marshalErr := json.Marshal(s.AsMap())
+		if marshalErr != nil {
+			continue
+		}
+		var tracedErr errors.TracedError
+		if tracedErr.UnmarshalJSON(b) == nil {
+			tracedErr.Origin = errors.OriginUpstream
+			return &tracedErr
+		}
+	}
+	return &errors.TracedError{
+		Err:        stderrors.New(connectErr.Message()),
+		StatusCode: rpccode.ToHTTP(uint32(connectErr.Code())),
+		Origin:     errors.OriginUpstream,
+	}
+}
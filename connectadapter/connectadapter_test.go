@@ -0,0 +1,73 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectadapter
+
+import (
+	stderrors "errors"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/microbus-io/errors"
+)
+
+func TestConnectAdapter_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	err := errors.New("user not found", 404, "0123456789abcdef0123456789abcdef", "userID", "123")
+	connectErr := ToConnectError(err)
+	if connectErr.Code() != connect.CodeNotFound {
+		t.Errorf("expected NotFound, got %v", connectErr.Code())
+	}
+	if connectErr.Message() != "user not found" {
+		t.Errorf("expected message 'user not found', got %s", connectErr.Message())
+	}
+	if !stderrors.Is(connectErr, err) {
+		t.Errorf("expected the connect error to wrap the original error")
+	}
+
+	tracedErr := errors.Convert(FromConnectError(connectErr))
+	if tracedErr.Error() != "user not found" {
+		t.Errorf("expected message 'user not found', got %s", tracedErr.Error())
+	}
+	if tracedErr.StatusCode != 404 {
+		t.Errorf("expected status 404, got %d", tracedErr.StatusCode)
+	}
+	if tracedErr.Trace != "0123456789abcdef0123456789abcdef" {
+		t.Errorf("unexpected trace %s", tracedErr.Trace)
+	}
+	if tracedErr.Properties["userID"] != "123" {
+		t.Errorf("unexpected properties %v", tracedErr.Properties)
+	}
+	if len(tracedErr.Stack) != len(errors.Convert(err).Stack) {
+		t.Errorf("expected the stack to be restored")
+	}
+}
+
+func TestConnectAdapter_FromPlainConnectError(t *testing.T) {
+	t.Parallel()
+
+	err := FromConnectError(connect.NewError(connect.CodeUnavailable, stderrors.New("try later")))
+	if errors.StatusCode(err) != 503 {
+		t.Errorf("expected status 503, got %d", errors.StatusCode(err))
+	}
+	if err.Error() != "try later" {
+		t.Errorf("unexpected message %s", err.Error())
+	}
+	if FromConnectError(nil) != nil || ToConnectError(nil) != nil {
+		t.Errorf("expected nil")
+	}
+}
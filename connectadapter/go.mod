@@ -0,0 +1,11 @@
+module github.com/microbus-io/errors/connectadapter
+
+go 1.24.3
+
+require (
+	connectrpc.com/connect v1.16.2
+	github.com/microbus-io/errors v0.0.0-00010101000000-000000000000
+	google.golang.org/protobuf v1.34.2
+)
+
+replace github.com/microbus-io/errors => ../
@@ -0,0 +1,218 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package dberrors maps the errors of database drivers to status codes and properties.
+The errors of lib/pq, pgx and the MySQL driver are recognized, as well as sql.ErrNoRows.
+
+  - A unique violation maps to 409
+  - A foreign key violation maps to 409
+  - A serialization failure or a deadlock maps to 409 and is retryable
+  - No rows maps to 404
+
+The SQLSTATE of the error and the name of the violated constraint are attached in the "sqlState" and "constraint" properties.
+
+	func main() {
+		dberrors.Register()
+		...
+	}
+
+	row := db.QueryRowContext(ctx, "SELECT ...")
+	if err := row.Scan(&user); err != nil {
+		return dberrors.Trace(err)
+	}
+*/
+package dberrors
+
+import (
+	"database/sql"
+	stderrors "errors"
+	"regexp"
+	"sync"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+	"github.com/microbus-io/errors"
+)
+
+// Well-known SQLSTATE codes
+const (
+	UniqueViolation      = "23505"
+	ForeignKeyViolation  = "23503"
+	SerializationFailure = "40001"
+	DeadlockDetected     = "40P01"
+)
+
+// Properties attached to database errors
+const (
+	propSQLState   = "sqlState"
+	propConstraint = "constraint"
+	propRetryable  = "retryable"
+)
+
+// MySQL error numbers
+const (
+	mysqlDupEntry         = 1062
+	mysqlRowIsReferenced  = 1451
+	mysqlNoReferencedRow  = 1452
+	mysqlLockDeadlock     = 1213
+	mysqlDupEntryWithKey  = 1586
+	mysqlRowIsReferenced2 = 1217
+	mysqlNoReferencedRow2 = 1216
+)
+
+// dbError is the information extracted from the error of a database driver.
+type dbError struct {
+	sqlState   string
+	constraint string
+	noRows     bool
+}
+
+// statusCode returns the status code of the database error, or 0 if it is not recognized.
+func (e dbError) statusCode() int {
+	switch {
+	case e.noRows:
+		return 404
+	case e.sqlState == UniqueViolation, e.sqlState == ForeignKeyViolation:
+		return 409
+	case e.retryable():
+		return 409
+	default:
+		return 0
+	}
+}
+
+// retryable indicates if the transaction that failed with the database error can be retried.
+func (e dbError) retryable() bool {
+	return e.sqlState == SerializationFailure || e.sqlState == DeadlockDetected
+}
+
+var (
+	mysqlDupKeyRegexp = regexp.MustCompile(`for key '([^']+)'`)
+	mysqlFKRegexp     = regexp.MustCompile("CONSTRAINT `([^`]+)`")
+)
+
+// inspect extracts the information of the error of a database driver, if the error is recognized.
+func inspect(err error) (info dbError, ok bool) {
+	if err == nil {
+		return info, false
+	}
+	if stderrors.Is(err, sql.ErrNoRows) || stderrors.Is(err, pgx.ErrNoRows) {
+		return dbError{noRows: true}, true
+	}
+	var pgErr *pgconn.PgError
+	if stderrors.As(err, &pgErr) {
+		return dbError{sqlState: pgErr.Code, constraint: pgErr.ConstraintName}, true
+	}
+	var pqErr *pq.Error
+	if stderrors.As(err, &pqErr) {
+		return dbError{sqlState: string(pqErr.Code), constraint: pqErr.Constraint}, true
+	}
+	var mysqlErr *mysql.MySQLError
+	if stderrors.As(err, &mysqlErr) {
+		if mysqlErr.SQLState != [5]byte{} {
+			info.sqlState = string(mysqlErr.SQLState[:])
+		}
+		switch mysqlErr.Number {
+		case mysqlDupEntry, mysqlDupEntryWithKey:
+			info.sqlState = UniqueViolation
+			if m := mysqlDupKeyRegexp.FindStringSubmatch(mysqlErr.Message); m != nil {
+				info.constraint = m[1]
+			}
+		case mysqlRowIsReferenced, mysqlNoReferencedRow, mysqlRowIsReferenced2, mysqlNoReferencedRow2:
+			info.sqlState = ForeignKeyViolation
+			if m := mysqlFKRegexp.FindStringSubmatch(mysqlErr.Message); m != nil {
+				info.constraint = m[1]
+			}
+		case mysqlLockDeadlock:
+			info.sqlState = SerializationFailure
+		}
+		return info, true
+	}
+	return info, false
+}
+
+/*
+StatusMapper is an errors.StatusMapper that maps the errors of database drivers to status codes.
+It is registered by Register.
+*/
+func StatusMapper(err error) (statusCode int, ok bool) {
+	info, ok := inspect(err)
+	if !ok {
+		return 0, false
+	}
+	statusCode = info.statusCode()
+	return statusCode, statusCode != 0
+}
+
+var registerOnce sync.Once
+
+// Register registers StatusMapper with the status mapper registry of the errors package,
+// so that the status codes of database errors are determined by New and Trace. It is safe to call more than once.
+func Register() {
+	registerOnce.Do(func() {
+		errors.RegisterStatusMapper(StatusMapper)
+	})
+}
+
+/*
+Trace traces the error, attaching the status code of the database error, its SQLSTATE and the name of the violated constraint,
+and marking serialization failures and deadlocks as retryable.
+Errors that are not recognized are traced as they are. The variadic arguments behave like those of errors.Trace.
+
+	_, err := db.ExecContext(ctx, "INSERT INTO users ...")
+	if err != nil {
+		return dberrors.Trace(err)
+	}
+*/
+func Trace(err error, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	info, ok := inspect(err)
+	if !ok {
+		return errors.Trace(err, args...)
+	}
+	var dbArgs []any
+	if statusCode := info.statusCode(); statusCode != 0 {
+		dbArgs = append(dbArgs, statusCode)
+	}
+	if info.sqlState != "" {
+		dbArgs = append(dbArgs, propSQLState, info.sqlState)
+	}
+	if info.constraint != "" {
+		dbArgs = append(dbArgs, propConstraint, info.constraint)
+	}
+	if info.retryable() {
+		dbArgs = append(dbArgs, propRetryable, true)
+	}
+	return errors.Trace(err, append(dbArgs, args...)...)
+}
+
+// SQLState returns the SQLSTATE of the database error, or an empty string if it is not a recognized database error.
+// The "sqlState" property of traced errors is respected, so that the SQLSTATE survives serialization.
+func SQLState(err error) string {
+	if err == nil {
+		return ""
+	}
+	if sqlState, ok := errors.Convert(err).Properties[propSQLState].(string); ok {
+		return sqlState
+	}
+	info, _ := inspect(err)
+	return info.sqlState
+}
@@ -0,0 +1,84 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dberrors
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+	"github.com/microbus-io/errors"
+)
+
+func TestDBErrors_Trace(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		err        error
+		statusCode int
+		sqlState   string
+		constraint string
+		retryable  bool
+	}{
+		{&pgconn.PgError{Code: UniqueViolation, ConstraintName: "users_email_key"}, 409, UniqueViolation, "users_email_key", false},
+		{&pgconn.PgError{Code: ForeignKeyViolation, ConstraintName: "orders_user_fk"}, 409, ForeignKeyViolation, "orders_user_fk", false},
+		{&pgconn.PgError{Code: SerializationFailure}, 409, SerializationFailure, "", true},
+		{&pq.Error{Code: UniqueViolation, Constraint: "users_email_key"}, 409, UniqueViolation, "users_email_key", false},
+		{&pq.Error{Code: DeadlockDetected}, 409, DeadlockDetected, "", true},
+		{&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'a@b.c' for key 'users.email'"}, 409, UniqueViolation, "users.email", false},
+		{&mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row: a foreign key constraint fails (`db`.`orders`, CONSTRAINT `orders_user_fk` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`))"}, 409, ForeignKeyViolation, "orders_user_fk", false},
+		{&mysql.MySQLError{Number: 1213}, 409, SerializationFailure, "", true},
+		{fmt.Errorf("query failed: %w", sql.ErrNoRows), 404, "", "", false},
+		{&pgconn.PgError{Code: "42601"}, 500, "42601", "", false},
+	}
+	for _, tc := range testCases {
+		err := Trace(tc.err)
+		if errors.StatusCode(err) != tc.statusCode {
+			t.Errorf("%v: got status %d, want %d", tc.err, errors.StatusCode(err), tc.statusCode)
+		}
+		if SQLState(err) != tc.sqlState {
+			t.Errorf("%v: got SQLSTATE %q, want %q", tc.err, SQLState(err), tc.sqlState)
+		}
+		props := errors.Convert(err).Properties
+		if constraint, _ := props["constraint"].(string); constraint != tc.constraint {
+			t.Errorf("%v: got constraint %q, want %q", tc.err, constraint, tc.constraint)
+		}
+		if errors.IsRetryable(err) != tc.retryable {
+			t.Errorf("%v: got retryable %v, want %v", tc.err, errors.IsRetryable(err), tc.retryable)
+		}
+		if !errors.Is(err, tc.err) {
+			t.Errorf("%v: original error not wrapped", tc.err)
+		}
+	}
+}
+
+func TestDBErrors_Register(t *testing.T) {
+	t.Parallel()
+
+	Register()
+	Register()
+	err := errors.Trace(&pq.Error{Code: UniqueViolation})
+	if errors.StatusCode(err) != 409 {
+		t.Errorf("got status %d, want 409", errors.StatusCode(err))
+	}
+	if errors.Trace(nil) != nil || Trace(nil) != nil {
+		t.Error("expected nil")
+	}
+}
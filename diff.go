@@ -0,0 +1,197 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// CompareOption customizes the comparison of Equal and Diff.
+type CompareOption func(opts *compareOptions)
+
+type compareOptions struct {
+	stack bool
+}
+
+// CompareStacks includes or omits the stack traces in the comparison. Stack traces are omitted by default.
+func CompareStacks(enabled bool) CompareOption {
+	return func(opts *compareOptions) {
+		opts.stack = enabled
+	}
+}
+
+/*
+Equal indicates if two errors have the same message, status code, trace ID, properties and suppressed errors.
+Stack traces are compared only if requested.
+Property values are considered equal if they are deeply equal or if their JSON representations are equal,
+so that an error is equal to itself after a round trip through JSON.
+
+	if !errors.Equal(got, want) {
+		t.Error(errors.Diff(got, want))
+	}
+*/
+func Equal(a, b error, opts ...CompareOption) bool {
+	return Diff(a, b, opts...) == ""
+}
+
+/*
+Diff returns a readable description of the differences between two errors, one per line, or an empty string if they are equal per Equal.
+
+	message: "not found" != "forbidden"
+	statusCode: 404 != 403
+	properties.id: "123" != <missing>
+*/
+func Diff(a, b error, opts ...CompareOption) string {
+	var options compareOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	var diff strings.Builder
+	diffErrors(&diff, "", a, b, &options)
+	return strings.TrimSuffix(diff.String(), "\n")
+}
+
+// diffErrors writes the differences between the two errors to the builder, prefixing the name of each field.
+func diffErrors(diff *strings.Builder, prefix string, a, b error, options *compareOptions) {
+	if a == nil || b == nil {
+		if a != b && prefix == "" {
+			fmt.Fprintf(diff, "%s != %s\n", diffValue(a), diffValue(b))
+		} else if a != b {
+			fmt.Fprintf(diff, "%s: %s != %s\n", strings.TrimSuffix(prefix, "."), diffValue(a), diffValue(b))
+		}
+		return
+	}
+	ea, eb := Convert(a), Convert(b)
+	diffField(diff, prefix+"message", ea.Error(), eb.Error())
+	diffField(diff, prefix+"statusCode", ea.StatusCode, eb.StatusCode)
+	diffField(diff, prefix+"trace", ea.Trace, eb.Trace)
+	diffField(diff, prefix+"span", ea.Span, eb.Span)
+	diffField(diff, prefix+"origin", ea.Origin.String(), eb.Origin.String())
+
+	pa := flatDiffProperties(ea.Properties)
+	pb := flatDiffProperties(eb.Properties)
+	keys := maps.Clone(pa)
+	maps.Copy(keys, pb)
+	for _, k := range slices.Sorted(maps.Keys(keys)) {
+		va, okA := pa[k]
+		vb, okB := pb[k]
+		switch {
+		case !okA:
+			fmt.Fprintf(diff, "%sproperties.%s: <missing> != %s\n", prefix, k, diffValue(vb))
+		case !okB:
+			fmt.Fprintf(diff, "%sproperties.%s: %s != <missing>\n", prefix, k, diffValue(va))
+		default:
+			diffField(diff, prefix+"properties."+k, va, vb)
+		}
+	}
+
+	if options.stack {
+		for i := range max(len(ea.Stack), len(eb.Stack)) {
+			var fa, fb any = "<missing>", "<missing>"
+			if i < len(ea.Stack) {
+				fa = diffFrame(ea.Stack[i])
+			}
+			if i < len(eb.Stack) {
+				fb = diffFrame(eb.Stack[i])
+			}
+			if fa != fb {
+				fmt.Fprintf(diff, "%sstack[%d]: %v != %v\n", prefix, i, fa, fb)
+			}
+		}
+	}
+
+	for i := range max(len(ea.Suppressed), len(eb.Suppressed)) {
+		var sa, sb error
+		if i < len(ea.Suppressed) {
+			sa = ea.Suppressed[i]
+		}
+		if i < len(eb.Suppressed) {
+			sb = eb.Suppressed[i]
+		}
+		diffErrors(diff, fmt.Sprintf("%ssuppressed[%d].", prefix, i), sa, sb, options)
+	}
+}
+
+// flatDiffProperties flattens the properties to dotted keys.
+// Unlike flatProperties, it also flattens maps, which is what groups become after a round trip through JSON.
+func flatDiffProperties(props map[string]any) map[string]any {
+	flat := map[string]any{}
+	for k, v := range props {
+		var group map[string]any
+		switch v := v.(type) {
+		case GroupedProperties:
+			group = v
+		case map[string]any:
+			group = v
+		default:
+			flat[k] = v
+			continue
+		}
+		for nk, nv := range flatDiffProperties(group) {
+			flat[k+"."+nk] = nv
+		}
+	}
+	return flat
+}
+
+// diffField writes a line to the builder if the two values are not equal.
+func diffField(diff *strings.Builder, name string, a, b any) {
+	if !equalValues(a, b) {
+		fmt.Fprintf(diff, "%s: %s != %s\n", name, diffValue(a), diffValue(b))
+	}
+}
+
+// equalValues indicates if the two values are deeply equal or if their JSON representations are equal.
+func equalValues(a, b any) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(ja, jb)
+}
+
+// diffValue returns the representation of a value in a diff.
+func diffValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "<nil>"
+	case string:
+		return fmt.Sprintf("%q", v)
+	case error:
+		return fmt.Sprintf("%q", v.Error())
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// diffFrame returns the representation of a stack frame in a diff.
+func diffFrame(frame *StackFrame) string {
+	if frame.isPseudo() {
+		return frame.Function
+	}
+	if frame.Repeat > 1 {
+		return fmt.Sprintf("%s (x%d) %s:%d", frame.Function, frame.Repeat, frame.File, frame.Line)
+	}
+	return fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line)
+}
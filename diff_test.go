@@ -0,0 +1,78 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestErrors_Equal(t *testing.T) {
+	t.Parallel()
+
+	newErr := func() error {
+		return New("not found", 404, "id", 123, Group("db", "table", "users"))
+	}
+	a := newErr()
+	b := newErr()
+	assertTrue(t, Equal(a, b))
+	assertEqual(t, "", Diff(a, b))
+	assertTrue(t, Equal(nil, nil))
+	assertTrue(t, !Equal(a, nil))
+
+	// The stacks differ in line numbers
+	c := New("oops")
+	d := New("oops")
+	assertTrue(t, Equal(c, d))
+	assertTrue(t, !Equal(c, d, CompareStacks(true)))
+	assertTrue(t, Equal(a, b, CompareStacks(true)))
+
+	// Round trip through JSON
+	data, err := json.Marshal(a)
+	assertNil(t, err)
+	var unmarshaled TracedError
+	err = json.Unmarshal(data, &unmarshaled)
+	assertNil(t, err)
+	assertEqual(t, "", Diff(a, &unmarshaled, CompareStacks(true)))
+}
+
+func TestErrors_Diff(t *testing.T) {
+	t.Parallel()
+
+	a := New("not found", 404, "id", "123", "only", "a")
+	b := New("forbidden", 403, "id", "456", "extra", true)
+	assertEqual(t, `message: "not found" != "forbidden"
+statusCode: 404 != 403
+properties.extra: <missing> != true
+properties.id: "123" != "456"
+properties.only: "a" != <missing>`, Diff(a, b))
+
+	assertEqual(t, `<nil> != "oops"`, Diff(nil, New("oops")))
+
+	a = AddSuppressed(New("oops"), New("rollback failed", 409))
+	b = New("oops")
+	assertEqual(t, `suppressed[0]: <nil> != "rollback failed"`, Diff(b, a))
+
+	a = AddSuppressed(New("oops"), New("rollback failed", 409))
+	b = AddSuppressed(New("oops"), New("rollback failed", 503))
+	assertEqual(t, `suppressed[0].statusCode: 409 != 503`, Diff(a, b))
+
+	a = New("oops")
+	b = Trace(a)
+	assertEqual(t, "", Diff(a, b))
+	assertContains(t, Diff(a, b, CompareStacks(true)), "stack[1]: <missing> != errors.TestErrors_Diff ")
+}
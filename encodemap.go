@@ -0,0 +1,172 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// mapBudget is the maximum total size of the keys and values of the map produced by EncodeToMap
+	mapBudget = 8 * 1024
+	// mapMaxMessageLen is the maximum length of the message in the map produced by EncodeToMap
+	mapMaxMessageLen = 1024
+)
+
+/*
+EncodeToMap flattens the error into a map of strings, suitable for the headers of a message bus such as NATS.
+The map is deterministic and its total size is limited to 8KB.
+
+The message is truncated to 1KB if necessary and is encoded along with the status code, trace ID, span ID and origin in the
+keys "error", "statusCode", "trace", "span" and "origin". Properties are encoded next, in order of their names, as JSON values in keys
+"prop.{name}". The stack frames are encoded last, in order, as JSON objects in keys "stack.{index}".
+Properties and stack frames that do not fit in the budget are dropped and their count is noted in the
+"prop.dropped" and "stack.dropped" keys respectively.
+The serialization limits set by Configure are applied first.
+*/
+func EncodeToMap(err error) map[string]string {
+	if err == nil {
+		return nil
+	}
+	tracedErr := Convert(err).limited()
+	m := map[string]string{}
+	budget := mapBudget
+	put := func(k, v string) bool {
+		if len(k)+len(v) > budget {
+			return false
+		}
+		m[k] = v
+		budget -= len(k) + len(v)
+		return true
+	}
+
+	put("error", truncateString(tracedErr.Error(), mapMaxMessageLen))
+	put("statusCode", strconv.Itoa(tracedErr.StatusCode))
+	if tracedErr.Trace != "" && tracedErr.Trace != zeroTrace {
+		put("trace", truncateString(tracedErr.Trace, 64))
+	}
+	if tracedErr.Span != "" && tracedErr.Span != zeroSpan {
+		put("span", truncateString(tracedErr.Span, 64))
+	}
+	if tracedErr.Origin != OriginLocal {
+		put("origin", tracedErr.Origin.String())
+	}
+	// Reserve room for the counts of dropped entries
+	budget -= len("prop.dropped") + len("stack.dropped") + 2*len(strconv.Itoa(mapBudget))
+
+	dropped := 0
+	for _, k := range slices.Sorted(maps.Keys(tracedErr.Properties)) {
+		v, jsonErr := json.Marshal(tracedErr.Properties[k])
+		if jsonErr != nil {
+			v, _ = json.Marshal(badPropertyValue(tracedErr.Properties[k]))
+		}
+		if !put("prop."+k, string(v)) {
+			dropped++
+		}
+	}
+	if dropped > 0 {
+		m["prop.dropped"] = strconv.Itoa(dropped)
+	}
+
+	dropped = 0
+	for i, frame := range tracedErr.Stack {
+		v, _ := json.Marshal(frame)
+		if dropped > 0 || !put("stack."+strconv.Itoa(i), string(v)) {
+			dropped++
+		}
+	}
+	if dropped > 0 {
+		m["stack.dropped"] = strconv.Itoa(dropped)
+	}
+	return m
+}
+
+/*
+DecodeFromMap reconstructs an error from the map produced by EncodeToMap.
+It returns nil if the map does not contain an error.
+Neither the type of the error nor any errors it wraps can be restored.
+*/
+func DecodeFromMap(m map[string]string) error {
+	msg, ok := m["error"]
+	if !ok {
+		return nil
+	}
+	tracedErr := &TracedError{
+		Err:        stderrors.New(msg),
+		StatusCode: 500,
+		Trace:      m["trace"],
+		Span:       m["span"],
+		Origin:     parseOrigin(m["origin"]),
+	}
+	if statusCode, err := strconv.Atoi(m["statusCode"]); err == nil && statusCode > 0 {
+		tracedErr.StatusCode = statusCode
+	}
+	var stack []*StackFrame
+	for k, v := range m {
+		if name, ok := strings.CutPrefix(k, "prop."); ok && name != "dropped" {
+			var value any
+			if err := json.Unmarshal([]byte(v), &value); err != nil {
+				value = v
+			}
+			if tracedErr.Properties == nil {
+				tracedErr.Properties = map[string]any{}
+			}
+			tracedErr.Properties[name] = value
+		}
+		if index, ok := strings.CutPrefix(k, "stack."); ok {
+			i, err := strconv.Atoi(index)
+			if err != nil || i < 0 || i >= len(m) {
+				continue
+			}
+			var frame StackFrame
+			if err := json.Unmarshal([]byte(v), &frame); err != nil {
+				continue
+			}
+			if i >= len(stack) {
+				stack = append(stack, make([]*StackFrame, i+1-len(stack))...)
+			}
+			stack[i] = &frame
+		}
+	}
+	for _, frame := range stack {
+		if frame != nil {
+			tracedErr.Stack = append(tracedErr.Stack, frame)
+		}
+	}
+	if n, err := strconv.Atoi(m["stack.dropped"]); err == nil && n > 0 {
+		tracedErr.Stack = append(tracedErr.Stack, elisionFrame(n))
+	}
+	return tracedErr
+}
+
+// truncateString truncates the string to the maximum length in bytes, marking the truncation with an ellipsis.
+func truncateString(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	cut := max(maxLen-len("..."), 0)
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "..."
+}
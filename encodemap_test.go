@@ -0,0 +1,79 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestErrors_EncodeToMap(t *testing.T) {
+	t.Parallel()
+
+	err := New("oops", 409, "00-0123456789abcdef0123456789abcdef-0123456789abcdef-01", "name", "value", "count", 5)
+	err = Trace(err)
+	m := EncodeToMap(err)
+	assertEqual(t, "oops", m["error"])
+	assertEqual(t, "409", m["statusCode"])
+	assertEqual(t, "0123456789abcdef0123456789abcdef", m["trace"])
+	assertEqual(t, "0123456789abcdef", m["span"])
+	assertEqual(t, `"value"`, m["prop.name"])
+	assertEqual(t, `5`, m["prop.count"])
+	assertContains(t, m["stack.0"], `"func":"errors.TestErrors_EncodeToMap"`)
+	assertContains(t, m["stack.1"], `"func":"errors.TestErrors_EncodeToMap"`)
+
+	decoded := Convert(DecodeFromMap(m))
+	assertEqual(t, "oops", decoded.Error())
+	assertEqual(t, 409, decoded.StatusCode)
+	assertEqual(t, "0123456789abcdef0123456789abcdef", decoded.Trace)
+	assertEqual(t, "0123456789abcdef", decoded.Span)
+	assertEqual(t, "value", decoded.Properties["name"])
+	assertEqual(t, 5.0, decoded.Properties["count"])
+	assertEqual(t, Convert(err).Stack, decoded.Stack)
+
+	// Deterministic
+	assertEqual(t, m, EncodeToMap(err))
+
+	// Nil
+	assertNil(t, EncodeToMap(nil))
+	assertNil(t, DecodeFromMap(map[string]string{}))
+}
+
+func TestErrors_EncodeToMapBudget(t *testing.T) {
+	t.Parallel()
+
+	err := New(strings.Repeat("x", 5000), "big", strings.Repeat("y", 7500), "small", "z")
+	tracedErr := Convert(err)
+	for range 100 {
+		tracedErr.Stack = append(tracedErr.Stack, tracedErr.Stack[0])
+	}
+	m := EncodeToMap(tracedErr)
+	size := 0
+	for k, v := range m {
+		size += len(k) + len(v)
+	}
+	assertTrue(t, size <= mapBudget)
+	assertEqual(t, mapMaxMessageLen, len(m["error"]))
+	assertTrue(t, strings.HasSuffix(m["error"], "..."))
+	assertEqual(t, "1", m["prop.dropped"])
+	assertEqual(t, `"z"`, m["prop.small"])
+	assertNotEqual(t, "", m["stack.dropped"])
+
+	decoded := Convert(DecodeFromMap(m))
+	assertEqual(t, "z", decoded.Properties["small"])
+	assertTrue(t, decoded.Stack[len(decoded.Stack)-1].isElision())
+}
@@ -0,0 +1,183 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"runtime/pprof"
+	"strconv"
+	"sync"
+)
+
+// buildInfoProperties are the properties stamped on new errors when build info enrichment is enabled.
+var buildInfoProperties = sync.OnceValue(func() map[string]any {
+	props := map[string]any{
+		"pid": os.Getpid(),
+	}
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		props["hostname"] = hostname
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if info.Main.Version != "" {
+			props["buildVersion"] = info.Main.Version
+		}
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" && setting.Value != "" {
+				props["buildRevision"] = setting.Value
+			}
+		}
+	}
+	return props
+})
+
+/*
+EnableBuildInfoEnrichment enables or disables stamping errors created from this point on with the version of the main module,
+the VCS revision of the build, the hostname and the process ID,
+in the "buildVersion", "buildRevision", "hostname" and "pid" properties respectively.
+Properties that are not available are omitted, and properties that are set explicitly are not overridden.
+Enrichment is disabled by default and can be disabled again by passing false.
+
+	func main() {
+		errors.EnableBuildInfoEnrichment(true)
+		...
+	}
+*/
+func EnableBuildInfoEnrichment(enabled bool) {
+	updateSettings(func(s *settings) {
+		s.buildInfoEnrichment = enabled
+	})
+}
+
+/*
+EnablePprofLabelCapture enables or disables adding the pprof labels of the context to the properties of errors created or traced
+from this point on with a context argument. Labels that are set explicitly as properties are not overridden.
+Capture is disabled by default.
+The labels of the goroutine are only reachable through the context that carries them, such as the one passed to pprof.Do.
+
+	pprof.Do(ctx, pprof.Labels("tenant", tenantID), func(ctx context.Context) {
+		...
+		return errors.Trace(err, ctx)
+	})
+*/
+func EnablePprofLabelCapture(enabled bool) {
+	updateSettings(func(s *settings) {
+		s.pprofLabelCapture = enabled
+	})
+}
+
+/*
+EnableGoroutineCapture enables or disables recording the identifier of the goroutine on which an error is created from this point on,
+and the location of the go statement that started that goroutine,
+in the "goroutine" and "goroutineOrigin" properties respectively.
+It helps diagnose errors that surface on a different goroutine from where they originated.
+Capturing the goroutine is relatively expensive and is intended for debugging. It is disabled by default.
+*/
+func EnableGoroutineCapture(enabled bool) {
+	updateSettings(func(s *settings) {
+		s.goroutineCapture = enabled
+	})
+}
+
+// currentGoroutine returns the identifier of the current goroutine and the stack frame of the go statement that started it.
+// The origin is nil for the main goroutine.
+func currentGoroutine() (id int, origin *StackFrame) {
+	buf := make([]byte, 4096)
+	for {
+		n := runtime.Stack(buf, false)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	// goroutine 18 [running]:
+	header, rest, _ := bytes.Cut(buf, []byte("\n"))
+	header = bytes.TrimPrefix(header, []byte("goroutine "))
+	if p := bytes.IndexByte(header, ' '); p >= 0 {
+		id, _ = strconv.Atoi(string(header[:p]))
+	}
+	// created by main.main in goroutine 1
+	// 	/path/to/main.go:12 +0x1d
+	_, created, found := bytes.Cut(rest, []byte("\ncreated by "))
+	if !found {
+		return id, nil
+	}
+	function, location, _ := bytes.Cut(created, []byte("\n"))
+	if p := bytes.Index(function, []byte(" in goroutine ")); p >= 0 {
+		function = function[:p]
+	}
+	location, _, _ = bytes.Cut(bytes.TrimSpace(location), []byte("\n"))
+	if p := bytes.LastIndex(location, []byte(" +0x")); p >= 0 {
+		location = location[:p]
+	}
+	runtimeFrame := runtime.Frame{
+		Function: string(function),
+		File:     string(location),
+	}
+	if p := bytes.LastIndexByte(location, ':'); p >= 0 {
+		runtimeFrame.File = string(location[:p])
+		runtimeFrame.Line, _ = strconv.Atoi(string(location[p+1:]))
+	}
+	return id, newStackFrame(runtimeFrame)
+}
+
+// enrich adds the properties of the enabled enrichments to a newly created error.
+// The context is optional. If present, the timing of the operation per the context is attached as by WithTimeout.
+func enrich(err *TracedError, ctx context.Context) {
+	s := loadSettings()
+	if s.buildInfoEnrichment {
+		for k, v := range buildInfoProperties() {
+			setDefaultProperty(err, k, v)
+		}
+	}
+	if s.goroutineCapture {
+		if _, ok := err.Properties["goroutine"]; !ok {
+			id, origin := currentGoroutine()
+			setDefaultProperty(err, "goroutine", id)
+			if origin != nil {
+				setDefaultProperty(err, "goroutineOrigin", origin.Function+" "+origin.File+":"+strconv.Itoa(origin.Line))
+			}
+		}
+	}
+	if ctx != nil {
+		props := timeoutProperties(ctx)
+		for i := 0; i+1 < len(props); i += 2 {
+			setDefaultProperty(err, props[i].(string), props[i+1])
+		}
+	}
+	if s.pprofLabelCapture && ctx != nil {
+		pprof.ForLabels(ctx, func(k, v string) bool {
+			setDefaultProperty(err, k, v)
+			return true
+		})
+	}
+}
+
+// setDefaultProperty sets the property of the error unless it is already set.
+func setDefaultProperty(err *TracedError, k string, v any) {
+	if _, ok := err.Properties[k]; ok {
+		return
+	}
+	if err.Properties == nil {
+		err.Properties = map[string]any{}
+	}
+	err.Properties[k] = v
+}
@@ -0,0 +1,118 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	"os"
+	"runtime/pprof"
+	"strings"
+	"testing"
+)
+
+func TestErrors_BuildInfoEnrichment(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	err := New("not enriched")
+	_, ok := Convert(err).Properties["pid"]
+	assertTrue(t, !ok)
+
+	EnableBuildInfoEnrichment(true)
+	defer EnableBuildInfoEnrichment(false)
+
+	err = New("enriched")
+	tracedErr := Convert(err)
+	assertEqual(t, os.Getpid(), tracedErr.Properties["pid"])
+	if hostname, err := os.Hostname(); err == nil {
+		assertEqual(t, hostname, tracedErr.Properties["hostname"])
+	}
+
+	// Explicit properties are not overridden
+	err = New("explicit", "pid", "override")
+	assertEqual(t, "override", Convert(err).Properties["pid"])
+	err = Trace(err)
+	assertEqual(t, "override", Convert(err).Properties["pid"])
+
+	// Disabled again
+	EnableBuildInfoEnrichment(false)
+	_, ok = Convert(New("not enriched")).Properties["pid"]
+	assertTrue(t, !ok)
+}
+
+func TestErrors_PprofLabelCapture(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	ctx := pprof.WithLabels(context.Background(), pprof.Labels("tenant", "acme", "request", "r123"))
+	err := New("not captured", ctx)
+	tracedErr := Convert(err)
+	_, ok := tracedErr.Properties["tenant"]
+	assertTrue(t, !ok)
+	_, ok = tracedErr.Properties["!BADKEY"]
+	assertTrue(t, !ok)
+
+	EnablePprofLabelCapture(true)
+	defer EnablePprofLabelCapture(false)
+
+	err = New("captured", ctx, "request", "explicit")
+	tracedErr = Convert(err)
+	assertEqual(t, "acme", tracedErr.Properties["tenant"])
+	assertEqual(t, "explicit", tracedErr.Properties["request"])
+
+	pprof.Do(context.Background(), pprof.Labels("tenant", "globex"), func(ctx context.Context) {
+		err = Trace(New("traced"), ctx)
+	})
+	assertEqual(t, "globex", Convert(err).Properties["tenant"])
+
+	// No context
+	err = New("no context")
+	_, ok = Convert(err).Properties["tenant"]
+	assertTrue(t, !ok)
+
+	// Disabled again
+	EnablePprofLabelCapture(false)
+	_, ok = Convert(New("not captured", ctx)).Properties["tenant"]
+	assertTrue(t, !ok)
+}
+
+func TestErrors_GoroutineCapture(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	EnableGoroutineCapture(true)
+	defer EnableGoroutineCapture(false)
+
+	var err error
+	done := make(chan bool)
+	go func() {
+		err = New("async")
+		close(done)
+	}()
+	<-done
+	tracedErr := Convert(err)
+	id, ok := tracedErr.Properties["goroutine"].(int)
+	assertTrue(t, ok)
+	assertTrue(t, id > 0)
+	origin, _ := tracedErr.Properties["goroutineOrigin"].(string)
+	assertTrue(t, strings.HasPrefix(origin, "errors.TestErrors_GoroutineCapture "))
+	assertContains(t, origin, "enrich_test.go:")
+
+	// The originating goroutine is retained when traced on another goroutine
+	err = Trace(err)
+	assertEqual(t, id, Convert(err).Properties["goroutine"])
+	assertEqual(t, origin, Convert(err).Properties["goroutineOrigin"])
+
+	// Disabled again
+	EnableGoroutineCapture(false)
+	_, ok = Convert(New("not captured")).Properties["goroutine"]
+	assertTrue(t, !ok)
+}
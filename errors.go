@@ -17,8 +17,15 @@ limitations under the License.
 package errors
 
 import (
+	"context"
 	stderrors "errors"
 	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"runtime/debug"
+	"slices"
+	"time"
 )
 
 var statusText = map[int]string{
@@ -92,11 +99,101 @@ func As(err error, target any) bool {
 	return stderrors.As(err, target)
 }
 
+/*
+Find finds the first error in the error tree that matches the type T, and if one is found, returns it.
+It is a generic alternative to As.
+
+	if pathErr, ok := errors.Find[*fs.PathError](err); ok {
+		...
+	}
+*/
+func Find[T error](err error) (T, bool) {
+	var target T
+	ok := stderrors.As(err, &target)
+	return target, ok
+}
+
+// findTraced finds the first TracedError in the error tree.
+// Unlike As, it terminates even if an error ends up wrapping itself.
+func findTraced(err error) *TracedError {
+	// Fast path for linear chains, which avoids the allocations of Walk
+	for e, hops := err, 0; hops < 16; hops++ {
+		if tracedErr, ok := e.(*TracedError); ok {
+			return tracedErr
+		}
+		if _, ok := e.(interface{ Unwrap() []error }); ok {
+			break
+		}
+		u, ok := e.(interface{ Unwrap() error })
+		if !ok {
+			return nil
+		}
+		e = u.Unwrap()
+	}
+	var found *TracedError
+	Walk(err, func(e error) bool {
+		found, _ = e.(*TracedError)
+		return found == nil
+	})
+	return found
+}
+
+// AsTraced finds the deepest TracedError in the error tree.
+// Unlike Convert, which only looks at the top error, it finds traced errors that were wrapped by other errors.
+func AsTraced(err error) (*TracedError, bool) {
+	var deepest *TracedError
+	maxDepth := -1
+	walkDepth(err, func(e error, depth int) bool {
+		if tracedErr, ok := e.(*TracedError); ok && depth > maxDepth {
+			deepest = tracedErr
+			maxDepth = depth
+		}
+		return true
+	})
+	return deepest, deepest != nil
+}
+
 // Is delegates to the standard Go's errors.Is function.
 func Is(err, target error) bool {
 	return stderrors.Is(err, target)
 }
 
+/*
+Ignore returns nil if the error matches any of the targets per Is.
+Otherwise, the error is traced to the current stack location.
+
+	err := os.Remove(name)
+	return errors.Ignore(err, fs.ErrNotExist)
+*/
+func Ignore(err error, targets ...error) error {
+	if err == nil {
+		return nil
+	}
+	for _, target := range targets {
+		if Is(err, target) {
+			return nil
+		}
+	}
+	return Trace(err)
+}
+
+/*
+IgnoreStatus returns nil if the status code of the error is any of the indicated status codes.
+Otherwise, the error is traced to the current stack location.
+
+	err := client.Delete(ctx, id)
+	return errors.IgnoreStatus(err, http.StatusNotFound)
+*/
+func IgnoreStatus(err error, statusCodes ...int) error {
+	if err == nil {
+		return nil
+	}
+	if slices.Contains(statusCodes, StatusCode(err)) {
+		return nil
+	}
+	return Trace(err)
+}
+
 // Join aggregates multiple errors into one.
 // The stack traces of the original errors are discarded and a new stack trace is captured.
 func Join(errs ...error) error {
@@ -112,9 +209,13 @@ func Join(errs ...error) error {
 	case 0:
 		return nil
 	case 1:
-		return traceCaller(err)
+		return Trace(err)
 	default:
-		return traceCaller(stderrors.Join(errs...))
+		// The metadata of the joined errors is intentionally not carried over
+		return markSite(traceCaller(&TracedError{
+			Err:        stderrors.Join(errs...),
+			StatusCode: 500,
+		}), 0, "join")
 	}
 }
 
@@ -132,8 +233,87 @@ func Trace(err error, a ...any) error {
 	return New("", append([]any{err}, a...)...)
 }
 
+/*
+TraceIf traces the error only if the condition is true, and otherwise returns it as it is.
+It allows hot paths to skip capturing the stack location of expected errors.
+The variadic arguments behave like those of New.
+
+	return errors.TraceIf(!errors.Is(err, ErrCacheMiss), err)
+*/
+func TraceIf(cond bool, err error, a ...any) error {
+	if err == nil || !cond {
+		return err
+	}
+	return New("", append([]any{err}, a...)...)
+}
+
+/*
+TraceUnlessStatus traces the error unless its status code is any of the indicated status codes,
+in which case it is returned as it is.
+It allows hot paths to skip capturing the stack location of expected business errors.
+
+	return errors.TraceUnlessStatus(err, http.StatusNotFound, http.StatusConflict)
+*/
+func TraceUnlessStatus(err error, statusCodes ...int) error {
+	if err == nil || slices.Contains(statusCodes, StatusCode(err)) {
+		return err
+	}
+	return Trace(err)
+}
+
+// AddSuppressed attaches a secondary error to the primary error, for example when a rollback fails after an operation fails.
+// The secondary error does not change the message of the primary error, nor does it participate in Is or As,
+// but it is included in String and JSON.
+// If the primary error is nil, the secondary error is returned instead.
+// The primary error is not modified.
+func AddSuppressed(primary, secondary error) error {
+	if secondary == nil {
+		return primary
+	}
+	if primary == nil {
+		return Trace(secondary)
+	}
+	tracedErr := deriveTraced(primary)
+	tracedErr.Suppressed = append(tracedErr.Suppressed, secondary)
+	return tracedErr
+}
+
+/*
+DeferClose closes the closer and merges any error it returns into the error pointed to by errp.
+It is intended to be deferred by functions with a named error return value.
+The close error is wrapped with the message and arguments, which behave like those of New.
+If errp already holds an error, the close error is added to it as suppressed.
+
+	func writeFile(name string) (err error) {
+		f, err := os.Create(name)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer errors.DeferClose(&err, f, "closing %s", name)
+		...
+	}
+*/
+func DeferClose(errp *error, c io.Closer, msg string, args ...any) {
+	if c == nil {
+		return
+	}
+	closeErr := c.Close()
+	if closeErr == nil || errp == nil {
+		return
+	}
+	closeErr = New(msg, append(append([]any{}, args...), closeErr)...)
+	if *errp == nil {
+		*errp = closeErr
+	} else {
+		*errp = AddSuppressed(*errp, closeErr)
+	}
+}
+
 // Convert converts an error to one that supports stack tracing.
-// If the error already supports this, it is returned as it is.
+// If the error already supports this, it is returned as it is, unless it lacks a status code,
+// in which case a derived error with a status code of 500 is returned so as not to modify the original.
+// If a traced error is wrapped elsewhere in the error tree, for example by fmt.Errorf,
+// its status code, trace ID, properties and stack are carried over to the converted error.
 // Note: Trace should be called to include the error's trace in the stack.
 func Convert(err error) *TracedError {
 	if err == nil {
@@ -141,24 +321,158 @@ func Convert(err error) *TracedError {
 	}
 	if tracedErr, ok := err.(*TracedError); ok {
 		if tracedErr.StatusCode == 0 {
+			tracedErr = tracedErr.derive()
 			tracedErr.StatusCode = 500
 		}
 		return tracedErr
 	}
+	if wrappedErr := findTraced(err); wrappedErr != nil {
+		converted := &TracedError{
+			Err:           err,
+			Stack:         slices.Clip(wrappedErr.Stack),
+			StatusCode:    wrappedErr.StatusCode,
+			Trace:         wrappedErr.Trace,
+			Span:          wrappedErr.Span,
+			Properties:    maps.Clone(wrappedErr.Properties),
+			Suppressed:    slices.Clip(wrappedErr.Suppressed),
+			Origin:        wrappedErr.Origin,
+			RawPanicStack: wrappedErr.RawPanicStack,
+		}
+		if converted.StatusCode == 0 {
+			converted.StatusCode = 500
+		}
+		return converted
+	}
 	return &TracedError{
 		Err:        err,
-		StatusCode: 500,
+		StatusCode: mapStatusCode(err),
 	}
 }
 
+/*
+CatchPanicExcept calls the given function and returns any panic as a standard error,
+except for the indicated panic values, which are re-panicked.
+It is intended for code that relies on panics for control flow, such as http.ErrAbortHandler.
+A panic value matches if it equals a passthrough value or, if both are errors, if it Is the passthrough value.
+
+	err = errors.CatchPanicExcept(func() error {
+		return handle(w, r)
+	}, http.ErrAbortHandler)
+*/
+func CatchPanicExcept(f func() error, passthrough ...any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if matchesPanic(r, passthrough) {
+				panic(r)
+			}
+			err = recoveredError(r, 1)
+		}
+	}()
+	err = f()
+	return
+}
+
+// matchesPanic indicates if the recovered panic value matches any of the targets.
+func matchesPanic(r any, targets []any) bool {
+	rErr, rIsErr := r.(error)
+	rType := reflect.TypeOf(r)
+	for _, target := range targets {
+		if target == nil {
+			continue
+		}
+		if rType == reflect.TypeOf(target) && rType.Comparable() && r == target {
+			return true
+		}
+		if targetErr, ok := target.(error); ok && rIsErr && stderrors.Is(rErr, targetErr) {
+			return true
+		}
+	}
+	return false
+}
+
+// recoveredError converts a recovered panic value to an error traced with the full stack of the panic,
+// starting at the indicated level. In debug mode, the unfiltered stack of the panic is captured as well.
+func recoveredError(r any, level int) error {
+	err, ok := r.(error)
+	if !ok {
+		err = fmt.Errorf("%v", r)
+	}
+	// The panic value may be shared, e.g. a package-level error, and must not be modified
+	tracedErr := deriveTraced(err)
+	if loadSettings().debugMode {
+		tracedErr.RawPanicStack = string(debug.Stack())
+	}
+	n := len(tracedErr.Stack)
+	return markSite(traceFull(tracedErr, level+1), n, "panic")
+}
+
+// deriveTraced converts the error to a traced error that can be modified without affecting the original error.
+// Errors shared across goroutines, such as package-level errors, must not be modified in place.
+func deriveTraced(err error) *TracedError {
+	tracedErr := Convert(err)
+	if tracedErr == err {
+		tracedErr = tracedErr.derive()
+	}
+	return tracedErr
+}
+
 // StatusCode returns the HTTP status code associated with an error.
-// It is the equivalent of Convert(err).StatusCode.
-// The default status code is 500.
+// It is the equivalent of Convert(err).StatusCode, but does not allocate.
+// The status code of a traced error is respected even if it is wrapped by another error.
+// Otherwise, the status code is determined by the registered status mappers, and defaults to 500.
 func StatusCode(err error) int {
 	if err == nil {
 		return 0
 	}
-	return Convert(err).StatusCode
+	if tracedErr := findTraced(err); tracedErr != nil {
+		if tracedErr.StatusCode == 0 {
+			return 500
+		}
+		return tracedErr.StatusCode
+	}
+	return mapStatusCode(err)
+}
+
+/*
+IsStatus indicates if the status code of the error is any of the indicated status codes.
+It does not allocate.
+
+	if errors.IsStatus(err, http.StatusNotFound, http.StatusGone) {
+		...
+	}
+*/
+func IsStatus(err error, statusCodes ...int) bool {
+	return err != nil && slices.Contains(statusCodes, StatusCode(err))
+}
+
+// TraceID returns the trace ID associated with an error, or an empty string if none is.
+// The trace ID of a traced error is respected even if it is wrapped by another error.
+// It is the equivalent of Convert(err).Trace, but does not allocate.
+func TraceID(err error) string {
+	tracedErr := findTraced(err)
+	if tracedErr == nil || tracedErr.Trace == zeroTrace {
+		return ""
+	}
+	return tracedErr.Trace
+}
+
+// SpanID returns the span ID associated with an error, or an empty string if none is.
+// The span ID of a traced error is respected even if it is wrapped by another error.
+func SpanID(err error) string {
+	tracedErr := findTraced(err)
+	if tracedErr == nil || tracedErr.Span == zeroSpan {
+		return ""
+	}
+	return tracedErr.Span
+}
+
+// tracedProperties returns the properties of the first traced error in the error tree, without the allocations of Convert.
+// The returned map must not be modified.
+func tracedProperties(err error) map[string]any {
+	if tracedErr := findTraced(err); tracedErr != nil {
+		return tracedErr.Properties
+	}
+	return nil
 }
 
 /*
@@ -172,14 +486,34 @@ CatchPanic calls the given function and returns any panic as a standard error.
 func CatchPanic(f func() error) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			if e, ok := r.(error); ok {
-				err = e
-			} else {
-				err = fmt.Errorf("%v", r)
-			}
-			err = traceFull(err, 1)
+			err = recoveredError(r, 1)
 		}
 	}()
 	err = f()
 	return
 }
+
+/*
+CatchPanicCtx calls the given function with the context and returns any panic as a standard error.
+If the function fails, the error is annotated per WithTimeout, with the elapsed time measured from the call to CatchPanicCtx
+unless the context was already marked by ContextWithStartTime.
+
+	err = errors.CatchPanicCtx(ctx, func(ctx context.Context) error {
+		return process(ctx, job)
+	})
+*/
+func CatchPanicCtx(ctx context.Context, f func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(startTimeContextKey{}).(time.Time); !ok {
+		ctx = ContextWithStartTime(ctx)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = recoveredError(r, 1)
+		}
+		if err != nil {
+			err = WithTimeout(ctx, err)
+		}
+	}()
+	err = f(ctx)
+	return
+}
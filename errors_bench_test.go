@@ -0,0 +1,157 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"fmt"
+	"io/fs"
+	"testing"
+)
+
+func BenchmarkErrors_StatusCode(b *testing.B) {
+	plain := fs.ErrNotExist
+	wrapped := fmt.Errorf("wrapped: %w", New("traced", 409))
+	b.ReportAllocs()
+	for b.Loop() {
+		StatusCode(plain)
+		StatusCode(wrapped)
+	}
+}
+
+func BenchmarkErrors_IsStatus(b *testing.B) {
+	err := fmt.Errorf("wrapped: %w", fs.ErrNotExist)
+	b.ReportAllocs()
+	for b.Loop() {
+		IsStatus(err, 404, 410)
+	}
+}
+
+func BenchmarkErrors_TraceID(b *testing.B) {
+	err := fmt.Errorf("wrapped: %w", New("traced", "0123456789abcdef0123456789abcdef"))
+	b.ReportAllocs()
+	for b.Loop() {
+		TraceID(err)
+	}
+}
+
+func BenchmarkErrors_NewStatic(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = New("oops")
+	}
+}
+
+func BenchmarkErrors_NewWithStatusCode(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = New("not found", 404)
+	}
+}
+
+func BenchmarkErrors_NewWithProperties(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = New("oops", 400, "key1", "value1", "key2", 2, "key3", true)
+	}
+}
+
+func BenchmarkErrors_NewFormatted(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = New("failed to process %s #%d", "order", 123)
+	}
+}
+
+func BenchmarkErrors_NewWrapped(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = New("failed to open", fs.ErrNotExist)
+	}
+}
+
+func BenchmarkErrors_TraceChain(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		err := New("oops")
+		for range 5 {
+			err = traceChainLevel(err)
+		}
+	}
+}
+
+//go:noinline
+func traceChainLevel(err error) error {
+	return Trace(err)
+}
+
+func BenchmarkErrors_String(b *testing.B) {
+	err := Convert(New("oops", 400, "key1", "value1", "key2", 2))
+	for range 5 {
+		err = Convert(traceChainLevel(err))
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = err.String()
+	}
+}
+
+func BenchmarkErrors_MarshalJSON(b *testing.B) {
+	err := Convert(New("oops", 400, "key1", "value1", "key2", 2))
+	for range 5 {
+		err = Convert(traceChainLevel(err))
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = err.MarshalJSON()
+	}
+}
+
+func BenchmarkErrors_CatchPanic(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = CatchPanic(func() error {
+			panic("oops")
+		})
+	}
+}
+
+func TestErrors_AllocationBudget(t *testing.T) {
+	// Not parallel because allocations are counted across the process
+	err := New("oops", 400, "key", "value")
+	tracedErr := Convert(err)
+	deep := err
+	for range 1000 {
+		deep = Trace(deep)
+	}
+	budgets := []struct {
+		name   string
+		budget float64
+		f      func()
+	}{
+		{"New", 6, func() { _ = New("oops") }},
+		{"NewWithStatusCode", 6, func() { _ = New("not found", 404) }},
+		{"Trace", 9, func() { _ = Trace(err) }},
+		{"String", 12, func() { _ = tracedErr.String() }},
+		{"ErrorOfDeepChain", 0, func() { _ = deep.Error() }},
+	}
+	for _, b := range budgets {
+		allocs := testing.AllocsPerRun(100, b.f)
+		if allocs > b.budget {
+			t.Errorf("%s allocates %v times, exceeding the budget of %v", b.name, allocs, b.budget)
+		}
+	}
+}
@@ -17,11 +17,16 @@ limitations under the License.
 package errors
 
 import (
+	"context"
+	"encoding/json"
 	stderrors "errors"
 	"fmt"
+	"io/fs"
+	"math"
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -221,6 +226,31 @@ func TestErrors_JSON(t *testing.T) {
 	assertEqual(t, err.(*TracedError).String(), unmarshal.String())
 }
 
+func TestErrors_JSONUnmarshalableProperties(t *testing.T) {
+	t.Parallel()
+
+	suppressed := New("suppressed", "nan", math.NaN())
+	err := New("error!", "ch", make(chan int), "ok", 1)
+	err = AddSuppressed(err, suppressed)
+	b, jsonErr := json.Marshal(err)
+	assertNil(t, jsonErr)
+
+	var m map[string]any
+	assertNil(t, json.Unmarshal(b, &m))
+	assertEqual(t, "error!", m["error"])
+	assertEqual(t, 1.0, m["ok"])
+	assertTrue(t, strings.HasPrefix(m["ch"].(string), "!BADVALUE(chan int) 0x"))
+	s := m["suppressed"].([]any)[0].(map[string]any)
+	assertEqual(t, "!BADVALUE(float64) NaN", s["nan"])
+
+	// The error itself is not modified
+	_, ok := Convert(err).Properties["ch"].(chan int)
+	assertTrue(t, ok)
+
+	encoded := EncodeToMap(New("error!", "f", func() {}))
+	assertContains(t, encoded["prop.f"], "!BADVALUE(func())")
+}
+
 func TestErrors_Format(t *testing.T) {
 	t.Parallel()
 
@@ -275,7 +305,10 @@ func TestErrors_Join(t *testing.T) {
 	}
 
 	assertNil(t, Join(nil, nil))
-	assertEqual(t, e3, Join(e3, nil))
+	j = Join(e3, nil)
+	assertTrue(t, Is(j, e3))
+	assertEqual(t, 3, len(Convert(j).Stack))
+	assertEqual(t, 2, len(Convert(e3).Stack))
 }
 
 func TestErrors_String(t *testing.T) {
@@ -374,6 +407,323 @@ func TestErrors_CatchPanic(t *testing.T) {
 	assertEqual(t, "standard", err.Error())
 }
 
+func TestErrors_RawPanicStack(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	err := CatchPanic(func() error {
+		panic("message")
+	})
+	assertEqual(t, "", Convert(err).RawPanicStack)
+
+	SetDebugMode(true)
+	defer SetDebugMode(false)
+
+	err = CatchPanic(func() error {
+		panic("message")
+	})
+	tracedErr := Convert(err)
+	assertContains(t, tracedErr.RawPanicStack, "goroutine ")
+	assertContains(t, tracedErr.RawPanicStack, "panic(")
+	assertContains(t, tracedErr.RawPanicStack, "TestErrors_RawPanicStack")
+	assertContains(t, tracedErr.String(), "raw panic stack:")
+
+	// Carried over by Trace and New
+	assertEqual(t, tracedErr.RawPanicStack, Convert(Trace(err)).RawPanicStack)
+	assertEqual(t, tracedErr.RawPanicStack, Convert(New("wrapped", err)).RawPanicStack)
+
+	// Restored by ParseString
+	parsed, parseErr := ParseString(tracedErr.String())
+	assertNil(t, parseErr)
+	assertEqual(t, tracedErr.RawPanicStack, parsed.RawPanicStack)
+	assertEqual(t, tracedErr.Stack, parsed.Stack)
+}
+
+func TestErrors_AccessorsDoNotAllocate(t *testing.T) {
+	// Not parallel because allocations are counted across the process
+	plain := fs.ErrNotExist
+	wrapped := fmt.Errorf("wrapped: %w", New("traced", 409, "0123456789abcdef0123456789abcdef", "retryable", true))
+	traced := New("traced", 404)
+	for _, err := range []error{plain, wrapped, traced} {
+		allocs := testing.AllocsPerRun(100, func() {
+			StatusCode(err)
+			IsStatus(err, 404, 409)
+			TraceID(err)
+			IsRetryable(err)
+			RetryAfter(err)
+			Help(err)
+		})
+		assertEqual(t, 0.0, allocs)
+	}
+}
+
+func TestErrors_IsStatus(t *testing.T) {
+	t.Parallel()
+
+	assertTrue(t, IsStatus(New("not found", 404), 400, 404))
+	assertTrue(t, IsStatus(fs.ErrNotExist, 404))
+	assertTrue(t, IsStatus(fmt.Errorf("wrapped: %w", New("gone", 410)), 410))
+	assertTrue(t, !IsStatus(New("oops"), 404))
+	assertTrue(t, !IsStatus(nil, 404))
+	assertTrue(t, !IsStatus(New("not found", 404)))
+}
+
+func TestErrors_TraceID(t *testing.T) {
+	t.Parallel()
+
+	traceID := "0123456789abcdef0123456789abcdef"
+	assertEqual(t, traceID, TraceID(New("oops", traceID)))
+	assertEqual(t, traceID, TraceID(fmt.Errorf("wrapped: %w", New("oops", traceID))))
+	assertEqual(t, traceID, TraceID(stderrors.Join(stderrors.New("other"), New("oops", traceID))))
+	assertEqual(t, "", TraceID(New("oops")))
+	assertEqual(t, "", TraceID(New("oops", zeroTrace)))
+	assertEqual(t, "", TraceID(fs.ErrNotExist))
+	assertEqual(t, "", TraceID(nil))
+}
+
+func TestErrors_CatchPanicCtx(t *testing.T) {
+	t.Parallel()
+
+	// Panic
+	err := CatchPanicCtx(context.Background(), func(ctx context.Context) error {
+		panic("message")
+	})
+	assertEqual(t, "message", err.Error())
+	assertEqual(t, 500, StatusCode(err))
+	assertContains(t, Convert(err).String(), "TestErrors_CatchPanicCtx")
+
+	// Success
+	err = CatchPanicCtx(context.Background(), func(ctx context.Context) error {
+		return nil
+	})
+	assertNil(t, err)
+
+	// Deadline exceeded
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	err = CatchPanicCtx(ctx, func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(5 * time.Millisecond)
+		return New("timed out waiting")
+	})
+	assertEqual(t, 504, StatusCode(err))
+	assertTrue(t, strings.HasPrefix(Convert(err).Properties["deadlineIn"].(string), "-"))
+
+	// Canceled
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	err = CatchPanicCtx(ctx, func(ctx context.Context) error {
+		return ctx.Err()
+	})
+	assertEqual(t, 499, StatusCode(err))
+	_, ok := Convert(err).Properties["deadlineIn"]
+	assertTrue(t, !ok)
+	_, ok = Convert(err).Properties["elapsed"]
+	assertTrue(t, ok)
+
+	// Explicit status codes are respected
+	err = CatchPanicCtx(ctx, func(ctx context.Context) error {
+		return New("unavailable", 503)
+	})
+	assertEqual(t, 503, StatusCode(err))
+
+	// Remaining time
+	ctx, cancel = context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	err = CatchPanicCtx(ctx, func(ctx context.Context) error {
+		return New("oops", 400)
+	})
+	assertEqual(t, 400, StatusCode(err))
+	deadlineIn, parseErr := time.ParseDuration(Convert(err).Properties["deadlineIn"].(string))
+	assertNil(t, parseErr)
+	assertTrue(t, deadlineIn > 59*time.Minute && deadlineIn <= time.Hour)
+}
+
+func TestErrors_CatchPanicExcept(t *testing.T) {
+	t.Parallel()
+
+	sentinel := stderrors.New("control flow")
+	type stop struct{ reason string }
+
+	// Not passed through
+	err := CatchPanicExcept(func() error {
+		panic("message")
+	}, sentinel, stop{reason: "done"})
+	assertError(t, err)
+	assertEqual(t, "message", err.Error())
+	assertTrue(t, len(Convert(err).Stack) > 0)
+
+	// Non-comparable panic values do not match
+	err = CatchPanicExcept(func() error {
+		panic([]int{1})
+	}, []int{1})
+	assertError(t, err)
+
+	// No panic
+	err = CatchPanicExcept(func() error {
+		return nil
+	}, sentinel)
+	assertNil(t, err)
+
+	// Passed through by equality
+	r := func() (r any) {
+		defer func() { r = recover() }()
+		CatchPanicExcept(func() error {
+			panic(stop{reason: "done"})
+		}, sentinel, stop{reason: "done"})
+		return nil
+	}()
+	assertEqual(t, stop{reason: "done"}, r)
+
+	// Passed through by Is
+	r = func() (r any) {
+		defer func() { r = recover() }()
+		CatchPanicExcept(func() error {
+			panic(New("wrapped", sentinel))
+		}, sentinel)
+		return nil
+	}()
+	rErr, ok := r.(error)
+	assertTrue(t, ok)
+	assertTrue(t, Is(rErr, sentinel))
+}
+
+func TestErrors_Ignore(t *testing.T) {
+	t.Parallel()
+
+	assertNil(t, Ignore(nil, fs.ErrNotExist))
+	assertNil(t, Ignore(fs.ErrNotExist, fs.ErrNotExist))
+	assertNil(t, Ignore(New("wrapped", fs.ErrNotExist), fs.ErrPermission, fs.ErrNotExist))
+
+	err := Ignore(fs.ErrPermission, fs.ErrNotExist)
+	assertError(t, err)
+	assertTrue(t, Is(err, fs.ErrPermission))
+	assertEqual(t, 1, len(Convert(err).Stack))
+	assertContains(t, Convert(err).Stack[0].File, "errors/errors_test.go")
+
+	assertNil(t, IgnoreStatus(nil, 404))
+	assertNil(t, IgnoreStatus(New("not found", 404), 404, 410))
+	err = New("forbidden", 403)
+	err = IgnoreStatus(err, 404)
+	assertError(t, err)
+	assertEqual(t, 403, StatusCode(err))
+	assertEqual(t, 2, len(Convert(err).Stack))
+}
+
+func TestErrors_TraceIf(t *testing.T) {
+	t.Parallel()
+
+	base := New("base", 404)
+	assertEqual(t, 1, len(Convert(base).Stack))
+
+	err := TraceIf(false, base, "key", "value")
+	assertEqual(t, base, err)
+	err = TraceIf(true, base, "key", "value")
+	assertEqual(t, 2, len(Convert(err).Stack))
+	assertEqual(t, "value", Convert(err).Properties["key"])
+	assertNil(t, TraceIf(true, nil))
+
+	err = TraceUnlessStatus(base, 404)
+	assertEqual(t, base, err)
+	err = TraceUnlessStatus(base, 500)
+	assertEqual(t, 2, len(Convert(err).Stack))
+	assertNil(t, TraceUnlessStatus(nil, 404))
+}
+
+func TestErrors_With(t *testing.T) {
+	t.Parallel()
+
+	base := New("base")
+	err := With(base, 409, "0123456789abcdef0123456789abcdef", "key", "value", "dangling")
+	assertNotEqual(t, base, err)
+	assertTrue(t, Is(err, base))
+	assertEqual(t, 500, StatusCode(base))
+	assertEqual(t, 0, len(Convert(base).Properties))
+	tracedErr := Convert(err)
+	assertEqual(t, 1, len(tracedErr.Stack))
+	assertEqual(t, 409, tracedErr.StatusCode)
+	assertEqual(t, "0123456789abcdef0123456789abcdef", tracedErr.Trace)
+	assertEqual(t, "value", tracedErr.Properties["key"])
+	assertEqual(t, "", tracedErr.Properties["dangling"])
+	assertEqual(t, "base", err.Error())
+
+	// Standard errors are converted without capturing the stack
+	err = With(stderrors.New("standard"), 400)
+	assertEqual(t, 400, StatusCode(err))
+	assertEqual(t, 0, len(Convert(err).Stack))
+
+	// Bad keys
+	err = With(New("bad"), 1.5)
+	assertEqual(t, 1.5, Convert(err).Properties["!BADKEY"])
+
+	assertNil(t, With(nil, 400))
+}
+
+func TestErrors_SharedNotModified(t *testing.T) {
+	t.Parallel()
+
+	// A package-level traced error that may be shared across goroutines
+	shared := &TracedError{Err: stderrors.New("shared")}
+	assertEqual(t, 500, Convert(shared).StatusCode)
+	err := With(shared, 409, "key", "value")
+	assertEqual(t, 409, StatusCode(err))
+	err = AddSuppressed(shared, stderrors.New("suppressed"))
+	assertEqual(t, 1, len(Convert(err).Suppressed))
+	err = Trace(shared)
+	assertEqual(t, 1, len(Convert(err).Stack))
+	err = Ignore(shared, os.ErrNotExist)
+	assertEqual(t, 1, len(Convert(err).Stack))
+	err = TraceUnlessStatus(shared, 404)
+	assertEqual(t, 1, len(Convert(err).Stack))
+	err = CatchPanic(func() error {
+		panic(shared)
+	})
+	assertTrue(t, Is(err, shared))
+	assertTrue(t, len(Convert(err).Stack) > 0)
+
+	assertEqual(t, 0, shared.StatusCode)
+	assertEqual(t, 0, len(shared.Stack))
+	assertEqual(t, 0, len(shared.Properties))
+	assertEqual(t, 0, len(shared.Suppressed))
+
+	// Concurrent modifications should not race
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = With(shared, 400+i, "i", i)
+			_ = Trace(shared, "i", i)
+		}()
+	}
+	wg.Wait()
+	assertEqual(t, 0, shared.StatusCode)
+}
+
+func TestErrors_Sentinel(t *testing.T) {
+	t.Parallel()
+
+	errNotFound := Sentinel("not found", http.StatusNotFound, "code", "NOT_FOUND")
+	sentinel := Convert(errNotFound)
+	assertEqual(t, errNotFound, sentinel)
+	assertEqual(t, "not found", errNotFound.Error())
+	assertEqual(t, 404, sentinel.StatusCode)
+	assertEqual(t, "NOT_FOUND", sentinel.Properties["code"])
+	assertEqual(t, 0, len(sentinel.Stack))
+
+	err := Trace(errNotFound, "id", 123)
+	assertTrue(t, Is(err, errNotFound))
+	assertEqual(t, 404, StatusCode(err))
+	assertEqual(t, "NOT_FOUND", Convert(err).Properties["code"])
+	assertEqual(t, 123, Convert(err).Properties["id"])
+	err = With(errNotFound, 410, "id", 456)
+	assertEqual(t, 410, StatusCode(err))
+	err = New("lookup failed", errNotFound)
+	assertTrue(t, Is(err, errNotFound))
+
+	assertEqual(t, 404, sentinel.StatusCode)
+	assertEqual(t, 1, len(sentinel.Properties))
+	assertEqual(t, 0, len(sentinel.Stack))
+}
+
 func TestErrors_AnonymousProperties(t *testing.T) {
 	t.Parallel()
 
@@ -391,3 +741,156 @@ func TestErrors_AnonymousProperties(t *testing.T) {
 	err = New("failed", "0123456789abcdef0123456789abcdef")
 	assertEqual(t, "0123456789abcdef0123456789abcdef", Convert(err).Trace)
 }
+
+func TestErrors_AddSuppressed(t *testing.T) {
+	t.Parallel()
+
+	primary := New("operation failed", 400)
+	secondary := New("rollback failed", "table", "users")
+	err := AddSuppressed(primary, secondary)
+	assertEqual(t, "operation failed", err.Error())
+	assertEqual(t, 400, StatusCode(err))
+	assertTrue(t, Is(err, primary))
+	assertTrue(t, !Is(err, secondary))
+	assertEqual(t, 1, len(Convert(err).Suppressed))
+
+	s := Convert(err).String()
+	assertContains(t, s, "suppressed: rollback failed")
+	assertContains(t, s, "table=users")
+
+	// JSON round trip
+	b, jsonErr := Convert(err).MarshalJSON()
+	assertNil(t, jsonErr)
+	var unmarshal TracedError
+	jsonErr = unmarshal.UnmarshalJSON(b)
+	assertNil(t, jsonErr)
+	assertEqual(t, 0, len(unmarshal.Properties))
+	assertEqual(t, 1, len(unmarshal.Suppressed))
+	if len(unmarshal.Suppressed) == 1 {
+		assertEqual(t, "rollback failed", unmarshal.Suppressed[0].Error())
+		assertEqual(t, "users", Convert(unmarshal.Suppressed[0]).Properties["table"])
+	}
+
+	// Nil arguments
+	assertEqual(t, primary, AddSuppressed(primary, nil))
+	assertNil(t, AddSuppressed(nil, nil))
+	err = AddSuppressed(nil, stderrors.New("rollback failed"))
+	assertEqual(t, "rollback failed", err.Error())
+	assertEqual(t, 0, len(Convert(err).Suppressed))
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
+
+func TestErrors_DeferClose(t *testing.T) {
+	t.Parallel()
+
+	closeErr := stderrors.New("close failed")
+	failingCloser := closerFunc(func() error { return closeErr })
+	okCloser := closerFunc(func() error { return nil })
+
+	// Close error becomes the return error
+	f := func() (err error) {
+		defer DeferClose(&err, failingCloser, "closing %s", "file.txt")
+		return nil
+	}
+	err := f()
+	assertError(t, err)
+	assertEqual(t, "closing file.txt: close failed", err.Error())
+	assertTrue(t, Is(err, closeErr))
+	assertContains(t, Convert(err).Stack[0].Function, "TestErrors_DeferClose")
+
+	// Close error is suppressed by the return error
+	opErr := stderrors.New("operation failed")
+	f = func() (err error) {
+		defer DeferClose(&err, failingCloser, "closing")
+		return opErr
+	}
+	err = f()
+	assertEqual(t, "operation failed", err.Error())
+	assertTrue(t, Is(err, opErr))
+	assertTrue(t, !Is(err, closeErr))
+	assertEqual(t, 1, len(Convert(err).Suppressed))
+
+	// Successful close
+	f = func() (err error) {
+		defer DeferClose(&err, okCloser, "closing")
+		return nil
+	}
+	assertNil(t, f())
+}
+
+func TestErrors_Find(t *testing.T) {
+	t.Parallel()
+
+	_, originalErr := os.Open("non/existent.file")
+	err := New("failed to open", originalErr, 404)
+	err = fmt.Errorf("wrapped: %w", err)
+
+	pathErr, ok := Find[*os.PathError](err)
+	assertTrue(t, ok)
+	assertEqual(t, "non/existent.file", pathErr.Path)
+
+	tracedErr, ok := Find[*TracedError](err)
+	assertTrue(t, ok)
+	assertEqual(t, 404, tracedErr.StatusCode)
+
+	_, ok = Find[*os.LinkError](err)
+	assertTrue(t, !ok)
+	_, ok = Find[*os.PathError](nil)
+	assertTrue(t, !ok)
+}
+
+func TestErrors_AsTraced(t *testing.T) {
+	t.Parallel()
+
+	inner := New("inner", 404)
+	outer := New("outer", inner, 400)
+	err := fmt.Errorf("wrapped: %w", outer)
+
+	tracedErr, ok := AsTraced(err)
+	assertTrue(t, ok)
+	assertEqual(t, inner, tracedErr)
+
+	tracedErr, ok = AsTraced(stderrors.Join(stderrors.New("plain"), fmt.Errorf("wrapped: %w", inner)))
+	assertTrue(t, ok)
+	assertEqual(t, inner, tracedErr)
+
+	_, ok = AsTraced(stderrors.New("plain"))
+	assertTrue(t, !ok)
+	_, ok = AsTraced(nil)
+	assertTrue(t, !ok)
+}
+
+func TestErrors_ConvertWrapped(t *testing.T) {
+	t.Parallel()
+
+	inner := New("inner", 404, "0123456789abcdef0123456789abcdef", "key", "value")
+	err := fmt.Errorf("third party: %w", inner)
+
+	tracedErr := Convert(err)
+	assertEqual(t, "third party: inner", tracedErr.Error())
+	assertEqual(t, 404, tracedErr.StatusCode)
+	assertEqual(t, "0123456789abcdef0123456789abcdef", tracedErr.Trace)
+	assertEqual(t, "value", tracedErr.Properties["key"])
+	assertEqual(t, 1, len(tracedErr.Stack))
+	assertTrue(t, Is(tracedErr, inner))
+
+	// The wrapped error is not modified
+	tracedErr.Properties["key"] = "modified"
+	assertEqual(t, "value", inner.(*TracedError).Properties["key"])
+
+	// Tracing preserves the metadata
+	err = Trace(err)
+	assertEqual(t, 404, StatusCode(err))
+	assertEqual(t, 2, len(Convert(err).Stack))
+	assertEqual(t, 1, len(inner.(*TracedError).Stack))
+
+	// Suppressed errors are carried over
+	err = AddSuppressed(New("primary"), stderrors.New("secondary"))
+	err = Trace(fmt.Errorf("wrapped: %w", err))
+	assertEqual(t, 1, len(Convert(err).Suppressed))
+}
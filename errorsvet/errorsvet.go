@@ -0,0 +1,265 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package errorsvet provides an analyzer that reports malformed arguments of New, Trace, TraceIf, With and Sentinel
+at build time, rather than as !BADKEY properties at runtime.
+
+It reports:
+  - a key that is missing its value
+  - an argument in the position of a key that is neither a string nor one of the recognized unnamed arguments
+  - a pattern with more format verbs than arguments
+  - a property that looks like a misplaced status code, e.g. "statusCode", 404
+  - an error that is formatted by the pattern with a verb other than %w rather than wrapped
+
+It can be run standalone or by go vet:
+
+	go install github.com/microbus-io/errors/errorsvet/cmd/errorsvet@latest
+	go vet -vettool=$(which errorsvet) ./...
+*/
+package errorsvet
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const errorsPkgPath = "github.com/microbus-io/errors"
+
+// Analyzer reports malformed arguments of New, Trace, TraceIf, With and Sentinel.
+var Analyzer = &analysis.Analyzer{
+	Name:     "errorsvet",
+	Doc:      "report malformed arguments of the functions of github.com/microbus-io/errors",
+	URL:      "https://pkg.go.dev/github.com/microbus-io/errors/errorsvet",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// signature describes the position of the arguments of a function of the errors package.
+type signature struct {
+	// pattern is the index of the format pattern, or -1 if none
+	pattern int
+	// args is the index of the variadic arguments
+	args int
+	// with indicates the argument semantics of With, which does not accept errors
+	with bool
+}
+
+var signatures = map[string]signature{
+	"New":      {pattern: 0, args: 1},
+	"Sentinel": {pattern: 0, args: 1},
+	"Trace":    {pattern: -1, args: 1},
+	"TraceIf":  {pattern: -1, args: 2},
+	"With":     {pattern: -1, args: 1, with: true},
+}
+
+// statusCodeKeys are property names that indicate a misplaced status code.
+var statusCodeKeys = map[string]bool{
+	"status":      true,
+	"statusCode":  true,
+	"statuscode":  true,
+	"status_code": true,
+	"httpStatus":  true,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+		if !ok || fn.Pkg() == nil || fn.Pkg().Path() != errorsPkgPath {
+			return
+		}
+		if sig, ok := fn.Type().(*types.Signature); !ok || sig.Recv() != nil {
+			return
+		}
+		sig, ok := signatures[fn.Name()]
+		if !ok || call.Ellipsis.IsValid() || len(call.Args) < sig.args {
+			return
+		}
+		checkCall(pass, call, fn.Name(), sig)
+	})
+	return nil, nil
+}
+
+// checkCall checks the arguments of a call to a function of the errors package.
+func checkCall(pass *analysis.Pass, call *ast.CallExpr, name string, sig signature) {
+	args := call.Args[sig.args:]
+	if sig.pattern >= 0 {
+		pattern, ok := constantString(pass, call.Args[sig.pattern])
+		if ok {
+			verbs := formatVerbs(pattern)
+			// New counts the % signs rather than the verbs
+			pctArgs := strings.Count(pattern, "%") - 2*strings.Count(pattern, "%%")
+			if pctArgs > len(args) {
+				pass.Reportf(call.Args[sig.pattern].Pos(), "%s pattern has %d format verbs but %d arguments", name, pctArgs, len(args))
+			}
+			for i := 0; i < min(len(verbs), len(args)); i++ {
+				if verbs[i] != 'w' && isError(pass.TypesInfo.TypeOf(args[i])) {
+					pass.Reportf(args[i].Pos(), "error formatted with %%%c is not wrapped; use %%w or pass it as an unnamed argument", verbs[i])
+				}
+			}
+			args = args[min(max(pctArgs, 0), len(args)):]
+		}
+	}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		t := pass.TypesInfo.TypeOf(arg)
+		if t == nil {
+			continue
+		}
+		switch {
+		case types.Identical(t, types.Typ[types.Int]) || isUntypedInt(pass, arg):
+			// Status code
+		case isError(t):
+			if sig.with {
+				pass.Reportf(arg.Pos(), "With does not wrap errors; the error is added as a !BADKEY property")
+			}
+		case isString(t):
+			if s, ok := constantString(pass, arg); ok && isTraceID(s) {
+				continue
+			}
+			if i == len(args)-1 {
+				pass.Reportf(arg.Pos(), "%s key is missing its value", name)
+				continue
+			}
+			if s, ok := constantString(pass, arg); ok && statusCodeKeys[s] && isStatusCode(pass, args[i+1]) {
+				pass.Reportf(arg.Pos(), "%q looks like a misplaced status code; pass the status code as an unnamed int", s)
+			}
+			i++
+		case isNamed(t, errorsPkgPath, "StackDepth") && !sig.with,
+			isNamed(t, errorsPkgPath, "PropertyGroup"),
+			isNamed(t, "log/slog", "Attr"),
+			isContext(t) && !sig.with:
+			// Recognized unnamed arguments
+		default:
+			pass.Reportf(arg.Pos(), "%s argument of type %s is not a string key and is added as a !BADKEY property", name, t)
+		}
+	}
+}
+
+// constantString returns the value of the expression if it is a string constant.
+func constantString(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	tv, ok := pass.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+// isStatusCode indicates if the expression is an integer constant in the range of HTTP status codes.
+func isStatusCode(pass *analysis.Pass, expr ast.Expr) bool {
+	tv, ok := pass.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.Int {
+		return false
+	}
+	n, ok := constant.Int64Val(tv.Value)
+	return ok && n >= 100 && n <= 599
+}
+
+// isUntypedInt indicates if the expression is an untyped integer constant, which defaults to int.
+func isUntypedInt(pass *analysis.Pass, expr ast.Expr) bool {
+	tv, ok := pass.TypesInfo.Types[expr]
+	if !ok {
+		return false
+	}
+	basic, ok := tv.Type.(*types.Basic)
+	return ok && basic.Kind() == types.UntypedInt
+}
+
+// isError indicates if the type implements the error interface.
+func isError(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	errorType := types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+	return types.Implements(t, errorType)
+}
+
+// isString indicates if the type is exactly string, as required by the type switch of New.
+func isString(t types.Type) bool {
+	return types.Identical(t, types.Typ[types.String]) || types.Identical(t, types.Typ[types.UntypedString])
+}
+
+// isNamed indicates if the type is the named type of the package.
+func isNamed(t types.Type, pkgPath string, name string) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == pkgPath && obj.Name() == name
+}
+
+// isContext indicates if the type implements context.Context.
+func isContext(t types.Type) bool {
+	methods := types.NewMethodSet(t)
+	for _, name := range []string{"Deadline", "Done", "Err", "Value"} {
+		if methods.Lookup(nil, name) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// isTraceID indicates if the string is a 32-character long hex string, a 16-character long hex string or a W3C traceparent,
+// which New interprets as a trace ID, a span ID or both.
+func isTraceID(s string) bool {
+	if len(s) == 55 && s[2] == '-' && s[35] == '-' && s[52] == '-' {
+		return isHex(s[:2]) && !strings.EqualFold(s[:2], "ff") && isHex(s[3:35]) && isHex(s[36:52]) && isHex(s[53:])
+	}
+	return (len(s) == 32 || len(s) == 16) && isHex(s)
+}
+
+// isHex indicates if the string consists of hex digits only.
+func isHex(s string) bool {
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// formatVerbs returns the verbs of the format pattern, in order, excluding %%.
+func formatVerbs(pattern string) []rune {
+	var verbs []rune
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '%' {
+			continue
+		}
+		i++
+		// Skip flags, argument indexes, width and precision
+		for i < len(pattern) && strings.IndexByte("+-# 0123456789.[]*", pattern[i]) >= 0 {
+			i++
+		}
+		if i >= len(pattern) {
+			break
+		}
+		if pattern[i] == '%' {
+			continue
+		}
+		verbs = append(verbs, rune(pattern[i]))
+	}
+	return verbs
+}
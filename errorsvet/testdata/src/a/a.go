@@ -0,0 +1,45 @@
+package a
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/microbus-io/errors"
+)
+
+type key string
+
+func examples(ctx context.Context, err error, id int, name string, args []any) {
+	// Well-formed
+	_ = errors.New("user %d not found", id, 404, "name", name)
+	_ = errors.New("failed", err, "0123456789abcdef0123456789abcdef", errors.StackDepth(8), ctx)
+	_ = errors.New("failed", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	_ = errors.New("failed", errors.Group("db", "id", id), slog.String("name", name))
+	_ = errors.New("failed: %w", err)
+	_ = errors.New("100%% done")
+	_ = errors.Trace(err, "id", id)
+	_ = errors.TraceIf(true, err, "id", id)
+	_ = errors.With(err, 409, "id", id)
+	_ = errors.New("failed", args...)
+
+	// Missing value
+	_ = errors.New("failed", "id", id, "name") // want `New key is missing its value`
+	_ = errors.Trace(err, "dangling")          // want `Trace key is missing its value`
+
+	// Non-string keys
+	_ = errors.New("failed", int64(5))         // want `New argument of type int64 is not a string key`
+	_ = errors.New("failed", key("k"), "v")    // want `New argument of type a.key is not a string key` `New key is missing its value`
+	_ = errors.With(err, errors.StackDepth(5)) // want `With argument of type github.com/microbus-io/errors.StackDepth is not a string key`
+	_ = errors.With(err, err)                  // want `With does not wrap errors`
+
+	// Format verbs without arguments
+	_ = errors.New("user %d not found in %s", id) // want `New pattern has 2 format verbs but 1 arguments`
+
+	// Misplaced status code
+	_ = errors.New("not found", "statusCode", 404) // want `"statusCode" looks like a misplaced status code`
+	_ = errors.New("not found", "status", "ok")
+
+	// Missing wrapping
+	_ = errors.New("failed: %v", err)      // want `error formatted with %v is not wrapped`
+	_ = errors.Sentinel("failed: %s", err) // want `error formatted with %s is not wrapped`
+}
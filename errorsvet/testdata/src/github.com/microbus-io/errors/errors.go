@@ -0,0 +1,13 @@
+// Package errors is a stub of github.com/microbus-io/errors for testing the analyzer.
+package errors
+
+type StackDepth int
+
+type PropertyGroup struct{}
+
+func New(pattern string, args ...any) error           { return nil }
+func Sentinel(pattern string, args ...any) error      { return nil }
+func Trace(err error, args ...any) error              { return nil }
+func TraceIf(cond bool, err error, args ...any) error { return nil }
+func With(err error, args ...any) error               { return nil }
+func Group(name string, args ...any) PropertyGroup    { return PropertyGroup{} }
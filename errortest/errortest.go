@@ -0,0 +1,129 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package errortest provides assertions on errors for use in tests.
+Failures are reported via t.Errorf and do not stop the test.
+Each assertion returns true if it passed, so that it can be followed by t.FailNow if needed.
+
+	err := svc.Delete(ctx, id)
+	errortest.AssertStatus(t, err, http.StatusNotFound)
+	errortest.AssertProperty(t, err, "id", id)
+*/
+package errortest
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/microbus-io/errors"
+)
+
+// AssertStatus asserts that the status code of the error is as expected.
+func AssertStatus(t testing.TB, err error, statusCode int) bool {
+	t.Helper()
+	if err == nil {
+		t.Errorf("got nil, want error with status code %d", statusCode)
+		return false
+	}
+	if got := errors.StatusCode(err); got != statusCode {
+		t.Errorf("got status code %d, want %d: %v", got, statusCode, err)
+		return false
+	}
+	return true
+}
+
+// AssertProperty asserts that the error has the property and that its value deeply equals the expected value.
+// The properties of traced errors wrapped elsewhere in the error tree are considered.
+func AssertProperty(t testing.TB, err error, key string, value any) bool {
+	t.Helper()
+	if err == nil {
+		t.Errorf("got nil, want error with property %q", key)
+		return false
+	}
+	got, ok := errors.Convert(err).Properties[key]
+	if !ok {
+		t.Errorf("property %q not found: %v", key, err)
+		return false
+	}
+	if !reflect.DeepEqual(got, value) {
+		t.Errorf("got property %q = %v (%T), want %v (%T)", key, got, got, value, value)
+		return false
+	}
+	return true
+}
+
+// AssertIs asserts that the error matches the target per errors.Is.
+func AssertIs(t testing.TB, err error, target error) bool {
+	t.Helper()
+	if !errors.Is(err, target) {
+		t.Errorf("got %v, want to match %v", err, target)
+		return false
+	}
+	return true
+}
+
+// AssertStackContains asserts that a frame of the error's stack trace is of the indicated function.
+// Functions are qualified by the last element of their package path, e.g. "http.(*Server).Serve".
+// A function matches if it equals the indicated function or ends with it, e.g. "(*Server).Serve".
+func AssertStackContains(t testing.TB, err error, function string) bool {
+	t.Helper()
+	if err == nil {
+		t.Errorf("got nil, want error with %s in its stack", function)
+		return false
+	}
+	tracedErr := errors.Convert(err)
+	for frame := range tracedErr.Frames() {
+		if frame.Function == function || strings.HasSuffix(frame.Function, "."+function) {
+			return true
+		}
+	}
+	t.Errorf("function %s not found in stack:\n%+v", function, tracedErr)
+	return false
+}
+
+// AssertJSONRoundTrip asserts that the error survives marshaling to JSON and back.
+// The error is marshaled, unmarshaled and marshaled again, and the two JSON representations are expected to be identical.
+func AssertJSONRoundTrip(t testing.TB, err error) bool {
+	t.Helper()
+	if err == nil {
+		t.Errorf("got nil, want error")
+		return false
+	}
+	original, marshalErr := json.Marshal(errors.Convert(err))
+	if marshalErr != nil {
+		t.Errorf("marshaling: %v", marshalErr)
+		return false
+	}
+	var unmarshaled errors.TracedError
+	if unmarshalErr := json.Unmarshal(original, &unmarshaled); unmarshalErr != nil {
+		t.Errorf("unmarshaling: %v", unmarshalErr)
+		return false
+	}
+	roundTripped, marshalErr := json.Marshal(&unmarshaled)
+	if marshalErr != nil {
+		t.Errorf("marshaling unmarshaled error: %v", marshalErr)
+		return false
+	}
+	if !bytes.Equal(original, roundTripped) {
+		t.Errorf("got %s after round trip, want %s", roundTripped, original)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,104 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errortest
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/microbus-io/errors"
+)
+
+// recorder records the failures reported by the assertions.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, format)
+}
+
+func TestErrorTest_Pass(t *testing.T) {
+	t.Parallel()
+
+	err := errors.New("not found", 404, fs.ErrNotExist, "key", "value", "n", 5)
+	if !AssertStatus(t, err, 404) ||
+		!AssertProperty(t, err, "key", "value") ||
+		!AssertProperty(t, err, "n", 5) ||
+		!AssertIs(t, err, fs.ErrNotExist) ||
+		!AssertStackContains(t, err, "errortest.TestErrorTest_Pass") ||
+		!AssertStackContains(t, err, "TestErrorTest_Pass") ||
+		!AssertJSONRoundTrip(t, errors.Trace(err)) {
+		t.FailNow()
+	}
+}
+
+func TestErrorTest_Fail(t *testing.T) {
+	t.Parallel()
+
+	err := errors.New("bad request", 400, "key", "value")
+	r := &recorder{}
+	if AssertStatus(r, err, 404) ||
+		AssertStatus(r, nil, 404) ||
+		AssertProperty(r, err, "key", "other") ||
+		AssertProperty(r, err, "missing", "value") ||
+		AssertIs(r, err, fs.ErrNotExist) ||
+		AssertStackContains(r, err, "Missing") ||
+		AssertStackContains(r, err, "Fail") ||
+		AssertJSONRoundTrip(r, nil) {
+		t.Errorf("assertion passed unexpectedly")
+	}
+	if len(r.failures) != 8 {
+		t.Errorf("got %d failures, want 8: %s", len(r.failures), strings.Join(r.failures, ", "))
+	}
+}
+
+func TestErrorTest_Normalize(t *testing.T) {
+	t.Parallel()
+
+	newErr := func(line int) error {
+		var err error
+		if line == 1 {
+			err = errors.New("oops", "key", "value")
+		} else {
+			err = errors.New("oops", "key", "value")
+		}
+		return errors.AddSuppressed(err, errors.New("rollback failed"))
+	}
+	err1 := newErr(1)
+	err2 := newErr(2)
+	if errors.Convert(err1).String() == errors.Convert(err2).String() {
+		t.Fatalf("expected the stack traces to differ")
+	}
+	normalized := Normalize(err1)
+	if normalized.String() != Normalize(err2).String() {
+		t.Errorf("got %s, want %s", normalized.String(), Normalize(err2).String())
+	}
+	if !strings.Contains(normalized.String(), "errortest_test.go:0") {
+		t.Errorf("unexpected normalized error %s", normalized.String())
+	}
+	if errors.Convert(err1).Stack[0].Line == 0 {
+		t.Errorf("original error was modified")
+	}
+	if Normalize(nil) != nil {
+		t.Errorf("expected nil")
+	}
+}
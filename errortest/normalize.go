@@ -0,0 +1,60 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errortest
+
+import (
+	"path"
+
+	"github.com/microbus-io/errors"
+)
+
+/*
+Normalize returns a copy of the error with the file of each stack frame replaced by its base name
+and the line number replaced by 0, recursively into suppressed errors.
+The string and JSON representations of the normalized error do not change when code is moved,
+which allows them to be compared against golden files.
+The original error is not modified.
+
+	got := errortest.Normalize(err).String()
+	if got != string(golden) {
+		...
+	}
+*/
+func Normalize(err error) *errors.TracedError {
+	if err == nil {
+		return nil
+	}
+	tracedErr := errors.Convert(err)
+	normalized := *tracedErr
+	normalized.Stack = make([]*errors.StackFrame, 0, len(tracedErr.Stack))
+	for _, frame := range tracedErr.Stack {
+		frame := *frame
+		if frame.File != "" {
+			frame.File = path.Base(frame.File)
+			frame.Line = 0
+		}
+		normalized.Stack = append(normalized.Stack, &frame)
+	}
+	normalized.Suppressed = make([]error, 0, len(tracedErr.Suppressed))
+	for _, suppressed := range tracedErr.Suppressed {
+		if _, ok := suppressed.(*errors.TracedError); ok {
+			suppressed = Normalize(suppressed)
+		}
+		normalized.Suppressed = append(normalized.Suppressed, suppressed)
+	}
+	return &normalized
+}
@@ -0,0 +1,136 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"bytes"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"unicode/utf8"
+)
+
+// maxStderrTail is the maximum length of the tail of the standard error of a process that is attached to errors.
+const maxStderrTail = 1024
+
+/*
+FromExec traces the error of running the command, attaching the details of the process failure as properties:
+
+  - "exitCode" is the exit code of the process, if it exited
+  - "signal" is the name of the signal that terminated the process, if any
+  - "argv" is the command line, with the values of arguments named by Config.RedactedProperties replaced by "!REDACTED"
+  - "stderr" is the tail of the standard error of the process, if it was captured
+
+The standard error is captured by Output, or if the Stderr of the command is a *bytes.Buffer or *strings.Builder.
+An unnamed status code, or any other argument, can be passed as with Trace.
+
+	cmd := exec.CommandContext(ctx, "git", "clone", url)
+	if _, err := cmd.Output(); err != nil {
+		return errors.FromExec(err, cmd)
+	}
+*/
+func FromExec(err error, cmd *exec.Cmd, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	var props []any
+	var stderr []byte
+	var exitErr *exec.ExitError
+	if As(err, &exitErr) && exitErr.ProcessState != nil {
+		state := exitErr.ProcessState
+		if code := state.ExitCode(); code >= 0 {
+			props = append(props, "exitCode", code)
+		}
+		if ws, ok := state.Sys().(interface {
+			Signaled() bool
+			Signal() syscall.Signal
+		}); ok && ws.Signaled() {
+			props = append(props, "signal", ws.Signal().String())
+		}
+		stderr = exitErr.Stderr
+	}
+	if cmd != nil {
+		if len(cmd.Args) > 0 {
+			props = append(props, "argv", redactArgs(cmd.Args))
+		} else if cmd.Path != "" {
+			props = append(props, "argv", cmd.Path)
+		}
+		if len(stderr) == 0 {
+			switch w := cmd.Stderr.(type) {
+			case *bytes.Buffer:
+				stderr = w.Bytes()
+			case *strings.Builder:
+				stderr = []byte(w.String())
+			}
+		}
+	}
+	if tail := stderrTail(stderr); tail != "" {
+		props = append(props, "stderr", tail)
+	}
+	return Trace(err, append(props, args...)...)
+}
+
+// redactArgs returns the command line, with the values of arguments named by the redacted properties replaced by "!REDACTED".
+// Both the --name=value and the --name value forms are recognized, with any number of leading dashes.
+func redactArgs(argv []string) string {
+	redacted := loadSettings().redactedProperties
+	isRedacted := func(arg string) bool {
+		name := strings.TrimLeft(arg, "-")
+		if name == arg || name == "" {
+			return false
+		}
+		for _, r := range redacted {
+			if strings.EqualFold(name, r) {
+				return true
+			}
+		}
+		return false
+	}
+	out := make([]string, len(argv))
+	copy(out, argv)
+	for i := 1; i < len(out); i++ {
+		if name, _, ok := strings.Cut(out[i], "="); ok && isRedacted(name) {
+			out[i] = name + "=!REDACTED"
+		} else if isRedacted(out[i]) && i+1 < len(out) {
+			out[i+1] = "!REDACTED"
+			i++
+		}
+	}
+	for i, arg := range out {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'") {
+			out[i] = strconv.Quote(arg)
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+// stderrTail returns the trimmed tail of the standard error of a process, limited to the last lines that fit the maximum length.
+func stderrTail(stderr []byte) string {
+	tail := strings.TrimSpace(string(stderr))
+	if len(tail) <= maxStderrTail {
+		return tail
+	}
+	tail = tail[len(tail)-maxStderrTail:]
+	if p := strings.IndexByte(tail, '\n'); p >= 0 && p < len(tail)-1 {
+		tail = tail[p+1:]
+	}
+	for len(tail) > 0 && !utf8.RuneStart(tail[0]) {
+		tail = tail[1:]
+	}
+	return "..." + tail
+}
@@ -0,0 +1,84 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"bytes"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestErrors_FromExec(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+
+	assertNil(t, FromExec(nil, nil))
+
+	cmd := exec.Command("sh", "-c", "echo line1 >&2; echo line2 >&2; exit 3")
+	_, err := cmd.Output()
+	err = FromExec(err, cmd, 502)
+	assertError(t, err)
+	assertEqual(t, 502, StatusCode(err))
+	props := Convert(err).Properties
+	assertEqual(t, 3, props["exitCode"])
+	assertEqual(t, "line1\nline2", props["stderr"])
+	assertEqual(t, `sh -c "echo line1 >&2; echo line2 >&2; exit 3"`, props["argv"])
+	_, ok := props["signal"]
+	assertTrue(t, !ok)
+
+	// Stderr captured in a buffer
+	var stderr bytes.Buffer
+	cmd = exec.Command("sh", "-c", "echo oops >&2; kill -TERM $$")
+	cmd.Stderr = &stderr
+	err = FromExec(cmd.Run(), cmd)
+	props = Convert(err).Properties
+	assertEqual(t, "oops", props["stderr"])
+	assertEqual(t, "terminated", props["signal"])
+
+	// Command not found
+	cmd = exec.Command("no-such-command-for-sure")
+	err = FromExec(cmd.Run(), cmd)
+	assertError(t, err)
+	assertEqual(t, "no-such-command-for-sure", Convert(err).Properties["argv"])
+}
+
+func TestErrors_FromExecRedaction(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	defer Configure(Config{})
+	Configure(Config{RedactedProperties: []string{"password", "token"}})
+
+	argv := redactArgs([]string{"db", "--user", "admin", "--password", "secret", "-token=abc", "password", "x"})
+	assertEqual(t, "db --user admin --password !REDACTED -token=!REDACTED password x", argv)
+}
+
+func TestErrors_StderrTail(t *testing.T) {
+	t.Parallel()
+
+	assertEqual(t, "", stderrTail(nil))
+	assertEqual(t, "short", stderrTail([]byte("  short\n")))
+
+	long := strings.Repeat("x", 2000) + "\nlast line\n"
+	tail := stderrTail([]byte(long))
+	assertTrue(t, len(tail) <= maxStderrTail+3)
+	assertTrue(t, strings.HasSuffix(tail, "last line"))
+	assertTrue(t, strings.HasPrefix(tail, "..."))
+}
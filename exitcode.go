@@ -0,0 +1,123 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// exitCodeMapping maps errors that match the target to an exit code.
+type exitCodeMapping struct {
+	target   any
+	exitCode int
+}
+
+var (
+	exitCodes    []*exitCodeMapping
+	exitCodesMux sync.RWMutex
+
+	// osExit and fatalWriter are replaced in tests
+	osExit                = os.Exit
+	fatalWriter io.Writer = os.Stderr
+)
+
+/*
+RegisterExitCode registers the process exit code of errors that match the target.
+The target is either an int status code, a string error code matched against the "code" property, or an error matched per Is.
+Mappings are consulted in the order of their registration.
+The returned function unregisters the mapping, for example at the end of a test.
+
+	errors.RegisterExitCode(http.StatusBadRequest, 2)
+	errors.RegisterExitCode("config.invalid", 78)
+	errors.RegisterExitCode(context.Canceled, 130)
+*/
+func RegisterExitCode(target any, exitCode int) (unregister func()) {
+	switch target.(type) {
+	case int, string, error:
+	default:
+		return func() {}
+	}
+	entry := &exitCodeMapping{target: target, exitCode: exitCode}
+	exitCodesMux.Lock()
+	exitCodes = append(exitCodes, entry)
+	exitCodesMux.Unlock()
+	return func() {
+		exitCodesMux.Lock()
+		exitCodes = withoutEntry(exitCodes, entry)
+		exitCodesMux.Unlock()
+	}
+}
+
+// ExitCode returns the process exit code for the error.
+// A nil error results in 0. Otherwise, the registered mappings are consulted first,
+// followed by any error in the tree that reports its own exit code, such as *exec.ExitError.
+// The default is 1.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	exitCodesMux.RLock()
+	mappings := exitCodes
+	exitCodesMux.RUnlock()
+	if len(mappings) > 0 {
+		tracedErr := Convert(err)
+		for _, m := range mappings {
+			switch target := m.target.(type) {
+			case int:
+				if tracedErr.StatusCode == target {
+					return m.exitCode
+				}
+			case string:
+				if code, ok := tracedErr.Properties["code"]; ok && fmt.Sprintf("%v", code) == target {
+					return m.exitCode
+				}
+			case error:
+				if Is(err, target) {
+					return m.exitCode
+				}
+			}
+		}
+	}
+	if exitErr, ok := Find[interface {
+		error
+		ExitCode() int
+	}](err); ok {
+		if code := exitErr.ExitCode(); code > 0 {
+			return code
+		}
+	}
+	return 1
+}
+
+/*
+Fatal prints the error to stderr in a human-readable form and exits the process with the exit code of the error.
+The stack trace is omitted unless requested by the options, typically based on a verbosity flag.
+It does nothing if the error is nil.
+
+	err := run()
+	errors.Fatal(err, errors.WithStack(*verbose))
+*/
+func Fatal(err error, opts ...PrintOption) {
+	if err == nil {
+		return
+	}
+	Fprint(fatalWriter, err, append([]PrintOption{WithStack(false)}, opts...)...)
+	osExit(ExitCode(err))
+}
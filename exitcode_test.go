@@ -0,0 +1,73 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"bytes"
+	"os/exec"
+	"testing"
+)
+
+func TestErrors_ExitCode(t *testing.T) {
+	t.Parallel()
+
+	sentinel := New("exit code sentinel")
+	defer RegisterExitCode(499, 130)()
+	defer RegisterExitCode("exitcode.test", 78)()
+	unregister := RegisterExitCode(sentinel, 3)
+	defer RegisterExitCode(1.5, 9)()
+
+	assertEqual(t, 0, ExitCode(nil))
+	assertEqual(t, 1, ExitCode(New("oops")))
+	assertEqual(t, 130, ExitCode(New("canceled", 499)))
+	assertEqual(t, 78, ExitCode(New("bad config", "code", "exitcode.test")))
+	assertEqual(t, 3, ExitCode(New("wrapped", sentinel)))
+	unregister()
+	assertEqual(t, 1, ExitCode(New("wrapped", sentinel)))
+
+	// Errors that report their own exit code
+	cmdErr := exec.Command("sh", "-c", "exit 7").Run()
+	if cmdErr != nil {
+		assertEqual(t, 7, ExitCode(Trace(cmdErr)))
+	}
+}
+
+func TestErrors_Fatal(t *testing.T) {
+	// Not parallel because it replaces the exit function
+
+	var exitCode int
+	var buf bytes.Buffer
+	originalExit, originalWriter := osExit, fatalWriter
+	osExit = func(code int) { exitCode = code }
+	fatalWriter = &buf
+	defer func() {
+		osExit, fatalWriter = originalExit, originalWriter
+	}()
+
+	Fatal(nil)
+	assertEqual(t, 0, buf.Len())
+
+	Fatal(New("cannot open config", 404, "file", "app.yaml"))
+	assertEqual(t, 1, exitCode)
+	assertContains(t, buf.String(), "cannot open config")
+	assertContains(t, buf.String(), "file=app.yaml")
+	assertTrue(t, !bytes.Contains(buf.Bytes(), []byte("exitcode_test.go")))
+
+	buf.Reset()
+	Fatal(New("oops"), WithStack(true))
+	assertContains(t, buf.String(), "exitcode_test.go")
+}
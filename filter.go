@@ -0,0 +1,97 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"path"
+	"strings"
+)
+
+// StackFilter determines whether a stack frame is captured.
+// It returns true to keep the frame, or false to skip it.
+type StackFilter func(frame StackFrame) bool
+
+/*
+SetStackFilter sets the filters that determine which stack frames are captured from this point on.
+A frame is captured only if all filters keep it.
+The default filter is SkipRuntime.
+Frames of this package are always skipped.
+
+	errors.SetStackFilter(
+		errors.SkipRuntime,
+		errors.SkipVendored,
+		errors.SkipGenerated,
+		errors.SkipPackages("connector.", "httpingress."),
+	)
+*/
+func SetStackFilter(filters ...StackFilter) {
+	var nonNil []StackFilter
+	for _, f := range filters {
+		if f != nil {
+			nonNil = append(nonNil, f)
+		}
+	}
+	updateSettings(func(s *settings) {
+		s.stackFilters = nonNil
+	})
+}
+
+// SkipRuntime skips frames of the Go runtime.
+func SkipRuntime(frame StackFrame) bool {
+	return !strings.HasPrefix(frame.Function, "runtime.")
+}
+
+// SkipVendored skips frames of vendored packages.
+func SkipVendored(frame StackFrame) bool {
+	return !strings.Contains(frame.File, "/vendor/") && !strings.HasPrefix(frame.File, "vendor/")
+}
+
+// SkipGenerated skips frames of generated code, as identified by common file naming conventions.
+func SkipGenerated(frame StackFrame) bool {
+	name := path.Base(frame.File)
+	return !strings.HasSuffix(name, ".pb.go") &&
+		!strings.HasSuffix(name, "_gen.go") &&
+		!strings.HasSuffix(name, ".gen.go") &&
+		!strings.HasSuffix(name, "_generated.go") &&
+		!strings.HasPrefix(name, "zz_generated")
+}
+
+// SkipPackages returns a filter that skips frames whose function starts with any of the prefixes.
+// Function names are qualified by the last element of their package path, e.g. connector.(*Connector).Publish
+func SkipPackages(prefixes ...string) StackFilter {
+	return func(frame StackFrame) bool {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(frame.Function, prefix) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// keepFrame determines whether a stack frame should be captured.
+func keepFrame(frame StackFrame) bool {
+	if strings.HasPrefix(frame.Function, "errors.") && !strings.HasPrefix(frame.Function, "errors.Test") {
+		return false
+	}
+	for _, f := range loadSettings().stackFilters {
+		if !f(frame) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	stderrors "errors"
+	"testing"
+)
+
+func TestErrors_SetStackFilter(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	SetStackFilter(SkipRuntime, SkipPackages("testing."))
+	defer SetStackFilter(SkipRuntime)
+
+	err := traceFull(stderrors.New("oops"), 0)
+	stack := Convert(err).Stack
+	assertEqual(t, 1, len(stack))
+	assertEqual(t, "errors.TestErrors_SetStackFilter", stack[0].Function)
+
+	// Frames of this package are always skipped
+	SetStackFilter()
+	err = New("oops")
+	assertEqual(t, "errors.TestErrors_SetStackFilter", Convert(err).Stack[0].Function)
+}
+
+func TestErrors_BuiltInStackFilters(t *testing.T) {
+	t.Parallel()
+
+	assertTrue(t, !SkipRuntime(StackFrame{Function: "runtime.gopanic"}))
+	assertTrue(t, SkipRuntime(StackFrame{Function: "main.main"}))
+
+	assertTrue(t, !SkipVendored(StackFrame{File: "/src/my-app/vendor/github.com/dep/pkg/file.go"}))
+	assertTrue(t, !SkipVendored(StackFrame{File: "vendor/github.com/dep/pkg/file.go"}))
+	assertTrue(t, SkipVendored(StackFrame{File: "/src/my-app/service/file.go"}))
+
+	assertTrue(t, !SkipGenerated(StackFrame{File: "/src/my-app/api/service.pb.go"}))
+	assertTrue(t, !SkipGenerated(StackFrame{File: "/src/my-app/api/client_gen.go"}))
+	assertTrue(t, !SkipGenerated(StackFrame{File: "/src/my-app/api/zz_generated.deepcopy.go"}))
+	assertTrue(t, SkipGenerated(StackFrame{File: "/src/my-app/api/service.go"}))
+
+	skip := SkipPackages("connector.", "httpingress.")
+	assertTrue(t, !skip(StackFrame{Function: "connector.(*Connector).Publish"}))
+	assertTrue(t, !skip(StackFrame{Function: "httpingress.(*Service).ServeHTTP"}))
+	assertTrue(t, skip(StackFrame{Function: "calculator.(*Service).Square"}))
+}
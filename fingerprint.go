@@ -0,0 +1,60 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+)
+
+// Hash64 returns a fingerprint of the error that is stable for as long as the code does not change.
+// Errors that originate from the same locations in the code and have the same status code and "code" property
+// have the same fingerprint, even if their messages or other properties differ.
+// Errors with no stack trace, such as sentinel errors, are fingerprinted by their message instead.
+// Repeat counts and omitted frames are not considered.
+func (e *TracedError) Hash64() uint64 {
+	h := fnv.New64a()
+	write := func(s string) {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	write(strconv.Itoa(e.StatusCode))
+	if code, ok := e.Properties["code"]; ok {
+		write(fmt.Sprintf("%v", code))
+	}
+	var located bool
+	for _, frame := range e.Stack {
+		if frame.isPseudo() {
+			continue
+		}
+		write(frame.Function)
+		write(frame.File)
+		write(strconv.Itoa(frame.Line))
+		located = true
+	}
+	if !located {
+		write(e.Error())
+	}
+	return h.Sum64()
+}
+
+// Key returns the fingerprint of the error as a string of 16 hex digits, for use as a key of maps, counters and caches.
+// See Hash64 for what errors share a fingerprint.
+func (e *TracedError) Key() string {
+	return fmt.Sprintf("%016x", e.Hash64())
+}
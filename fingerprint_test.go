@@ -0,0 +1,77 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestErrors_Key(t *testing.T) {
+	t.Parallel()
+
+	newErr := func(id int) *TracedError {
+		return Convert(New("user %d not found", id, 404, "code", "USER_NOT_FOUND", "id", id))
+	}
+	a := newErr(1)
+	b := newErr(2)
+	assertEqual(t, a.Hash64(), b.Hash64())
+	assertEqual(t, a.Key(), b.Key())
+	assertEqual(t, 16, len(a.Key()))
+
+	// Repeat counts do not change the fingerprint
+	traceN := func(err error, n int) *TracedError {
+		for range n {
+			err = Trace(err)
+		}
+		return Convert(err)
+	}
+	c := traceN(a, 1)
+	assertEqual(t, c.Key(), traceN(b, 3).Key())
+	assertNotEqual(t, a.Key(), c.Key())
+
+	// Different origin
+	d := Convert(New("user %d not found", 1, 404, "code", "USER_NOT_FOUND"))
+	assertNotEqual(t, a.Key(), d.Key())
+
+	// Different status code or code property
+	e := Convert(With(a, 410))
+	assertNotEqual(t, a.Key(), e.Key())
+	f := Convert(With(a, "code", "GONE"))
+	assertNotEqual(t, a.Key(), f.Key())
+
+	// Round trip through JSON
+	data, err := json.Marshal(a)
+	assertNil(t, err)
+	var unmarshaled TracedError
+	err = json.Unmarshal(data, &unmarshaled)
+	assertNil(t, err)
+	assertEqual(t, a.Key(), unmarshaled.Key())
+
+	// Sentinels are fingerprinted by their message
+	s1 := Convert(Sentinel("not found", 404))
+	s2 := Convert(Sentinel("not found", 404))
+	s3 := Convert(Sentinel("gone", 404))
+	assertEqual(t, s1.Key(), s2.Key())
+	assertNotEqual(t, s1.Key(), s3.Key())
+
+	counts := map[string]int{}
+	for i := range 5 {
+		counts[newErr(i).Key()]++
+	}
+	assertEqual(t, 1, len(counts))
+}
@@ -0,0 +1,59 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import "iter"
+
+/*
+Frames iterates over the stack frames of the error, from the origin of the error to the last location it was traced.
+Pseudo frames that stand for omitted frames or that mark boundaries are skipped.
+The frames are copies and modifying them does not affect the error.
+
+	for frame := range tracedErr.Frames() {
+		fmt.Println(frame.Function, frame.File, frame.Line)
+	}
+*/
+func (e *TracedError) Frames() iter.Seq[StackFrame] {
+	return func(yield func(StackFrame) bool) {
+		for _, frame := range e.Stack {
+			if frame == nil || frame.isPseudo() {
+				continue
+			}
+			if !yield(*frame) {
+				return
+			}
+		}
+	}
+}
+
+// OriginFrame returns the first stack frame of the error, which is typically where the error was created.
+func (e *TracedError) OriginFrame() (StackFrame, bool) {
+	for frame := range e.Frames() {
+		return frame, true
+	}
+	return StackFrame{}, false
+}
+
+// LastFrame returns the last stack frame of the error, which is typically the last location where the error was traced.
+func (e *TracedError) LastFrame() (StackFrame, bool) {
+	for i := len(e.Stack) - 1; i >= 0; i-- {
+		if frame := e.Stack[i]; frame != nil && !frame.isPseudo() {
+			return *frame, true
+		}
+	}
+	return StackFrame{}, false
+}
@@ -0,0 +1,70 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestErrors_Frames(t *testing.T) {
+	t.Parallel()
+
+	tracedErr := &TracedError{
+		Err: New("oops"),
+		Stack: []*StackFrame{
+			{Function: "origin", File: "origin.go", Line: 1},
+			elisionFrame(3),
+			{Function: asyncBoundary},
+			{Function: "middle", File: "middle.go", Line: 2},
+			{Function: "last", File: "last.go", Line: 3},
+			elisionFrame(1),
+		},
+	}
+	var functions []string
+	for frame := range tracedErr.Frames() {
+		functions = append(functions, frame.Function)
+	}
+	assertEqual(t, "origin,middle,last", strings.Join(functions, ","))
+
+	// Early termination
+	n := 0
+	for range tracedErr.Frames() {
+		n++
+		break
+	}
+	assertEqual(t, 1, n)
+
+	frame, ok := tracedErr.OriginFrame()
+	assertTrue(t, ok)
+	assertEqual(t, "origin", frame.Function)
+	frame, ok = tracedErr.LastFrame()
+	assertTrue(t, ok)
+	assertEqual(t, "last", frame.Function)
+	assertEqual(t, 3, frame.Line)
+
+	// Copies
+	frame.Line = 100
+	assertEqual(t, 3, tracedErr.Stack[4].Line)
+
+	// No stack
+	tracedErr = &TracedError{Err: New("oops")}
+	_, ok = tracedErr.OriginFrame()
+	assertTrue(t, !ok)
+	_, ok = tracedErr.LastFrame()
+	assertTrue(t, !ok)
+}
@@ -0,0 +1,40 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/gob"
+)
+
+func init() {
+	// Allow traced errors to be sent in fields of type error
+	gob.Register(&TracedError{})
+}
+
+// GobEncode encodes the error for gob.
+// The wrapped error is flattened to its message, in the same manner as the JSON encoding.
+// When sending an arbitrary error in a field of type error, Convert it first to a traced error,
+// because gob is only able to encode registered types.
+func (e *TracedError) GobEncode() ([]byte, error) {
+	return e.MarshalBinary()
+}
+
+// GobDecode decodes the error from gob.
+// Neither the type of the error nor any errors it wraps can be restored.
+func (e *TracedError) GobDecode(data []byte) error {
+	return e.UnmarshalBinary(data)
+}
@@ -0,0 +1,65 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+)
+
+func TestErrors_Gob(t *testing.T) {
+	t.Parallel()
+
+	type reply struct {
+		Value int
+		Err   error
+	}
+
+	err := New("oops", 409, "00-0123456789abcdef0123456789abcdef-0123456789abcdef-01", "key", "value")
+	err = AddSuppressed(err, New("rollback failed"))
+	original := Convert(err)
+
+	var buf bytes.Buffer
+	encErr := gob.NewEncoder(&buf).Encode(reply{Value: 1, Err: err})
+	assertNil(t, encErr)
+
+	var decoded reply
+	encErr = gob.NewDecoder(&buf).Decode(&decoded)
+	assertNil(t, encErr)
+	assertEqual(t, 1, decoded.Value)
+	assertError(t, decoded.Err)
+	tracedErr, ok := decoded.Err.(*TracedError)
+	assertTrue(t, ok)
+	assertEqual(t, "oops", tracedErr.Error())
+	assertEqual(t, 409, tracedErr.StatusCode)
+	assertEqual(t, "0123456789abcdef0123456789abcdef", tracedErr.Trace)
+	assertEqual(t, "0123456789abcdef", tracedErr.Span)
+	assertEqual(t, "value", tracedErr.Properties["key"])
+	assertEqual(t, original.Stack, tracedErr.Stack)
+	assertEqual(t, 1, len(tracedErr.Suppressed))
+
+	// Directly as a value
+	buf.Reset()
+	encErr = gob.NewEncoder(&buf).Encode(original)
+	assertNil(t, encErr)
+	var direct TracedError
+	encErr = gob.NewDecoder(&buf).Decode(&direct)
+	assertNil(t, encErr)
+	assertEqual(t, "oops", direct.Error())
+	assertEqual(t, 409, direct.StatusCode)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"strings"
+)
+
+// GraphQLError is an error in the shape of the GraphQL specification.
+type GraphQLError struct {
+	Message    string         `json:"message"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+// Error returns the message of the error.
+func (g *GraphQLError) Error() string {
+	return g.Message
+}
+
+/*
+ToGraphQLError converts the error to the shape of the GraphQL specification.
+The extensions include the error code, status code, trace ID and properties of the error.
+The error code is taken from the "code" property of the error, if present,
+or is otherwise derived from the status code, e.g. NOT_FOUND or INTERNAL_SERVER_ERROR.
+The stack trace is included only in debug mode.
+The optional path elements are strings for field names and ints for list indices.
+
+	gqlErr := errors.ToGraphQLError(err, "user", 0, "name")
+*/
+func ToGraphQLError(err error, path ...any) *GraphQLError {
+	if err == nil {
+		return nil
+	}
+	tracedErr := Convert(err).scrubbed()
+	ext := map[string]any{
+		"statusCode": tracedErr.StatusCode,
+	}
+	if code, ok := tracedErr.Properties["code"]; ok {
+		ext["code"] = fmt.Sprintf("%v", code)
+	} else if text := http.StatusText(tracedErr.StatusCode); text != "" {
+		ext["code"] = strings.ToUpper(strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(text))
+	}
+	if tracedErr.Trace != "" && tracedErr.Trace != zeroTrace {
+		ext["trace"] = tracedErr.Trace
+	}
+	if len(tracedErr.Properties) > 0 {
+		props := maps.Clone(tracedErr.Properties)
+		delete(props, "code")
+		if len(props) > 0 {
+			ext["properties"] = props
+		}
+	}
+	if loadSettings().debugMode && len(tracedErr.Stack) > 0 {
+		ext["stack"] = tracedErr.Stack
+	}
+	gqlErr := &GraphQLError{
+		Message:    tracedErr.Error(),
+		Extensions: ext,
+	}
+	if len(path) > 0 {
+		gqlErr.Path = path
+	}
+	return gqlErr
+}
@@ -0,0 +1,58 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestErrors_ToGraphQLError(t *testing.T) {
+	// Not parallel because it modifies the package settings
+
+	err := New("user not found", 404, "0123456789abcdef0123456789abcdef", "userID", 123)
+	gqlErr := ToGraphQLError(err, "user", 0, "name")
+	assertEqual(t, "user not found", gqlErr.Error())
+	assertEqual(t, []any{"user", 0, "name"}, gqlErr.Path)
+	assertEqual(t, "NOT_FOUND", gqlErr.Extensions["code"])
+	assertEqual(t, 404, gqlErr.Extensions["statusCode"])
+	assertEqual(t, "0123456789abcdef0123456789abcdef", gqlErr.Extensions["trace"])
+	assertEqual(t, map[string]any{"userID": 123}, gqlErr.Extensions["properties"])
+	_, ok := gqlErr.Extensions["stack"]
+	assertTrue(t, !ok)
+
+	b, _ := json.Marshal(gqlErr)
+	assertContains(t, string(b), `"message":"user not found","path":["user",0,"name"],"extensions":{`)
+
+	// Explicit error code
+	gqlErr = ToGraphQLError(New("bad input", 400, "code", "BAD_USER_INPUT"))
+	assertEqual(t, "BAD_USER_INPUT", gqlErr.Extensions["code"])
+	assertNil(t, gqlErr.Path)
+	_, ok = gqlErr.Extensions["properties"]
+	assertTrue(t, !ok)
+
+	// Stack in debug mode
+	SetDebugMode(true)
+	defer SetDebugMode(false)
+	gqlErr = ToGraphQLError(New("oops"))
+	assertEqual(t, "INTERNAL_SERVER_ERROR", gqlErr.Extensions["code"])
+	stack, ok := gqlErr.Extensions["stack"].([]*StackFrame)
+	assertTrue(t, ok)
+	assertTrue(t, len(stack) > 0)
+
+	assertNil(t, ToGraphQLError(nil))
+}
@@ -0,0 +1,126 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"fmt"
+	"iter"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// GroupedProperties are properties that are namespaced under the name of a group in the property bag of an error.
+// They are marshaled to JSON as a nested object and are flattened to dotted names, e.g. db.query, in String.
+type GroupedProperties map[string]any
+
+// PropertyGroup is a group of properties to be namespaced under a name, as created by Group.
+type PropertyGroup struct {
+	Name       string
+	Properties GroupedProperties
+}
+
+/*
+Group creates a group of properties that are namespaced under the name, so that properties of independently-written layers
+do not overwrite each other. The arguments are name=value pairs, slog attributes, or nested groups.
+Groups of the same name are merged when an error is wrapped.
+
+	return errors.Trace(err, errors.Group("db", "query", q, "rows", n))
+*/
+func Group(name string, args ...any) PropertyGroup {
+	g := PropertyGroup{
+		Name:       name,
+		Properties: GroupedProperties{},
+	}
+	for i := 0; i < len(args); i++ {
+		switch k := args[i].(type) {
+		case PropertyGroup:
+			mergeGroup(g.Properties, k)
+		case slog.Attr:
+			foldSlogAttr(g.Properties, k)
+		case string:
+			if i < len(args)-1 {
+				g.Properties[k] = args[i+1]
+				i++
+			} else {
+				g.Properties[k] = ""
+			}
+		default:
+			g.Properties["!BADKEY"] = k
+		}
+	}
+	return g
+}
+
+// mergeGroup merges the group into the properties, without modifying a group of the same name that is already in the properties.
+func mergeGroup(props map[string]any, g PropertyGroup) {
+	merged := GroupedProperties{}
+	if existing, ok := props[g.Name].(GroupedProperties); ok {
+		maps.Copy(merged, existing)
+	}
+	for k, v := range g.Properties {
+		if nested, ok := v.(GroupedProperties); ok {
+			mergeGroup(merged, PropertyGroup{Name: k, Properties: nested})
+		} else {
+			merged[k] = v
+		}
+	}
+	props[g.Name] = merged
+}
+
+// LogValue returns the grouped properties as a slog group.
+func (g GroupedProperties) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, len(g))
+	for _, k := range slices.Sorted(maps.Keys(g)) {
+		attrs = append(attrs, slog.Any(k, g[k]))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// String returns the grouped properties as sorted dotted name=value pairs.
+func (g GroupedProperties) String() string {
+	var b strings.Builder
+	b.WriteString("{")
+	for k, v := range flatProperties(g) {
+		if b.Len() > 1 {
+			b.WriteString(" ")
+		}
+		fmt.Fprintf(&b, "%s=%v", k, v)
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// flatProperties iterates over the properties in order of their names, flattening grouped properties to dotted names.
+func flatProperties(props map[string]any) iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		for _, k := range slices.Sorted(maps.Keys(props)) {
+			if g, ok := props[k].(GroupedProperties); ok {
+				for nk, nv := range flatProperties(g) {
+					if !yield(k+"."+nk, nv) {
+						return
+					}
+				}
+				continue
+			}
+			if !yield(k, props[k]) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestErrors_Group(t *testing.T) {
+	t.Parallel()
+
+	err := New("query failed", Group("db", "id", 1, "query", "SELECT 1"), "id", "top")
+	err = Trace(err, Group("http", "id", 2))
+	err = With(err, Group("db", "rows", 5, Group("conn", "host", "localhost")))
+	tracedErr := Convert(err)
+	assertEqual(t, "top", tracedErr.Properties["id"])
+	db := tracedErr.Properties["db"].(GroupedProperties)
+	assertEqual(t, 1, db["id"])
+	assertEqual(t, "SELECT 1", db["query"])
+	assertEqual(t, 5, db["rows"])
+	assertEqual(t, "localhost", db["conn"].(GroupedProperties)["host"])
+	assertEqual(t, 2, tracedErr.Properties["http"].(GroupedProperties)["id"])
+
+	// Flat outputs
+	s := tracedErr.String()
+	assertContains(t, s, "\ndb.conn.host=localhost\ndb.id=1\ndb.query=SELECT 1\ndb.rows=5\nhttp.id=2\nid=top")
+
+	// JSON
+	b, jsonErr := json.Marshal(err)
+	assertNil(t, jsonErr)
+	var m map[string]any
+	assertNil(t, json.Unmarshal(b, &m))
+	assertEqual(t, 1.0, m["db"].(map[string]any)["id"])
+	assertEqual(t, "localhost", m["db"].(map[string]any)["conn"].(map[string]any)["host"])
+
+	// slog
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("failed", "db", db)
+	assertContains(t, buf.String(), "db.conn.host=localhost db.id=1")
+
+	// The group of the wrapped error is not modified
+	inner := Group("db", "id", 1)
+	err = New("inner", inner)
+	_ = With(err, Group("db", "id", 2))
+	assertEqual(t, 1, Convert(err).Properties["db"].(GroupedProperties)["id"])
+	assertEqual(t, 1, inner.Properties["id"])
+
+	// Bad keys
+	g := Group("g", 1, "dangling")
+	assertEqual(t, 1, g.Properties["!BADKEY"])
+	assertEqual(t, "", g.Properties["dangling"])
+	assertEqual(t, "{!BADKEY=1 dangling=}", g.Properties.String())
+}
@@ -0,0 +1,14 @@
+module github.com/microbus-io/errors/grpcadapter
+
+go 1.24.3
+
+require (
+	github.com/microbus-io/errors v0.0.0-00010101000000-000000000000
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240528184218-531527333157
+	google.golang.org/grpc v1.64.0
+	google.golang.org/protobuf v1.34.1
+)
+
+require golang.org/x/sys v0.18.0 // indirect
+
+replace github.com/microbus-io/errors => ../
@@ -0,0 +1,206 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package grpcadapter converts traced errors to and from gRPC statuses,
+mapping their structured parts to the standard google.rpc error details.
+
+	if err != nil {
+		return nil, grpcadapter.ToGRPCStatus(err).Err()
+	}
+*/
+package grpcadapter
+
+import (
+	stderrors "errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/errors/internal/rpccode"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Well-known properties that are mapped to error details
+const (
+	propCode            = "code"
+	propDomain          = "domain"
+	propField           = "field"
+	propFieldViolations = "fieldViolations"
+	propRetryable       = "retryable"
+	propRetryAfter      = "retryAfter"
+)
+
+/*
+ToGRPCStatus converts the error to a gRPC status.
+The status code of the error is mapped to a gRPC code and its structured parts are mapped to error details:
+
+  - Errors in the tree that have a "field" property, as well as the "fieldViolations" map property, map to BadRequest.FieldViolation
+  - The "retryAfter" property, or a true "retryable" property, maps to RetryInfo
+  - The "code" property maps to ErrorInfo, along with the "domain" property and the remaining properties as metadata
+  - The trace ID maps to RequestInfo
+*/
+func ToGRPCStatus(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+	tracedErr := errors.Convert(err)
+	st := status.New(codes.Code(rpccode.FromHTTP(tracedErr.StatusCode)), tracedErr.Error())
+	props := maps.Clone(tracedErr.Properties)
+	var details []protoadapt.MessageV1
+
+	// Field violations
+	var violations []*errdetails.BadRequest_FieldViolation
+	if m, ok := props[propFieldViolations].(map[string]string); ok {
+		for _, field := range slices.Sorted(maps.Keys(m)) {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       field,
+				Description: m[field],
+			})
+		}
+	}
+	delete(props, propFieldViolations)
+	delete(props, propField)
+	errors.Walk(err, func(e error) bool {
+		if t, ok := e.(*errors.TracedError); ok {
+			if field, ok := t.Properties[propField]; ok {
+				violations = append(violations, &errdetails.BadRequest_FieldViolation{
+					Field:       fmt.Sprintf("%v", field),
+					Description: t.Error(),
+				})
+			}
+		}
+		return true
+	})
+	if len(violations) > 0 {
+		details = append(details, &errdetails.BadRequest{FieldViolations: violations})
+	}
+
+	// Retryability
+	retryAfter, hasRetryAfter := toDuration(props[propRetryAfter])
+	retryable, _ := props[propRetryable].(bool)
+	if hasRetryAfter || retryable {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)})
+	}
+	delete(props, propRetryAfter)
+	delete(props, propRetryable)
+
+	// Error code
+	if code, ok := props[propCode]; ok {
+		info := &errdetails.ErrorInfo{
+			Reason: fmt.Sprintf("%v", code),
+		}
+		if domain, ok := props[propDomain]; ok {
+			info.Domain = fmt.Sprintf("%v", domain)
+		}
+		delete(props, propCode)
+		delete(props, propDomain)
+		if len(props) > 0 {
+			info.Metadata = make(map[string]string, len(props))
+			for k, v := range props {
+				info.Metadata[k] = fmt.Sprintf("%v", v)
+			}
+		}
+		details = append(details, info)
+	}
+
+	// Trace ID
+	if strings.Trim(tracedErr.Trace, "0") != "" {
+		details = append(details, &errdetails.RequestInfo{RequestId: tracedErr.Trace})
+	}
+
+	if len(details) > 0 {
+		withDetails, detailsErr := st.WithDetails(details...)
+		if detailsErr == nil {
+			st = withDetails
+		}
+	}
+	return st
+}
+
+// FromGRPCStatus converts a gRPC status to a traced error, restoring the structured parts mapped by ToGRPCStatus
+// from the error details. The error is attributed to the upstream origin. It returns nil if the status code is OK.
+func FromGRPCStatus(st *status.Status) error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+	tracedErr := &errors.TracedError{
+		Err:        stderrors.New(st.Message()),
+		StatusCode: rpccode.ToHTTP(uint32(st.Code())),
+		Origin:     errors.OriginUpstream,
+	}
+	props := map[string]any{}
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.BadRequest:
+			violations := map[string]string{}
+			for _, v := range d.GetFieldViolations() {
+				violations[v.GetField()] = v.GetDescription()
+			}
+			if len(violations) > 0 {
+				props[propFieldViolations] = violations
+			}
+		case *errdetails.RetryInfo:
+			props[propRetryable] = true
+			if d.GetRetryDelay() != nil {
+				props[propRetryAfter] = d.GetRetryDelay().AsDuration()
+			}
+		case *errdetails.ErrorInfo:
+			for k, v := range d.GetMetadata() {
+				props[k] = v
+			}
+			props[propCode] = d.GetReason()
+			if d.GetDomain() != "" {
+				props[propDomain] = d.GetDomain()
+			}
+		case *errdetails.RequestInfo:
+			tracedErr.Trace = d.GetRequestId()
+		}
+	}
+	if len(props) > 0 {
+		tracedErr.Properties = props
+	}
+	return tracedErr
+}
+
+// toDuration interprets a property value as a duration.
+// Numbers are interpreted as seconds and strings are parsed with time.ParseDuration.
+func toDuration(v any) (time.Duration, bool) {
+	switch x := v.(type) {
+	case time.Duration:
+		return x, true
+	case int:
+		return time.Duration(x) * time.Second, true
+	case int64:
+		return time.Duration(x) * time.Second, true
+	case float64:
+		return time.Duration(x * float64(time.Second)), true
+	case string:
+		d, err := time.ParseDuration(x)
+		return d, err == nil
+	}
+	return 0, false
+}
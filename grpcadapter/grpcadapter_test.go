@@ -0,0 +1,84 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcadapter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+func TestGRPCAdapter_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	err := errors.New("invalid request", 400, "0123456789abcdef0123456789abcdef",
+		"code", "INVALID_USER",
+		"domain", "example.com",
+		"retryAfter", 5*time.Second,
+		"userID", 123,
+		errors.Join(
+			errors.New("name is required", "field", "name"),
+			errors.New("age is negative", "field", "age"),
+		),
+	)
+	st := ToGRPCStatus(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", st.Code())
+	}
+
+	var badRequest *errdetails.BadRequest
+	var errorInfo *errdetails.ErrorInfo
+	for _, d := range st.Details() {
+		switch x := d.(type) {
+		case *errdetails.BadRequest:
+			badRequest = x
+		case *errdetails.ErrorInfo:
+			errorInfo = x
+		}
+	}
+	if badRequest == nil || len(badRequest.GetFieldViolations()) != 2 {
+		t.Fatalf("expected 2 field violations, got %v", badRequest)
+	}
+	if errorInfo == nil || errorInfo.GetReason() != "INVALID_USER" || errorInfo.GetDomain() != "example.com" || errorInfo.GetMetadata()["userID"] != "123" {
+		t.Errorf("unexpected error info %v", errorInfo)
+	}
+
+	tracedErr := errors.Convert(FromGRPCStatus(st))
+	if tracedErr.StatusCode != 400 {
+		t.Errorf("expected status 400, got %d", tracedErr.StatusCode)
+	}
+	if tracedErr.Trace != "0123456789abcdef0123456789abcdef" {
+		t.Errorf("unexpected trace %s", tracedErr.Trace)
+	}
+	if tracedErr.Properties["code"] != "INVALID_USER" {
+		t.Errorf("unexpected code %v", tracedErr.Properties["code"])
+	}
+	if tracedErr.Properties["retryAfter"] != 5*time.Second || tracedErr.Properties["retryable"] != true {
+		t.Errorf("unexpected retry properties %v", tracedErr.Properties)
+	}
+	violations, _ := tracedErr.Properties["fieldViolations"].(map[string]string)
+	if violations["name"] != "name is required" || violations["age"] != "age is negative" {
+		t.Errorf("unexpected violations %v", violations)
+	}
+
+	if FromGRPCStatus(ToGRPCStatus(nil)) != nil {
+		t.Errorf("expected nil")
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Headers used to propagate the metadata of an error.
+const (
+	HeaderErrorStatus  = "X-Error-Status"
+	HeaderErrorTrace   = "X-Error-Trace"
+	HeaderErrorSpan    = "X-Error-Span"
+	HeaderErrorOrigin  = "X-Error-Origin"
+	HeaderErrorCode    = "X-Error-Code"
+	HeaderErrorMessage = "X-Error-Message"
+	HeaderErrorDigest  = "X-Error-Digest"
+)
+
+// maxHeaderValueLen is the maximum length of the value of a header, before escaping.
+const maxHeaderValueLen = 256
+
+/*
+ToHeader encodes the metadata of the error into X-Error-* headers, for propagation across proxies
+in responses that cannot carry a body, such as responses to HEAD requests or streamed responses.
+The status code, trace ID, span ID, origin, error code and a compact digest are encoded, along with the message truncated to a safe length.
+The error code is taken from the "code" property of the error, if present.
+
+	errors.ToHeader(w.Header(), err)
+	w.WriteHeader(errors.StatusCode(err))
+*/
+func ToHeader(h http.Header, err error) {
+	if err == nil || h == nil {
+		return
+	}
+	original := Convert(err)
+	tracedErr := original.scrubbed()
+	h.Set(HeaderErrorStatus, strconv.Itoa(tracedErr.StatusCode))
+	if tracedErr.Trace != "" && tracedErr.Trace != zeroTrace {
+		h.Set(HeaderErrorTrace, escapeHeaderValue(tracedErr.Trace))
+	}
+	if tracedErr.Span != "" && tracedErr.Span != zeroSpan {
+		h.Set(HeaderErrorSpan, escapeHeaderValue(tracedErr.Span))
+	}
+	if tracedErr.Origin != OriginLocal {
+		h.Set(HeaderErrorOrigin, tracedErr.Origin.String())
+	}
+	if code, ok := tracedErr.Properties["code"]; ok {
+		h.Set(HeaderErrorCode, escapeHeaderValue(fmt.Sprintf("%v", code)))
+	}
+	h.Set(HeaderErrorMessage, escapeHeaderValue(tracedErr.limited().Error()))
+	h.Set(HeaderErrorDigest, original.digest())
+}
+
+/*
+FromHeader decodes an error from the X-Error-* headers set by ToHeader.
+It returns nil if the headers do not indicate an error.
+
+	err := errors.FromHeader(res.Header)
+*/
+func FromHeader(h http.Header) error {
+	status := h.Get(HeaderErrorStatus)
+	if status == "" {
+		return nil
+	}
+	statusCode, err := strconv.Atoi(status)
+	if err != nil || statusCode < 100 || statusCode > 999 {
+		statusCode = 500
+	}
+	msg := unescapeHeaderValue(h.Get(HeaderErrorMessage))
+	if msg == "" {
+		msg = statusText[statusCode]
+	}
+	if msg == "" {
+		msg = "unspecified error"
+	}
+	tracedErr := &TracedError{
+		Err:        stderrors.New(msg),
+		StatusCode: statusCode,
+		Trace:      unescapeHeaderValue(h.Get(HeaderErrorTrace)),
+		Span:       unescapeHeaderValue(h.Get(HeaderErrorSpan)),
+		Origin:     parseOrigin(h.Get(HeaderErrorOrigin)),
+	}
+	if code := h.Get(HeaderErrorCode); code != "" {
+		tracedErr.Properties = map[string]any{"code": unescapeHeaderValue(code)}
+	}
+	if digest := h.Get(HeaderErrorDigest); digest != "" {
+		if tracedErr.Properties == nil {
+			tracedErr.Properties = map[string]any{}
+		}
+		tracedErr.Properties["digest"] = unescapeHeaderValue(digest)
+	}
+	return tracedErr
+}
+
+// digest returns a compact hash of the error's message and point of origin.
+func (e *TracedError) digest() string {
+	h := fnv.New64a()
+	h.Write([]byte(e.Error()))
+	if len(e.Stack) > 0 {
+		fmt.Fprintf(h, "\n%s:%d", e.Stack[0].File, e.Stack[0].Line)
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// escapeHeaderValue truncates the value to the maximum length of a header and percent-encodes
+// control characters, non-ASCII characters and the percent sign.
+func escapeHeaderValue(s string) string {
+	s = truncateString(s, maxHeaderValueLen)
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < 0x20 || c >= 0x7f || c == '%' {
+			fmt.Fprintf(&b, "%%%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// unescapeHeaderValue reverses the percent-encoding of escapeHeaderValue.
+func unescapeHeaderValue(s string) string {
+	unescaped, err := url.PathUnescape(s)
+	if err != nil {
+		return s
+	}
+	return unescaped
+}
@@ -0,0 +1,69 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestErrors_Header(t *testing.T) {
+	t.Parallel()
+
+	err := New("user 100%% not found\nretry later", 404, "0123456789abcdef0123456789abcdef", "code", "user.not_found")
+	h := http.Header{}
+	ToHeader(h, err)
+	assertEqual(t, "404", h.Get(HeaderErrorStatus))
+	assertEqual(t, "0123456789abcdef0123456789abcdef", h.Get(HeaderErrorTrace))
+	assertEqual(t, "user.not_found", h.Get(HeaderErrorCode))
+	assertEqual(t, "user 100%25 not found%0Aretry later", h.Get(HeaderErrorMessage))
+	assertEqual(t, 16, len(h.Get(HeaderErrorDigest)))
+
+	decoded := FromHeader(h)
+	assertError(t, decoded)
+	assertEqual(t, err.Error(), decoded.Error())
+	tracedErr := Convert(decoded)
+	assertEqual(t, 404, tracedErr.StatusCode)
+	assertEqual(t, "0123456789abcdef0123456789abcdef", tracedErr.Trace)
+	assertEqual(t, "user.not_found", tracedErr.Properties["code"])
+	assertEqual(t, h.Get(HeaderErrorDigest), tracedErr.Properties["digest"])
+
+	// No error
+	assertNil(t, FromHeader(http.Header{}))
+	h = http.Header{}
+	ToHeader(h, nil)
+	assertEqual(t, 0, len(h))
+
+	// Status code only
+	h = http.Header{}
+	h.Set(HeaderErrorStatus, "503")
+	decoded = FromHeader(h)
+	assertEqual(t, "service unavailable", decoded.Error())
+	assertEqual(t, 503, StatusCode(decoded))
+}
+
+func TestErrors_HeaderSizeLimit(t *testing.T) {
+	t.Parallel()
+
+	long := strings.Repeat("é", 1000)
+	h := http.Header{}
+	ToHeader(h, New(long))
+	assertTrue(t, len(unescapeHeaderValue(h.Get(HeaderErrorMessage))) <= maxHeaderValueLen)
+	msg := FromHeader(h).Error()
+	assertTrue(t, strings.HasSuffix(msg, "é..."))
+}
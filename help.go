@@ -0,0 +1,45 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import "fmt"
+
+/*
+WithHelp links the error to a runbook or public documentation.
+The URL is stored in the "help" property of the error, which can also be set at construction.
+
+	return errors.WithHelp(err, "https://example.com/docs/errors/quota-exceeded")
+	return errors.New("quota exceeded", http.StatusTooManyRequests, "help", "https://example.com/docs/errors/quota-exceeded")
+*/
+func WithHelp(err error, url string) error {
+	if err == nil {
+		return nil
+	}
+	return With(err, "help", url)
+}
+
+// Help returns the URL of the runbook or public documentation linked to the error, if any.
+func Help(err error) string {
+	if err == nil {
+		return ""
+	}
+	help, ok := tracedProperties(err)["help"]
+	if !ok || help == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", help)
+}
@@ -0,0 +1,39 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	stderrors "errors"
+	"testing"
+)
+
+func TestErrors_Help(t *testing.T) {
+	t.Parallel()
+
+	err := New("quota exceeded", 429, "help", "https://example.com/quota")
+	assertEqual(t, "https://example.com/quota", Help(err))
+	assertContains(t, Convert(err).String(), "help=https://example.com/quota")
+
+	err = WithHelp(stderrors.New("standard"), "https://example.com/standard")
+	assertEqual(t, "https://example.com/standard", Help(err))
+	err = Trace(err)
+	assertEqual(t, "https://example.com/standard", Help(err))
+
+	assertEqual(t, "", Help(New("no help")))
+	assertEqual(t, "", Help(nil))
+	assertNil(t, WithHelp(nil, "https://example.com"))
+}
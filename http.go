@@ -0,0 +1,248 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// maxResponseBodyLen limits the size of the body read by FromHTTPResponse
+	maxResponseBodyLen = 64 * 1024
+	// maxResponseMessageLen limits the length of a plain text message read by FromHTTPResponse
+	maxResponseMessageLen = 1024
+)
+
+// HTTPSerializer writes an error to an HTTP response, including the status code.
+type HTTPSerializer func(w http.ResponseWriter, err error)
+
+// SetHTTPSerializer sets the serializer used by WriteHTTP.
+// Passing nil restores the default serializer, which writes the JSON representation of the error.
+func SetHTTPSerializer(serializer HTTPSerializer) {
+	updateSettings(func(s *settings) {
+		s.httpSerializer = serializer
+	})
+}
+
+/*
+WriteHTTP writes the error to the HTTP response using the configured serializer.
+By default, the body is the JSON representation of the error, wrapped in an "err" field unless set otherwise by SetJSONConfig,
+and the response status code is the status code of the error.
+The mask policy set by SetMaskPolicy is applied to the error by the default serializer.
+The stack trace and suppressed errors are included only in debug mode.
+
+	if err != nil {
+		errors.WriteHTTP(w, err)
+		return
+	}
+*/
+func WriteHTTP(w http.ResponseWriter, err error) {
+	if err == nil {
+		return
+	}
+	if serializer := loadSettings().httpSerializer; serializer != nil {
+		serializer(w, err)
+		return
+	}
+	tracedErr := applyMaskPolicy(Convert(err))
+	h := w.Header()
+	h.Set("Content-Type", "application/json")
+	h.Set("X-Content-Type-Options", "nosniff")
+	if tracedErr.Trace != "" && tracedErr.Trace != zeroTrace {
+		h.Set(HeaderErrorTrace, escapeHeaderValue(tracedErr.Trace))
+	}
+	if tracedErr.Span != "" && tracedErr.Span != zeroSpan {
+		h.Set(HeaderErrorSpan, escapeHeaderValue(tracedErr.Span))
+	}
+	if retryAfter, ok := RetryAfter(tracedErr); ok {
+		h.Set("Retry-After", formatRetryAfter(retryAfter))
+	}
+	body := responseBody(tracedErr)
+	h.Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(tracedErr.StatusCode)
+	w.Write(body)
+}
+
+/*
+WriteHTTPCtx writes the error to the HTTP response as does WriteHTTP,
+after applying ForTenant with the tenant of the context marked by ContextWithTenant, if any.
+
+	ctx := errors.ContextWithTenant(r.Context(), tenantID)
+	if err != nil {
+		errors.WriteHTTPCtx(ctx, w, err)
+		return
+	}
+*/
+func WriteHTTPCtx(ctx context.Context, w http.ResponseWriter, err error) {
+	WriteHTTP(w, forRequestTenant(ctx, err))
+}
+
+// responseBody returns the JSON representation of the error, wrapped in the configured envelope, to be sent in responses.
+func responseBody(tracedErr *TracedError) []byte {
+	tracedErr = responseError(tracedErr)
+	cfg := loadJSONConfig()
+	body, err := json.Marshal(tracedErr)
+	if err != nil {
+		// Properties that cannot be marshaled are dropped
+		clone := *tracedErr
+		clone.Properties = nil
+		clone.Suppressed = nil
+		body, _ = json.Marshal(&clone)
+	}
+	if cfg.Envelope == "-" {
+		return body
+	}
+	body, _ = json.Marshal(map[string]json.RawMessage{
+		cfg.Envelope: body,
+	})
+	return body
+}
+
+// responseError returns the error as it should be included in responses, with the scrub rules applied.
+// The stack trace and suppressed errors are included only in debug mode.
+// In production render mode, only the message, status code, trace ID and span ID are included.
+func responseError(tracedErr *TracedError) *TracedError {
+	tracedErr = tracedErr.scrubbed()
+	if s := loadSettings(); s.renderMode == RenderProduction {
+		tracedErr = &TracedError{
+			Err:        stderrors.New(tracedErr.Error()),
+			StatusCode: tracedErr.StatusCode,
+			Trace:      tracedErr.Trace,
+			Span:       tracedErr.Span,
+		}
+	} else if !s.debugMode {
+		clone := *tracedErr
+		tracedErr = &clone
+		tracedErr.Stack = nil
+		tracedErr.Suppressed = nil
+	}
+	return tracedErr
+}
+
+/*
+FromHTTPResponse returns an error if the status code of the HTTP response indicates an error, or nil otherwise.
+The error is attributed to the upstream origin.
+A body in the format written by WriteHTTP is unmarshaled to restore the error,
+otherwise the error is reconstructed from the X-Error-* headers, if present, or from the body as plain text.
+The Retry-After header of 429 and 503 responses populates the duration returned by RetryAfter.
+The body is read but not closed.
+
+	res, err := http.Get(url)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer res.Body.Close()
+	if err := errors.FromHTTPResponse(res); err != nil {
+		return errors.Trace(err)
+	}
+*/
+func FromHTTPResponse(res *http.Response) error {
+	if res == nil || res.StatusCode < 400 {
+		return nil
+	}
+	var body []byte
+	if res.Body != nil {
+		body, _ = io.ReadAll(io.LimitReader(res.Body, maxResponseBodyLen))
+	}
+	tracedErr := unmarshalResponseBody(body)
+	if tracedErr == nil {
+		if headerErr := FromHeader(res.Header); headerErr != nil {
+			tracedErr = headerErr.(*TracedError)
+		} else {
+			msg := strings.TrimSpace(string(body))
+			if msg == "" || !utf8.ValidString(msg) {
+				msg = statusText[res.StatusCode]
+			}
+			if msg == "" {
+				msg = "unspecified error"
+			}
+			tracedErr = &TracedError{
+				Err: stderrors.New(truncateString(msg, maxResponseMessageLen)),
+			}
+		}
+	}
+	tracedErr.StatusCode = res.StatusCode
+	tracedErr.Origin = OriginUpstream
+	if res.StatusCode == http.StatusTooManyRequests || res.StatusCode == http.StatusServiceUnavailable {
+		if retryAfter, ok := parseRetryAfter(res.Header.Get("Retry-After"), time.Now()); ok {
+			if tracedErr.Properties == nil {
+				tracedErr.Properties = map[string]any{}
+			}
+			tracedErr.Properties["retryAfter"] = retryAfter
+		}
+	}
+	n := len(tracedErr.Stack)
+	return markSite(traceCaller(tracedErr), n, "new")
+}
+
+// unmarshalResponseBody unmarshals an error from a body in the format written by WriteHTTP, or returns nil if the format does not match.
+func unmarshalResponseBody(body []byte) *TracedError {
+	cfg := loadJSONConfig()
+	if cfg.Envelope != "-" {
+		var envelope map[string]json.RawMessage
+		if json.Unmarshal(body, &envelope) != nil {
+			return nil
+		}
+		body = envelope[cfg.Envelope]
+	}
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(body, &fields) != nil {
+		return nil
+	}
+	if _, ok := fields[cfg.MessageField]; !ok {
+		return nil
+	}
+	var tracedErr TracedError
+	if json.Unmarshal(body, &tracedErr) != nil {
+		return nil
+	}
+	return &tracedErr
+}
+
+/*
+RecoverHandler wraps an HTTP handler and recovers from panics in it.
+A recovered panic is converted to an error with the full stack trace of the panic, in the same manner as CatchPanic,
+reported to the registered sinks, and written to the response with WriteHTTPCtx in the context of the request.
+The http.ErrAbortHandler panic is passed through, as required by net/http to abort the response.
+
+	http.ListenAndServe(":8080", errors.RecoverHandler(mux))
+*/
+func RecoverHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+			err := recoveredError(rec, 1)
+			Report(r.Context(), err)
+			WriteHTTPCtx(r.Context(), w, err)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,163 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestErrors_WriteHTTP(t *testing.T) {
+	t.Parallel()
+
+	w := httptest.NewRecorder()
+	WriteHTTP(w, New("user not found", 404, "0123456789abcdef0123456789abcdef", "userID", 123))
+	assertEqual(t, 404, w.Code)
+	assertEqual(t, "application/json", w.Header().Get("Content-Type"))
+	assertEqual(t, "0123456789abcdef0123456789abcdef", w.Header().Get(HeaderErrorTrace))
+
+	var body struct {
+		Err map[string]any `json:"err"`
+	}
+	assertNil(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assertEqual(t, "user not found", body.Err["error"])
+	assertEqual(t, 123.0, body.Err["userID"])
+	_, ok := body.Err["stack"]
+	assertTrue(t, !ok)
+
+	// Nil error
+	w = httptest.NewRecorder()
+	WriteHTTP(w, nil)
+	assertEqual(t, 0, w.Body.Len())
+}
+
+func TestErrors_FromHTTPResponse(t *testing.T) {
+	t.Parallel()
+
+	// Written by WriteHTTP
+	w := httptest.NewRecorder()
+	WriteHTTP(w, New("user not found", 404, "0123456789abcdef0123456789abcdef", "userID", 123))
+	err := FromHTTPResponse(w.Result())
+	assertError(t, err)
+	tracedErr := Convert(err)
+	assertEqual(t, "user not found", tracedErr.Error())
+	assertEqual(t, 404, tracedErr.StatusCode)
+	assertEqual(t, "0123456789abcdef0123456789abcdef", tracedErr.Trace)
+	assertEqual(t, 123.0, tracedErr.Properties["userID"])
+	assertContains(t, tracedErr.Stack[len(tracedErr.Stack)-1].File, "errors/http_test.go")
+
+	// Headers only
+	w = httptest.NewRecorder()
+	ToHeader(w.Header(), New("gone", 410))
+	w.WriteHeader(410)
+	err = FromHTTPResponse(w.Result())
+	assertEqual(t, "gone", err.Error())
+	assertEqual(t, 410, StatusCode(err))
+
+	// Plain text
+	w = httptest.NewRecorder()
+	http.Error(w, "bad input", 400)
+	err = FromHTTPResponse(w.Result())
+	assertEqual(t, "bad input", err.Error())
+	assertEqual(t, 400, StatusCode(err))
+
+	// Empty body
+	w = httptest.NewRecorder()
+	w.WriteHeader(502)
+	err = FromHTTPResponse(w.Result())
+	assertEqual(t, "bad gateway", err.Error())
+
+	// Success
+	w = httptest.NewRecorder()
+	w.Write([]byte("ok"))
+	assertNil(t, FromHTTPResponse(w.Result()))
+	assertNil(t, FromHTTPResponse(nil))
+}
+
+func TestErrors_SetHTTPSerializer(t *testing.T) {
+	// Not parallel because it modifies the package settings
+
+	SetHTTPSerializer(func(w http.ResponseWriter, err error) {
+		w.WriteHeader(StatusCode(err))
+		w.Write([]byte(err.Error()))
+	})
+	defer SetHTTPSerializer(nil)
+
+	w := httptest.NewRecorder()
+	WriteHTTP(w, New("bad", 400))
+	assertEqual(t, 400, w.Code)
+	assertEqual(t, "bad", w.Body.String())
+}
+
+func TestErrors_RecoverHandler(t *testing.T) {
+	t.Parallel()
+
+	var mux sync.Mutex
+	var reported error
+	defer RegisterSink(func(ctx context.Context, err error) {
+		if ctx.Value("test") == "TestErrors_RecoverHandler" {
+			mux.Lock()
+			reported = err
+			mux.Unlock()
+		}
+	})()
+
+	h := RecoverHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/abort" {
+			panic(http.ErrAbortHandler)
+		}
+		if r.URL.Path == "/ok" {
+			w.Write([]byte("ok"))
+			return
+		}
+		panic("oops")
+	}))
+
+	ctx := context.WithValue(context.Background(), "test", "TestErrors_RecoverHandler")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/panic", nil).WithContext(ctx))
+	assertEqual(t, 500, w.Code)
+	assertContains(t, w.Body.String(), `"error":"oops"`)
+
+	mux.Lock()
+	assertError(t, reported)
+	assertEqual(t, "oops", reported.Error())
+	stack := Convert(reported).Stack
+	mux.Unlock()
+	assertTrue(t, len(stack) > 0)
+	assertContains(t, stack[0].File, "errors/http_test.go")
+	for _, frame := range stack {
+		// Frames beyond the recovering handler are omitted
+		assertTrue(t, frame.Function != "errors.TestErrors_RecoverHandler")
+	}
+
+	// No panic
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/ok", nil))
+	assertEqual(t, 200, w.Code)
+	assertEqual(t, "ok", w.Body.String())
+
+	// Abort is passed through
+	defer func() {
+		assertEqual(t, http.ErrAbortHandler, recover())
+	}()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/abort", nil))
+}
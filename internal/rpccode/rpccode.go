@@ -0,0 +1,111 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package rpccode maps HTTP status codes to and from the canonical RPC codes shared by gRPC and Connect.
+*/
+package rpccode
+
+// Canonical RPC codes
+const (
+	OK                 uint32 = 0
+	Canceled           uint32 = 1
+	Unknown            uint32 = 2
+	InvalidArgument    uint32 = 3
+	DeadlineExceeded   uint32 = 4
+	NotFound           uint32 = 5
+	AlreadyExists      uint32 = 6
+	PermissionDenied   uint32 = 7
+	ResourceExhausted  uint32 = 8
+	FailedPrecondition uint32 = 9
+	Aborted            uint32 = 10
+	OutOfRange         uint32 = 11
+	Unimplemented      uint32 = 12
+	Internal           uint32 = 13
+	Unavailable        uint32 = 14
+	DataLoss           uint32 = 15
+	Unauthenticated    uint32 = 16
+)
+
+// FromHTTP returns the RPC code that corresponds to the HTTP status code.
+func FromHTTP(statusCode int) uint32 {
+	switch statusCode {
+	case 200:
+		return OK
+	case 400:
+		return InvalidArgument
+	case 401:
+		return Unauthenticated
+	case 403:
+		return PermissionDenied
+	case 404:
+		return NotFound
+	case 408, 504:
+		return DeadlineExceeded
+	case 409:
+		return AlreadyExists
+	case 412:
+		return FailedPrecondition
+	case 416:
+		return OutOfRange
+	case 429:
+		return ResourceExhausted
+	case 499:
+		return Canceled
+	case 501:
+		return Unimplemented
+	case 503:
+		return Unavailable
+	}
+	switch {
+	case statusCode >= 200 && statusCode < 300:
+		return OK
+	case statusCode >= 400 && statusCode < 500:
+		return FailedPrecondition
+	case statusCode >= 500:
+		return Internal
+	}
+	return Unknown
+}
+
+// ToHTTP returns the HTTP status code that corresponds to the RPC code.
+func ToHTTP(code uint32) int {
+	switch code {
+	case OK:
+		return 200
+	case Canceled:
+		return 499
+	case InvalidArgument, FailedPrecondition, OutOfRange:
+		return 400
+	case DeadlineExceeded:
+		return 504
+	case NotFound:
+		return 404
+	case AlreadyExists, Aborted:
+		return 409
+	case PermissionDenied:
+		return 403
+	case ResourceExhausted:
+		return 429
+	case Unimplemented:
+		return 501
+	case Unavailable:
+		return 503
+	case Unauthenticated:
+		return 401
+	}
+	return 500
+}
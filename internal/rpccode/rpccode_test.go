@@ -0,0 +1,40 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rpccode
+
+import (
+	"testing"
+)
+
+func TestRPCCode_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, statusCode := range []int{200, 400, 401, 403, 404, 409, 429, 499, 500, 501, 503, 504} {
+		if got := ToHTTP(FromHTTP(statusCode)); got != statusCode {
+			t.Errorf("expected %d, got %d", statusCode, got)
+		}
+	}
+	if FromHTTP(418) != FailedPrecondition {
+		t.Errorf("expected unmapped 4xx to map to FailedPrecondition")
+	}
+	if FromHTTP(502) != Internal {
+		t.Errorf("expected unmapped 5xx to map to Internal")
+	}
+	if ToHTTP(DataLoss) != 500 || ToHTTP(Unknown) != 500 {
+		t.Errorf("expected unmapped codes to map to 500")
+	}
+}
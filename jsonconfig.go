@@ -0,0 +1,72 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+// JSONConfig configures the names of the fields of the JSON representation of traced errors,
+// and the envelope that wraps the error in responses.
+// Zero-valued fields indicate the defaults.
+type JSONConfig struct {
+	// MessageField is the name of the field of the error message. The default is "error".
+	MessageField string
+	// CodeField is the name of the field of the HTTP status code. The default is "statusCode".
+	CodeField string
+	// Envelope is the name of the field that wraps the error in responses such as those written by WriteHTTP.
+	// The default is "err". A value of "-" indicates no envelope.
+	Envelope string
+}
+
+/*
+SetJSONConfig sets the names of the fields used by MarshalJSON, UnmarshalJSON, WriteHTTP and FromHTTPResponse.
+It is intended for services that must conform to the error format of an existing API.
+StreamedError is not affected.
+
+	errors.SetJSONConfig(errors.JSONConfig{
+		MessageField: "message",
+		CodeField:    "code",
+		Envelope:     "error",
+	})
+*/
+func SetJSONConfig(cfg JSONConfig) {
+	updateSettings(func(s *settings) {
+		s.jsonConfig = cfg
+	})
+}
+
+// loadJSONConfig returns the JSON configuration with the defaults filled in.
+func loadJSONConfig() JSONConfig {
+	cfg := loadSettings().jsonConfig
+	if cfg.MessageField == "" {
+		cfg.MessageField = "error"
+	}
+	if cfg.CodeField == "" {
+		cfg.CodeField = "statusCode"
+	}
+	if cfg.Envelope == "" {
+		cfg.Envelope = "err"
+	}
+	return cfg
+}
+
+// isReservedField indicates if the field name is one of the standard fields of the JSON representation of traced errors,
+// in which case a property of the same name is not serialized.
+func (cfg JSONConfig) isReservedField(name string) bool {
+	switch name {
+	case cfg.MessageField, cfg.CodeField, "trace", "span", "origin", "stack", "suppressed":
+		return true
+	}
+	return false
+}
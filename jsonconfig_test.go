@@ -0,0 +1,86 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrors_JSONConfig(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	SetJSONConfig(JSONConfig{
+		MessageField: "message",
+		CodeField:    "code",
+		Envelope:     "error",
+	})
+	defer SetJSONConfig(JSONConfig{})
+
+	err := New("user not found", 404, "userID", 123, "error", "shadowed")
+	b, jsonErr := json.Marshal(err)
+	assertNil(t, jsonErr)
+	var m map[string]any
+	assertNil(t, json.Unmarshal(b, &m))
+	assertEqual(t, "user not found", m["message"])
+	assertEqual(t, 404.0, m["code"])
+	assertEqual(t, 123.0, m["userID"])
+	assertEqual(t, "shadowed", m["error"])
+	_, ok := m["statusCode"]
+	assertTrue(t, !ok)
+
+	var unmarshaled TracedError
+	assertNil(t, json.Unmarshal(b, &unmarshaled))
+	assertEqual(t, "user not found", unmarshaled.Error())
+	assertEqual(t, 404, unmarshaled.StatusCode)
+	assertEqual(t, 123.0, unmarshaled.Properties["userID"])
+	assertEqual(t, "shadowed", unmarshaled.Properties["error"])
+
+	// Envelope
+	w := httptest.NewRecorder()
+	WriteHTTP(w, err)
+	var body map[string]map[string]any
+	assertNil(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assertEqual(t, "user not found", body["error"]["message"])
+	restored := FromHTTPResponse(w.Result())
+	assertEqual(t, "user not found", restored.Error())
+	assertEqual(t, 123.0, Convert(restored).Properties["userID"])
+
+	// No envelope
+	SetJSONConfig(JSONConfig{
+		Envelope: "-",
+	})
+	w = httptest.NewRecorder()
+	WriteHTTP(w, err)
+	m = nil
+	assertNil(t, json.Unmarshal(w.Body.Bytes(), &m))
+	assertEqual(t, "user not found", m["error"])
+	assertEqual(t, 404.0, m["statusCode"])
+	restored = FromHTTPResponse(w.Result())
+	assertEqual(t, "user not found", restored.Error())
+	assertEqual(t, 123.0, Convert(restored).Properties["userID"])
+
+	// Schema
+	SetJSONConfig(JSONConfig{
+		MessageField: "message",
+	})
+	props := JSONSchema()["properties"].(map[string]any)
+	_, ok = props["message"]
+	assertTrue(t, ok)
+	_, ok = props["error"]
+	assertTrue(t, !ok)
+}
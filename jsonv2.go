@@ -0,0 +1,349 @@
+//go:build go1.27
+
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/json/jsontext"
+	jsonv2 "encoding/json/v2"
+	stderrors "errors"
+	"slices"
+)
+
+// MarshalJSONTo marshals the error to JSON by streaming it to the encoder, without building an intermediate map.
+// The output is identical to that of MarshalJSON, including the field names set by SetJSONConfig.
+func (e *TracedError) MarshalJSONTo(enc *jsontext.Encoder) error {
+	var visited visitedSet
+	return e.marshalJSONTo(enc, &visited)
+}
+
+// marshalJSONTo streams the error to the encoder, skipping suppressed errors that were already visited.
+func (e *TracedError) marshalJSONTo(enc *jsontext.Encoder, visited *visitedSet) error {
+	visited.visit(e)
+	e = e.limited()
+	var suppressed []*TracedError
+	for _, s := range e.Suppressed {
+		if s != nil && visited.visit(s) {
+			suppressed = append(suppressed, Convert(s))
+		}
+	}
+
+	// Keys are written in sorted order, same as when marshaling a map
+	cfg := loadJSONConfig()
+	keys := make([]string, 0, len(e.Properties)+5)
+	for k := range e.Properties {
+		if !cfg.isReservedField(k) {
+			keys = append(keys, k)
+		}
+	}
+	keys = append(keys, cfg.MessageField)
+	if e.StatusCode != 0 {
+		keys = append(keys, cfg.CodeField)
+	}
+	if e.Stack != nil {
+		keys = append(keys, "stack")
+	}
+	if e.Trace != "" && e.Trace != zeroTrace {
+		keys = append(keys, "trace")
+	}
+	if e.Span != "" && e.Span != zeroSpan {
+		keys = append(keys, "span")
+	}
+	if e.Origin != OriginLocal {
+		keys = append(keys, "origin")
+	}
+	if len(suppressed) > 0 {
+		keys = append(keys, "suppressed")
+	}
+	slices.Sort(keys)
+
+	err := enc.WriteToken(jsontext.BeginObject)
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		err = enc.WriteToken(jsontext.String(k))
+		if err != nil {
+			return err
+		}
+		switch k {
+		case cfg.MessageField:
+			err = enc.WriteToken(jsontext.String(e.Error()))
+		case cfg.CodeField:
+			err = enc.WriteToken(jsontext.Int(int64(e.StatusCode)))
+		case "trace":
+			err = enc.WriteToken(jsontext.String(e.Trace))
+		case "span":
+			err = enc.WriteToken(jsontext.String(e.Span))
+		case "origin":
+			err = enc.WriteToken(jsontext.String(e.Origin.String()))
+		case "stack":
+			err = writeStackJSONTo(enc, e.Stack)
+		case "suppressed":
+			err = enc.WriteToken(jsontext.BeginArray)
+			for _, s := range suppressed {
+				if err == nil {
+					err = s.marshalJSONTo(enc, visited)
+				}
+			}
+			if err == nil {
+				err = enc.WriteToken(jsontext.EndArray)
+			}
+		default:
+			v := e.Properties[k]
+			if _, marshalErr := jsonv2.Marshal(v); marshalErr != nil {
+				v = badPropertyValue(v)
+			}
+			err = jsonv2.MarshalEncode(enc, v)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return enc.WriteToken(jsontext.EndObject)
+}
+
+// UnmarshalJSONFrom unmarshals the error by streaming it from the decoder.
+// Neither the type of the error nor any errors it wraps can be restored.
+// The input is treated as untrusted and is subject to the same limits as UnmarshalJSON.
+func (e *TracedError) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	if dec.StackDepth() >= maxUnmarshalDepth {
+		return New("error exceeds nesting depth limit of %d", maxUnmarshalDepth)
+	}
+	tok, err := dec.ReadToken()
+	if err != nil {
+		return err
+	}
+	if tok.Kind() != '{' {
+		return New("expected JSON object but found '%v'", tok.Kind())
+	}
+	cfg := loadJSONConfig()
+	var msg string
+	e.Stack = nil
+	e.StatusCode = 0
+	e.Trace = ""
+	e.Span = ""
+	e.Origin = OriginLocal
+	e.Properties = nil
+	e.Suppressed = nil
+	for dec.PeekKind() != '}' {
+		tok, err = dec.ReadToken()
+		if err != nil {
+			return err
+		}
+		switch k := tok.String(); k {
+		case cfg.MessageField:
+			err = jsonv2.UnmarshalDecode(dec, &msg)
+		case cfg.CodeField:
+			err = jsonv2.UnmarshalDecode(dec, &e.StatusCode)
+		case "trace":
+			err = jsonv2.UnmarshalDecode(dec, &e.Trace)
+		case "span":
+			err = jsonv2.UnmarshalDecode(dec, &e.Span)
+		case "origin":
+			var origin string
+			err = jsonv2.UnmarshalDecode(dec, &origin)
+			e.Origin = parseOrigin(origin)
+		case "stack":
+			err = jsonv2.UnmarshalDecode(dec, &e.Stack)
+		case "suppressed":
+			var suppressed []*TracedError
+			err = jsonv2.UnmarshalDecode(dec, &suppressed)
+			for _, s := range suppressed {
+				if s != nil {
+					e.Suppressed = append(e.Suppressed, s)
+				}
+			}
+		default:
+			var raw jsontext.Value
+			raw, err = dec.ReadValue()
+			if err != nil {
+				return err
+			}
+			if len(raw) > maxUnmarshalPropertyLen {
+				return New("property '%s' of %d bytes exceeds limit of %d", truncateString(k, 64), len(raw), maxUnmarshalPropertyLen)
+			}
+			err = checkUnmarshalInput(raw, dec.StackDepth())
+			if err != nil {
+				return err
+			}
+			var v any
+			err = jsonv2.Unmarshal(raw, &v)
+			if e.Properties == nil {
+				e.Properties = map[string]any{}
+			}
+			e.Properties[k] = v
+		}
+		if err != nil {
+			return err
+		}
+		if dec.InputOffset() > maxUnmarshalLen {
+			return New("error exceeds limit of %d bytes", maxUnmarshalLen)
+		}
+	}
+	_, err = dec.ReadToken()
+	if err != nil {
+		return err
+	}
+	e.Err = stderrors.New(msg)
+	return checkUnmarshaled(e)
+}
+
+// MarshalJSONTo marshals the streamed error to JSON by streaming it to the encoder.
+func (s *StreamedError) MarshalJSONTo(enc *jsontext.Encoder) error {
+	err := enc.WriteToken(jsontext.BeginObject)
+	if err == nil {
+		err = enc.WriteToken(jsontext.String("error"))
+	}
+	if err == nil {
+		err = enc.WriteToken(jsontext.String(s.Error))
+	}
+	if err == nil && s.StatusCode != 0 {
+		err = enc.WriteToken(jsontext.String("statusCode"))
+		if err == nil {
+			err = enc.WriteToken(jsontext.Int(int64(s.StatusCode)))
+		}
+	}
+	if err == nil && s.Trace != "" {
+		err = enc.WriteToken(jsontext.String("trace"))
+		if err == nil {
+			err = enc.WriteToken(jsontext.String(s.Trace))
+		}
+	}
+	if err == nil && s.Span != "" {
+		err = enc.WriteToken(jsontext.String("span"))
+		if err == nil {
+			err = enc.WriteToken(jsontext.String(s.Span))
+		}
+	}
+	if err == nil && s.Origin != "" {
+		err = enc.WriteToken(jsontext.String("origin"))
+		if err == nil {
+			err = enc.WriteToken(jsontext.String(s.Origin))
+		}
+	}
+	if err == nil && len(s.Stack) > 0 {
+		err = enc.WriteToken(jsontext.String("stack"))
+		if err == nil {
+			err = writeStackJSONTo(enc, s.Stack)
+		}
+	}
+	if err == nil && len(s.Suppressed) > 0 {
+		err = enc.WriteToken(jsontext.String("suppressed"))
+		if err == nil {
+			err = enc.WriteToken(jsontext.BeginArray)
+		}
+		for _, suppressed := range s.Suppressed {
+			if err == nil && suppressed != nil {
+				err = suppressed.MarshalJSONTo(enc)
+			}
+		}
+		if err == nil {
+			err = enc.WriteToken(jsontext.EndArray)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return enc.WriteToken(jsontext.EndObject)
+}
+
+// UnmarshalJSONFrom unmarshals the streamed error by streaming it from the decoder.
+// Unknown fields, such as the properties of the error, are skipped.
+func (s *StreamedError) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	tok, err := dec.ReadToken()
+	if err != nil {
+		return err
+	}
+	if tok.Kind() != '{' {
+		return New("expected JSON object but found '%v'", tok.Kind())
+	}
+	*s = StreamedError{}
+	for dec.PeekKind() != '}' {
+		tok, err = dec.ReadToken()
+		if err != nil {
+			return err
+		}
+		switch tok.String() {
+		case "error":
+			err = jsonv2.UnmarshalDecode(dec, &s.Error)
+		case "statusCode":
+			err = jsonv2.UnmarshalDecode(dec, &s.StatusCode)
+		case "trace":
+			err = jsonv2.UnmarshalDecode(dec, &s.Trace)
+		case "span":
+			err = jsonv2.UnmarshalDecode(dec, &s.Span)
+		case "origin":
+			err = jsonv2.UnmarshalDecode(dec, &s.Origin)
+		case "stack":
+			err = jsonv2.UnmarshalDecode(dec, &s.Stack)
+		case "suppressed":
+			err = jsonv2.UnmarshalDecode(dec, &s.Suppressed)
+		default:
+			err = dec.SkipValue()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	_, err = dec.ReadToken()
+	return err
+}
+
+// writeStackJSONTo streams the stack frames to the encoder one at a time.
+func writeStackJSONTo(enc *jsontext.Encoder, stack []*StackFrame) error {
+	err := enc.WriteToken(jsontext.BeginArray)
+	if err != nil {
+		return err
+	}
+	for _, frame := range stack {
+		if frame == nil {
+			err = enc.WriteToken(jsontext.Null)
+		} else {
+			err = writeStackFrameJSONTo(enc, frame)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return enc.WriteToken(jsontext.EndArray)
+}
+
+// writeStackFrameJSONTo streams a single stack frame to the encoder.
+func writeStackFrameJSONTo(enc *jsontext.Encoder, frame *StackFrame) error {
+	tokens := []jsontext.Token{
+		jsontext.BeginObject,
+		jsontext.String("func"), jsontext.String(frame.Function),
+		jsontext.String("file"), jsontext.String(frame.File),
+		jsontext.String("line"), jsontext.Int(int64(frame.Line)),
+	}
+	if frame.Repeat != 0 {
+		tokens = append(tokens, jsontext.String("repeat"), jsontext.Int(int64(frame.Repeat)))
+	}
+	if frame.Site != "" {
+		tokens = append(tokens, jsontext.String("site"), jsontext.String(frame.Site))
+	}
+	tokens = append(tokens, jsontext.EndObject)
+	for _, tok := range tokens {
+		err := enc.WriteToken(tok)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+//go:build go1.27
+
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/json"
+	jsonv2 "encoding/json/v2"
+	"testing"
+)
+
+func TestErrors_JSONv2(t *testing.T) {
+	t.Parallel()
+
+	err := New("oops <&>", 409, "0123456789abcdef0123456789abcdef",
+		"str", "value",
+		"num", 5,
+		"nested", map[string]any{"x": []any{"y"}},
+		"error", "reserved",
+	)
+	err = Trace(err)
+	err = AddSuppressed(err, New("rollback failed", "table", "users"))
+	original := Convert(err)
+
+	// Streaming output matches that of MarshalJSON
+	v1, marshalErr := original.MarshalJSON()
+	assertNil(t, marshalErr)
+	v2, marshalErr := jsonv2.Marshal(original)
+	assertNil(t, marshalErr)
+	var m1, m2 map[string]any
+	assertNil(t, json.Unmarshal(v1, &m1))
+	assertNil(t, json.Unmarshal(v2, &m2))
+	assertEqual(t, m1, m2)
+
+	var decoded TracedError
+	marshalErr = jsonv2.Unmarshal(v2, &decoded)
+	assertNil(t, marshalErr)
+	assertEqual(t, "oops <&>", decoded.Error())
+	assertEqual(t, 409, decoded.StatusCode)
+	assertEqual(t, "0123456789abcdef0123456789abcdef", decoded.Trace)
+	assertEqual(t, original.Stack, decoded.Stack)
+	assertEqual(t, "value", decoded.Properties["str"])
+	assertEqual(t, 5.0, decoded.Properties["num"])
+	assertEqual(t, map[string]any{"x": []any{"y"}}, decoded.Properties["nested"])
+	assertEqual(t, 1, len(decoded.Suppressed))
+	assertEqual(t, "users", Convert(decoded.Suppressed[0]).Properties["table"])
+
+	// Unmarshalable property
+	v2, marshalErr = jsonv2.Marshal(Convert(New("oops", "func", func() {})))
+	assertNil(t, marshalErr)
+	assertContains(t, string(v2), `"func":"!BADVALUE(func()) 0x`)
+
+	// Not an object
+	marshalErr = jsonv2.Unmarshal([]byte(`"oops"`), &decoded)
+	assertError(t, marshalErr)
+}
+
+func TestErrors_JSONv2Streamed(t *testing.T) {
+	t.Parallel()
+
+	original := Convert(AddSuppressed(New("oops", 409, "key", "value"), New("rollback failed")))
+	b, err := jsonv2.Marshal(original)
+	assertNil(t, err)
+
+	var streamed StreamedError
+	err = jsonv2.Unmarshal(b, &streamed)
+	assertNil(t, err)
+	assertEqual(t, "oops", streamed.Error)
+	assertEqual(t, 409, streamed.StatusCode)
+	assertEqual(t, len(original.Stack), len(streamed.Stack))
+	assertEqual(t, 1, len(streamed.Suppressed))
+	assertEqual(t, "rollback failed", streamed.Suppressed[0].Error)
+
+	// Round trip
+	b2, err := jsonv2.Marshal(&streamed)
+	assertNil(t, err)
+	var streamed2 StreamedError
+	err = jsonv2.Unmarshal(b2, &streamed2)
+	assertNil(t, err)
+	assertEqual(t, streamed, streamed2)
+
+	// Matches the v1 encoding
+	b1, err := json.Marshal(streamed)
+	assertNil(t, err)
+	assertEqual(t, string(b1), string(b2))
+}
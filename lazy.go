@@ -0,0 +1,80 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// LazyValue is a property value that is computed only when the error is formatted or serialized.
+// The value is computed at most once.
+type LazyValue struct {
+	compute func() any
+	once    sync.Once
+	value   any
+}
+
+/*
+Lazy creates a property value that is computed only when the error is formatted or serialized.
+It is intended for expensive context, such as large request dumps or database query plans, that is usually not needed.
+A panic during the computation results in a value marked with a !PANIC prefix.
+
+	return errors.New("query failed", err,
+		"plan", errors.Lazy(func() any {
+			return explain(ctx, query)
+		}),
+	)
+*/
+func Lazy(compute func() any) *LazyValue {
+	return &LazyValue{
+		compute: compute,
+	}
+}
+
+// Value computes the value, if not already computed, and returns it.
+func (l *LazyValue) Value() any {
+	l.once.Do(func() {
+		if l.compute == nil {
+			return
+		}
+		defer func() {
+			if r := recover(); r != nil {
+				l.value = fmt.Sprintf("!PANIC %v", r)
+			}
+		}()
+		l.value = l.compute()
+	})
+	return l.value
+}
+
+// String returns the string representation of the computed value.
+func (l *LazyValue) String() string {
+	return fmt.Sprintf("%v", l.Value())
+}
+
+// MarshalJSON marshals the computed value to JSON.
+func (l *LazyValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.Value())
+}
+
+// LogValue returns the computed value to be logged by slog.
+func (l *LazyValue) LogValue() slog.Value {
+	return slog.AnyValue(l.Value())
+}
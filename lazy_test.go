@@ -0,0 +1,60 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+)
+
+func TestErrors_Lazy(t *testing.T) {
+	t.Parallel()
+
+	var computed atomic.Int32
+	err := New("query failed", "plan", Lazy(func() any {
+		computed.Add(1)
+		return map[string]any{"rows": 100}
+	}))
+	err = Trace(err)
+	assertEqual(t, int32(0), computed.Load())
+
+	b, jsonErr := json.Marshal(err)
+	assertNil(t, jsonErr)
+	assertContains(t, string(b), `"plan":{"rows":100}`)
+	assertEqual(t, int32(1), computed.Load())
+
+	assertContains(t, Convert(err).String(), "plan=map[rows:100]")
+	assertEqual(t, int32(1), computed.Load())
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("failed", "plan", Convert(err).Properties["plan"])
+	assertContains(t, buf.String(), `"plan":{"rows":100}`)
+
+	// Panics
+	err = New("oops", "dump", Lazy(func() any {
+		panic("boom")
+	}))
+	b, jsonErr = json.Marshal(err)
+	assertNil(t, jsonErr)
+	assertContains(t, string(b), `"dump":"!PANIC boom"`)
+
+	// Nil function
+	assertEqual(t, nil, Lazy(nil).Value())
+}
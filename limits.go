@@ -0,0 +1,173 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"maps"
+	"slices"
+)
+
+// Limits enforced when unmarshaling errors, which may arrive from untrusted peers.
+const (
+	maxUnmarshalLen         = 1024 * 1024
+	maxUnmarshalDepth       = 32
+	maxUnmarshalFrames      = 1024
+	maxUnmarshalProperties  = 256
+	maxUnmarshalPropertyLen = 64 * 1024
+)
+
+// checkUnmarshalInput checks that JSON input from a potentially untrusted peer is within the limits of size and nesting depth.
+// The depth is that at which the input is nested.
+func checkUnmarshalInput(data []byte, depth int) error {
+	if len(data) > maxUnmarshalLen {
+		return New("error of %d bytes exceeds limit of %d", len(data), maxUnmarshalLen)
+	}
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			switch c {
+			case '\\':
+				i++
+			case '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+			if depth > maxUnmarshalDepth {
+				return New("error exceeds nesting depth limit of %d", maxUnmarshalDepth)
+			}
+		case '}', ']':
+			depth--
+		}
+	}
+	return nil
+}
+
+// checkUnmarshaled checks that an error unmarshaled from a potentially untrusted peer is within the limits
+// of the number of stack frames and properties, and that its trace ID and span ID are valid.
+func checkUnmarshaled(e *TracedError) error {
+	if len(e.Stack) > maxUnmarshalFrames {
+		return New("error with %d stack frames exceeds limit of %d", len(e.Stack), maxUnmarshalFrames)
+	}
+	if len(e.Properties) > maxUnmarshalProperties {
+		return New("error with %d properties exceeds limit of %d", len(e.Properties), maxUnmarshalProperties)
+	}
+	if e.Trace != "" && !isTraceID(e.Trace) {
+		return New("invalid trace ID '%s'", truncateString(e.Trace, 64))
+	}
+	if e.Span != "" && !isSpanID(e.Span) {
+		return New("invalid span ID '%s'", truncateString(e.Span, 64))
+	}
+	return nil
+}
+
+// limited returns the error with the configured scrub rules, serialization limits and redaction applied.
+// The error itself is returned if it is within the limits, otherwise a shallow copy is returned.
+// The copy does not wrap the original error and is only suitable for serialization.
+func (e *TracedError) limited() *TracedError {
+	s := loadSettings()
+	if s.maxMessageLen == 0 && s.maxPropertyLen == 0 && s.maxProperties == 0 && s.maxSerializedFrames == 0 && len(s.redactedProperties) == 0 && len(s.scrubRules) == 0 {
+		return e
+	}
+	limited := e.scrubbed()
+	clone := func() {
+		if limited == e {
+			c := *e
+			limited = &c
+		}
+	}
+	if msg := limited.Error(); s.maxMessageLen > 0 && len(msg) > s.maxMessageLen {
+		clone()
+		limited.Err = stderrors.New(truncateString(msg, s.maxMessageLen))
+	}
+	if s.maxProperties > 0 && len(limited.Properties) > s.maxProperties {
+		clone()
+		props := limited.Properties
+		limited.Properties = make(map[string]any, s.maxProperties+1)
+		keys := slices.Sorted(maps.Keys(props))
+		for _, k := range keys[:s.maxProperties] {
+			limited.Properties[k] = props[k]
+		}
+		limited.Properties["!DROPPED"] = len(keys) - s.maxProperties
+	}
+	if len(s.redactedProperties) > 0 {
+		var props map[string]any
+		for _, k := range s.redactedProperties {
+			if _, ok := limited.Properties[k]; !ok {
+				continue
+			}
+			if props == nil {
+				props = maps.Clone(limited.Properties)
+			}
+			props[k] = "!REDACTED"
+		}
+		if props != nil {
+			clone()
+			limited.Properties = props
+		}
+	}
+	if s.maxPropertyLen > 0 {
+		var props map[string]any
+		for k, v := range limited.Properties {
+			truncated, ok := truncateProperty(v, s.maxPropertyLen)
+			if !ok {
+				continue
+			}
+			if props == nil {
+				props = maps.Clone(limited.Properties)
+			}
+			props[k] = truncated
+		}
+		if props != nil {
+			clone()
+			limited.Properties = props
+		}
+	}
+	if s.maxSerializedFrames > 0 && len(e.Stack) > s.maxSerializedFrames {
+		clone()
+		omitted := len(e.Stack) - s.maxSerializedFrames
+		if last := e.Stack[len(e.Stack)-1]; last.isElision() {
+			omitted += last.elided() - 1
+		}
+		limited.Stack = append(slices.Clip(e.Stack[:s.maxSerializedFrames]), elisionFrame(omitted))
+	}
+	return limited
+}
+
+// truncateProperty truncates the value of a property if its JSON representation is longer than the maximum length.
+// Strings are truncated as they are, whereas other values are replaced by their truncated JSON representation.
+func truncateProperty(v any, maxLen int) (truncated any, ok bool) {
+	if str, isStr := v.(string); isStr {
+		if len(str) <= maxLen {
+			return nil, false
+		}
+		return truncateString(str, maxLen), true
+	}
+	b, err := json.Marshal(v)
+	if err != nil || len(b) <= maxLen {
+		return nil, false
+	}
+	return truncateString(string(b), maxLen), true
+}
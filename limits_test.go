@@ -0,0 +1,79 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestErrors_UnmarshalLimits(t *testing.T) {
+	t.Parallel()
+
+	var tracedErr TracedError
+
+	// Within limits
+	err := json.Unmarshal([]byte(`{"error":"oops","statusCode":400,"trace":"0123456789abcdef0123456789abcdef","key":{"nested":[1,2]}}`), &tracedErr)
+	assertNil(t, err)
+	assertEqual(t, "oops", tracedErr.Error())
+	assertEqual(t, "0123456789abcdef0123456789abcdef", tracedErr.Trace)
+
+	// Invalid trace ID
+	err = json.Unmarshal([]byte(`{"error":"oops","trace":"not-a-trace-id"}`), &tracedErr)
+	assertError(t, err)
+	err = tracedErr.UnmarshalJSON([]byte(`{"error":"oops","trace":"not-a-trace-id"}`))
+	assertError(t, err)
+	assertEqual(t, "oops", tracedErr.Error())
+
+	// Nesting depth
+	deep := strings.Repeat("[", maxUnmarshalDepth) + strings.Repeat("]", maxUnmarshalDepth)
+	err = json.Unmarshal([]byte(`{"error":"oops","key":`+deep+`}`), &tracedErr)
+	assertError(t, err)
+	err = tracedErr.UnmarshalJSON([]byte(`{"error":"oops","key":` + deep + `}`))
+	assertError(t, err)
+	deep = `{"error":"oops"}`
+	for range maxUnmarshalDepth {
+		deep = `{"error":"oops","suppressed":[` + deep + `]}`
+	}
+	err = json.Unmarshal([]byte(deep), &tracedErr)
+	assertError(t, err)
+
+	// Brackets in strings do not count towards the depth
+	err = json.Unmarshal([]byte(`{"error":"`+strings.Repeat("[", 2*maxUnmarshalDepth)+`\"{"}`), &tracedErr)
+	assertNil(t, err)
+
+	// Stack frames
+	frames := strings.Repeat(`{"func":"f","file":"f.go","line":1},`, maxUnmarshalFrames+1)
+	err = json.Unmarshal([]byte(`{"error":"oops","stack":[`+strings.TrimSuffix(frames, ",")+`]}`), &tracedErr)
+	assertError(t, err)
+
+	// Properties
+	var props strings.Builder
+	for i := range maxUnmarshalProperties + 1 {
+		fmt.Fprintf(&props, `,"p%d":%d`, i, i)
+	}
+	err = json.Unmarshal([]byte(`{"error":"oops"`+props.String()+`}`), &tracedErr)
+	assertError(t, err)
+	err = json.Unmarshal([]byte(`{"error":"oops","key":"`+strings.Repeat("x", maxUnmarshalPropertyLen)+`"}`), &tracedErr)
+	assertError(t, err)
+
+	// Size
+	err = json.Unmarshal([]byte(`{"error":"`+strings.Repeat("x", maxUnmarshalLen)+`"}`), &tracedErr)
+	assertError(t, err)
+}
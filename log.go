@@ -0,0 +1,93 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+/*
+Log logs the error with a severity derived from its status code: errors with a 5xx status code are logged at the error level,
+errors with a 4xx status code at the warn level, and others at the info level.
+The message of the record is the message of the error, and its status code, trace ID, span ID, properties, stack
+and suppressed errors are expanded into attributes.
+The source of the record is set to the origin of the error rather than to the call site of Log.
+A nil logger logs to the default logger. A nil error is not logged.
+
+	errors.Log(ctx, logger, err)
+*/
+func Log(ctx context.Context, logger *slog.Logger, err error) {
+	if err == nil {
+		return
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	tracedErr := Convert(err).scrubbed()
+	level := logLevel(tracedErr.StatusCode)
+	h := logger.Handler()
+	if !h.Enabled(ctx, level) {
+		return
+	}
+	origin := originFrame(tracedErr)
+	var pc uintptr
+	if origin == nil {
+		// Attribute the record to the caller of Log
+		var pcs [1]uintptr
+		runtime.Callers(2, pcs[:])
+		pc = pcs[0]
+	}
+	r := slog.NewRecord(time.Now(), level, tracedErr.Error(), pc)
+	if origin != nil {
+		r.AddAttrs(slog.Group(slog.SourceKey,
+			slog.String("function", origin.Function),
+			slog.String("file", origin.File),
+			slog.Int("line", origin.Line),
+		))
+	}
+	// The first attribute is the message, which is the message of the record
+	r.AddAttrs(slogValue(tracedErr).Group()[1:]...)
+	h.Handle(ctx, r)
+}
+
+// logLevel returns the slog level that corresponds to the status code.
+func logLevel(statusCode int) slog.Level {
+	switch {
+	case statusCode >= 500:
+		return slog.LevelError
+	case statusCode >= 400:
+		return slog.LevelWarn
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// originFrame returns the first stack frame of the error that points to a location in the code, or nil if there is none.
+func originFrame(e *TracedError) *StackFrame {
+	for _, frame := range e.Stack {
+		if !frame.isPseudo() {
+			return frame
+		}
+	}
+	return nil
+}
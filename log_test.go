@@ -0,0 +1,81 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestErrors_Log(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{AddSource: true}))
+	ctx := context.Background()
+
+	err := New("not found", 404, "id", 123) // Origin of the error
+	Log(ctx, logger, err)
+	var rec map[string]any
+	assertNil(t, json.Unmarshal(buf.Bytes(), &rec))
+	assertEqual(t, "WARN", rec["level"])
+	assertEqual(t, "not found", rec["msg"])
+	assertEqual(t, float64(404), rec["statusCode"])
+	assertEqual(t, float64(123), rec["properties"].(map[string]any)["id"])
+	source := rec["source"].(map[string]any)
+	assertEqual(t, "errors.TestErrors_Log", source["function"])
+	assertTrue(t, strings.HasSuffix(source["file"].(string), "log_test.go"))
+	assertEqual(t, float64(err.(*TracedError).Stack[0].Line), source["line"])
+
+	// Severity
+	buf.Reset()
+	Log(ctx, logger, New("oops"))
+	assertContains(t, buf.String(), `"level":"ERROR"`)
+
+	buf.Reset()
+	Log(ctx, logger, New("moved", 301))
+	assertContains(t, buf.String(), `"level":"INFO"`)
+
+	// Disabled level
+	buf.Reset()
+	quiet := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelError}))
+	Log(ctx, quiet, New("not found", 404))
+	assertEqual(t, "", buf.String())
+
+	// Nil error
+	Log(ctx, logger, nil)
+	assertEqual(t, "", buf.String())
+}
+
+func TestErrors_LogWithoutStack(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	defer Configure(Config{})
+	Configure(Config{DisableStacks: true})
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{AddSource: true}))
+	Log(context.Background(), logger, New("oops"))
+	var rec map[string]any
+	assertNil(t, json.Unmarshal(buf.Bytes(), &rec))
+	// Attributed to the call site of Log
+	source := rec["source"].(map[string]any)
+	assertEqual(t, "github.com/microbus-io/errors.TestErrors_LogWithoutStack", source["function"])
+}
@@ -0,0 +1,80 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package logrusadapter converts traced errors to logrus fields.
+The package does not depend on logrus because the returned map is assignable to logrus.Fields.
+
+	logger.WithFields(logrusadapter.Fields(err)).Error("Request failed")
+*/
+package logrusadapter
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/microbus-io/errors"
+)
+
+// Fields returns the message, status code, trace ID, properties, stack and suppressed errors of the error
+// as logrus fields. The message is keyed "error", in line with logrus.ErrorKey.
+// The scrub rules set by errors.SetScrubRules are applied. A nil error results in no fields.
+func Fields(err error) map[string]any {
+	if err == nil {
+		return map[string]any{}
+	}
+	e := errors.Scrub(err)
+	fields := map[string]any{
+		"error": e.Error(),
+	}
+	if e.StatusCode != 0 {
+		fields["statusCode"] = e.StatusCode
+	}
+	if strings.Trim(e.Trace, "0") != "" {
+		fields["trace"] = e.Trace
+	}
+	if strings.Trim(e.Span, "0") != "" {
+		fields["span"] = e.Span
+	}
+	if len(e.Properties) > 0 {
+		fields["properties"] = maps.Clone(e.Properties)
+	}
+	if len(e.Stack) > 0 {
+		stack := make([]string, 0, len(e.Stack))
+		for _, frame := range e.Stack {
+			switch {
+			case frame.File == "" && frame.Line == 0:
+				stack = append(stack, frame.Function)
+			case frame.Repeat > 1:
+				stack = append(stack, fmt.Sprintf("%s (x%d) %s:%d", frame.Function, frame.Repeat, frame.File, frame.Line))
+			default:
+				stack = append(stack, fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line))
+			}
+		}
+		fields["stack"] = stack
+	}
+	if len(e.Suppressed) > 0 {
+		suppressed := make([]string, 0, len(e.Suppressed))
+		for _, s := range e.Suppressed {
+			if s != nil {
+				suppressed = append(suppressed, s.Error())
+			}
+		}
+		fields["suppressed"] = suppressed
+	}
+	return fields
+}
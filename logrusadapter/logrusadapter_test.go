@@ -0,0 +1,74 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logrusadapter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/microbus-io/errors"
+)
+
+// fields has the same underlying type as logrus.Fields
+type fields map[string]any
+
+func TestLogrusAdapter_Fields(t *testing.T) {
+	t.Parallel()
+
+	err := errors.New("oops", 409, "key", "value")
+	err = errors.AddSuppressed(err, errors.New("rollback failed"))
+	var f fields = Fields(err)
+
+	if f["error"] != "oops" {
+		t.Errorf("expected error 'oops', got %v", f["error"])
+	}
+	if f["statusCode"] != 409 {
+		t.Errorf("expected statusCode 409, got %v", f["statusCode"])
+	}
+	if props, _ := f["properties"].(map[string]any); props["key"] != "value" {
+		t.Errorf("expected property 'value', got %v", f["properties"])
+	}
+	stack, _ := f["stack"].([]string)
+	if len(stack) == 0 || !strings.Contains(stack[0], "logrusadapter_test.go:") {
+		t.Errorf("unexpected stack %v", f["stack"])
+	}
+	suppressed, _ := f["suppressed"].([]string)
+	if len(suppressed) != 1 || suppressed[0] != "rollback failed" {
+		t.Errorf("unexpected suppressed %v", f["suppressed"])
+	}
+
+	if len(Fields(nil)) != 0 {
+		t.Errorf("expected no fields")
+	}
+}
+
+func TestLogrusAdapter_Scrub(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	errors.SetScrubRules(errors.ScrubEmails)
+	defer errors.SetScrubRules()
+
+	err := errors.New("failed to notify jane@example.com", "email", "jane@example.com")
+	err = errors.AddSuppressed(err, errors.New("rollback for jane@example.com failed"))
+	f := Fields(err)
+	if out := fmt.Sprintf("%v", f); strings.Contains(out, "jane@example.com") {
+		t.Errorf("expected scrubbed fields, got %s", out)
+	}
+	if f["error"] != "failed to notify !REDACTED" {
+		t.Errorf("expected scrubbed message, got %v", f["error"])
+	}
+}
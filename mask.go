@@ -0,0 +1,109 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	"maps"
+	"path"
+)
+
+// MaskPolicy rewrites an error before it is exposed to clients.
+// The policy receives a copy of the error that it may modify, and returns the error to expose.
+type MaskPolicy func(e *TracedError) *TracedError
+
+/*
+SetMaskPolicy sets the policy applied to errors before they are exposed to clients by WriteHTTP, WriteProblem,
+ToAPIGatewayResponse, AttachWarnings and Public,
+so that compliance rules are enforced in one place rather than at each output site.
+The policy can rewrite the message, drop properties with MaskProperties, or downgrade details based on the status code.
+The role of the caller can be attached to the error as a property, for the policy to act upon and then drop.
+The stack trace and suppressed errors are removed after the policy is applied, unless in debug mode.
+Passing nil removes the policy.
+
+	errors.SetMaskPolicy(func(e *errors.TracedError) *errors.TracedError {
+		e = errors.MaskProperties(e, "internal*", "sql")
+		if e.StatusCode >= 500 {
+			e.Err = stderrors.New("internal server error")
+		}
+		return e
+	})
+*/
+func SetMaskPolicy(policy MaskPolicy) {
+	updateSettings(func(s *settings) {
+		s.maskPolicy = policy
+	})
+}
+
+/*
+Public returns the error as it would be exposed to clients by WriteHTTPCtx,
+after applying ForTenant with the tenant of the context marked by ContextWithTenant, if any,
+the mask policy set by SetMaskPolicy and the render mode set by SetRenderMode.
+The original error is not modified.
+
+	resp.Error = errors.Public(ctx, err).Error()
+*/
+func Public(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	return responseError(applyMaskPolicy(Convert(forRequestTenant(ctx, err))))
+}
+
+// applyMaskPolicy applies the mask policy, if any, to a copy of the traced error.
+func applyMaskPolicy(tracedErr *TracedError) *TracedError {
+	policy := loadSettings().maskPolicy
+	if policy == nil {
+		return tracedErr
+	}
+	clone := *tracedErr
+	clone.Properties = maps.Clone(tracedErr.Properties)
+	if masked := policy(&clone); masked != nil {
+		return masked
+	}
+	return &clone
+}
+
+/*
+MaskProperties returns a copy of the traced error without the properties whose key matches any of the patterns.
+Patterns follow the syntax of path.Match, for example "internal*".
+The original error is not modified.
+*/
+func MaskProperties(e *TracedError, patterns ...string) *TracedError {
+	if e == nil {
+		return nil
+	}
+	var props map[string]any
+	for k, v := range e.Properties {
+		masked := false
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, k); ok {
+				masked = true
+				break
+			}
+		}
+		if !masked {
+			if props == nil {
+				props = make(map[string]any, len(e.Properties))
+			}
+			props[k] = v
+		}
+	}
+	clone := *e
+	clone.Properties = props
+	return &clone
+}
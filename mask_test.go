@@ -0,0 +1,96 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrors_MaskProperties(t *testing.T) {
+	t.Parallel()
+
+	tracedErr := Convert(New("oops", "internalHost", "db1", "internalPort", 5432, "code", "E1"))
+	masked := MaskProperties(tracedErr, "internal*")
+	assertEqual(t, map[string]any{"code": "E1"}, masked.Properties)
+	assertEqual(t, 3, len(tracedErr.Properties))
+	assertEqual(t, "oops", masked.Error())
+
+	masked = MaskProperties(tracedErr, "*")
+	assertEqual(t, 0, len(masked.Properties))
+
+	assertNil(t, MaskProperties(nil, "*"))
+}
+
+func TestErrors_MaskPolicy(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	SetMaskPolicy(func(e *TracedError) *TracedError {
+		if e.Properties["role"] != "admin" {
+			e = MaskProperties(e, "sql")
+			if e.StatusCode >= 500 {
+				e.Err = stderrors.New("internal server error")
+			}
+		}
+		return MaskProperties(e, "role")
+	})
+	defer SetMaskPolicy(nil)
+
+	err := New("query failed", "sql", "SELECT 1", "code", "E1")
+
+	w := httptest.NewRecorder()
+	WriteHTTP(w, err)
+	assertEqual(t, 500, w.Code)
+	var body map[string]map[string]any
+	assertNil(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assertEqual(t, "internal server error", body["err"]["error"])
+	assertEqual(t, "E1", body["err"]["code"])
+	_, ok := body["err"]["sql"]
+	assertTrue(t, !ok)
+
+	public := Convert(Public(context.Background(), err))
+	assertEqual(t, "internal server error", public.Error())
+	assertEqual(t, 500, public.StatusCode)
+	_, ok = public.Properties["sql"]
+	assertTrue(t, !ok)
+
+	// The caller role is dropped after the policy acts upon it
+	public = Convert(Public(context.Background(), With(err, "role", "admin")))
+	assertEqual(t, "query failed", public.Error())
+	assertEqual(t, "SELECT 1", public.Properties["sql"])
+	_, ok = public.Properties["role"]
+	assertTrue(t, !ok)
+
+	// Client errors keep their message
+	public = Convert(Public(context.Background(), New("not found", 404, "sql", "SELECT 1")))
+	assertEqual(t, "not found", public.Error())
+
+	// The original error is not modified
+	assertEqual(t, "query failed", err.Error())
+	assertEqual(t, "SELECT 1", Convert(err).Properties["sql"])
+
+	w = httptest.NewRecorder()
+	WriteProblem(w, err)
+	assertContains(t, w.Body.String(), "internal server error")
+
+	// Removing the policy
+	SetMaskPolicy(nil)
+	assertEqual(t, "query failed", Public(context.Background(), err).Error())
+	assertNil(t, Public(context.Background(), nil))
+}
@@ -0,0 +1,95 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// MergeStrategy determines how conflicting properties are resolved when a traced error is wrapped by New or Trace.
+type MergeStrategy int
+
+const (
+	// MergeKeepOuter resolves conflicts in favor of the properties of the wrapping error. It is the default.
+	MergeKeepOuter MergeStrategy = iota
+	// MergeKeepInner resolves conflicts in favor of the properties of the wrapped error.
+	MergeKeepInner
+	// MergeSuffix retains both properties, the one of the wrapping error under the original name and the one of
+	// the wrapped error under the name suffixed with _1, or the next available number.
+	// Conflicting properties with equal values are not duplicated.
+	MergeSuffix
+)
+
+/*
+SetPropertyMergeStrategy sets how conflicting properties are resolved when a traced error is wrapped by New or Trace.
+Properties in groups of the same name are merged one by one per the same strategy.
+The outcome does not depend on the order of the arguments.
+
+	errors.SetPropertyMergeStrategy(errors.MergeSuffix)
+*/
+func SetPropertyMergeStrategy(strategy MergeStrategy) {
+	updateSettings(func(s *settings) {
+		s.mergeStrategy = strategy
+	})
+}
+
+// mergeProperties merges the properties of the wrapping error into those of the wrapped error per the strategy.
+// Neither map is modified.
+func mergeProperties(inner, outer map[string]any, strategy MergeStrategy) map[string]any {
+	if len(outer) == 0 {
+		return maps.Clone(inner)
+	}
+	merged := make(map[string]any, len(inner)+len(outer))
+	maps.Copy(merged, inner)
+	// Sorted for deterministic suffixes
+	for _, k := range slices.Sorted(maps.Keys(outer)) {
+		v := outer[k]
+		existing, conflict := merged[k]
+		if !conflict {
+			merged[k] = v
+			continue
+		}
+		existingGroup, ok1 := existing.(GroupedProperties)
+		group, ok2 := v.(GroupedProperties)
+		if ok1 && ok2 {
+			merged[k] = GroupedProperties(mergeProperties(existingGroup, group, strategy))
+			continue
+		}
+		switch strategy {
+		case MergeKeepInner:
+		case MergeSuffix:
+			merged[k] = v
+			if !reflect.DeepEqual(existing, v) {
+				for n := 1; ; n++ {
+					suffixed := k + "_" + strconv.Itoa(n)
+					_, taken := merged[suffixed]
+					_, reserved := outer[suffixed]
+					if !taken && !reserved {
+						merged[suffixed] = existing
+						break
+					}
+				}
+			}
+		default:
+			merged[k] = v
+		}
+	}
+	return merged
+}
@@ -0,0 +1,60 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"testing"
+)
+
+func TestErrors_MergeStrategy(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	defer SetPropertyMergeStrategy(MergeKeepOuter)
+
+	inner := New("inner", "id", "inner", "same", 1, "innerOnly", true, Group("db", "id", "inner"))
+
+	// The default is independent of the order of the arguments
+	for _, err := range []error{
+		New("outer", "id", "outer", inner, "same", 1, Group("db", "id", "outer")),
+		New("outer", inner, "id", "outer", "same", 1, Group("db", "id", "outer")),
+	} {
+		props := Convert(err).Properties
+		assertEqual(t, "outer", props["id"])
+		assertEqual(t, true, props["innerOnly"])
+		assertEqual(t, "outer", props["db"].(GroupedProperties)["id"])
+	}
+
+	SetPropertyMergeStrategy(MergeKeepInner)
+	props := Convert(New("outer", "id", "outer", inner, "outerOnly", true, Group("db", "id", "outer", "rows", 5))).Properties
+	assertEqual(t, "inner", props["id"])
+	assertEqual(t, true, props["innerOnly"])
+	assertEqual(t, true, props["outerOnly"])
+	assertEqual(t, "inner", props["db"].(GroupedProperties)["id"])
+	assertEqual(t, 5, props["db"].(GroupedProperties)["rows"])
+
+	SetPropertyMergeStrategy(MergeSuffix)
+	props = Convert(New("outer", inner, "id", "outer", "same", 1, "id_1", "taken")).Properties
+	assertEqual(t, "outer", props["id"])
+	assertEqual(t, "taken", props["id_1"])
+	assertEqual(t, "inner", props["id_2"])
+	assertEqual(t, 1, props["same"])
+	_, ok := props["same_1"]
+	assertTrue(t, !ok)
+
+	// The properties of the wrapped error are not modified
+	assertEqual(t, "inner", Convert(inner).Properties["id"])
+	assertEqual(t, "inner", Convert(inner).Properties["db"].(GroupedProperties)["id"])
+}
@@ -0,0 +1,522 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/binary"
+	stderrors "errors"
+	"maps"
+	"math"
+	"slices"
+)
+
+/*
+AppendBinary appends the MessagePack encoding of the error to b.
+The layout mirrors that of the JSON encoding: a map with the error, statusCode, trace, span, origin, stack and suppressed keys,
+alongside the properties of the error.
+Property values of types that have no native MessagePack representation are encoded following their JSON representation.
+*/
+func (e *TracedError) AppendBinary(b []byte) ([]byte, error) {
+	var visited visitedSet
+	return e.scrubbed().appendMsgpack(b, &visited), nil
+}
+
+// MarshalBinary marshals the error to MessagePack.
+func (e *TracedError) MarshalBinary() ([]byte, error) {
+	return e.AppendBinary(nil)
+}
+
+// UnmarshalBinary unmarshals the error from MessagePack.
+// Integer properties are restored as int64 or uint64.
+// Neither the type of the error nor any errors it wraps can be restored.
+func (e *TracedError) UnmarshalBinary(data []byte) error {
+	v, rest, err := msgpackDecode(data, 0)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return New("trailing data after msgpack value")
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return New("msgpack value is not a map")
+	}
+	return e.fromBinaryMap(m)
+}
+
+// appendMsgpack appends the MessagePack encoding of the error, skipping suppressed errors that were already visited.
+func (e *TracedError) appendMsgpack(b []byte, visited *visitedSet) []byte {
+	return msgpackAppend(b, e.binaryMap(visited))
+}
+
+// binaryMap returns the representation of the error that is encoded by the binary formats.
+// It mirrors the JSON representation, except that property values are retained as-is.
+func (e *TracedError) binaryMap(visited *visitedSet) map[string]any {
+	visited.visit(e)
+	m := map[string]any{}
+	if len(e.Properties) > 0 {
+		maps.Copy(m, e.Properties)
+	}
+	delete(m, "statusCode")
+	delete(m, "stack")
+	delete(m, "trace")
+	delete(m, "span")
+	delete(m, "origin")
+	delete(m, "suppressed")
+	m["error"] = e.Error()
+	if e.StatusCode != 0 {
+		m["statusCode"] = e.StatusCode
+	}
+	if e.Trace != "" && e.Trace != zeroTrace {
+		m["trace"] = e.Trace
+	}
+	if e.Span != "" && e.Span != zeroSpan {
+		m["span"] = e.Span
+	}
+	if e.Origin != OriginLocal {
+		m["origin"] = e.Origin.String()
+	}
+	if e.Stack != nil {
+		stack := make([]any, 0, len(e.Stack))
+		for _, frame := range e.Stack {
+			f := map[string]any{
+				"func": frame.Function,
+				"file": frame.File,
+				"line": frame.Line,
+			}
+			if frame.Repeat != 0 {
+				f["repeat"] = frame.Repeat
+			}
+			if frame.Site != "" {
+				f["site"] = frame.Site
+			}
+			stack = append(stack, f)
+		}
+		m["stack"] = stack
+	}
+	var suppressed []any
+	for _, s := range e.Suppressed {
+		if s != nil && visited.visit(s) {
+			suppressed = append(suppressed, Convert(s).binaryMap(visited))
+		}
+	}
+	if len(suppressed) > 0 {
+		m["suppressed"] = suppressed
+	}
+	return m
+}
+
+// fromBinaryMap restores the error from its decoded binary representation.
+func (e *TracedError) fromBinaryMap(m map[string]any) error {
+	msg, _ := m["error"].(string)
+	e.Err = stderrors.New(msg)
+	e.StatusCode = int(binaryInt(m["statusCode"]))
+	e.Trace, _ = m["trace"].(string)
+	e.Span, _ = m["span"].(string)
+	origin, _ := m["origin"].(string)
+	e.Origin = parseOrigin(origin)
+	e.Stack = nil
+	if stack, ok := m["stack"].([]any); ok {
+		e.Stack = make([]*StackFrame, 0, len(stack))
+		for _, f := range stack {
+			fm, ok := f.(map[string]any)
+			if !ok {
+				return New("malformed stack frame")
+			}
+			frame := &StackFrame{
+				Line:   int(binaryInt(fm["line"])),
+				Repeat: int(binaryInt(fm["repeat"])),
+			}
+			frame.Function, _ = fm["func"].(string)
+			frame.File, _ = fm["file"].(string)
+			frame.Site, _ = fm["site"].(string)
+			e.Stack = append(e.Stack, frame)
+		}
+	}
+	e.Suppressed = nil
+	if suppressed, ok := m["suppressed"].([]any); ok {
+		for _, s := range suppressed {
+			sm, ok := s.(map[string]any)
+			if !ok {
+				return New("malformed suppressed error")
+			}
+			suppressedErr := &TracedError{}
+			err := suppressedErr.fromBinaryMap(sm)
+			if err != nil {
+				return err
+			}
+			e.Suppressed = append(e.Suppressed, suppressedErr)
+		}
+	}
+	delete(m, "error")
+	delete(m, "statusCode")
+	delete(m, "stack")
+	delete(m, "trace")
+	delete(m, "span")
+	delete(m, "origin")
+	delete(m, "suppressed")
+	if len(m) > 0 {
+		e.Properties = m
+	} else {
+		e.Properties = nil
+	}
+	return nil
+}
+
+// binaryInt returns the value of a decoded integer, or 0 if not an integer.
+func binaryInt(v any) int64 {
+	switch x := v.(type) {
+	case int64:
+		return x
+	case uint64:
+		return int64(x)
+	}
+	return 0
+}
+
+// msgpackAppend appends the MessagePack encoding of a value.
+func msgpackAppend(b []byte, v any) []byte {
+	switch x := v.(type) {
+	case nil:
+		return append(b, 0xc0)
+	case bool:
+		if x {
+			return append(b, 0xc3)
+		}
+		return append(b, 0xc2)
+	case int:
+		return msgpackAppendInt(b, int64(x))
+	case int8:
+		return msgpackAppendInt(b, int64(x))
+	case int16:
+		return msgpackAppendInt(b, int64(x))
+	case int32:
+		return msgpackAppendInt(b, int64(x))
+	case int64:
+		return msgpackAppendInt(b, x)
+	case uint:
+		return msgpackAppendUint(b, uint64(x))
+	case uint8:
+		return msgpackAppendUint(b, uint64(x))
+	case uint16:
+		return msgpackAppendUint(b, uint64(x))
+	case uint32:
+		return msgpackAppendUint(b, uint64(x))
+	case uint64:
+		return msgpackAppendUint(b, x)
+	case float32:
+		b = append(b, 0xca)
+		return binary.BigEndian.AppendUint32(b, math.Float32bits(x))
+	case float64:
+		b = append(b, 0xcb)
+		return binary.BigEndian.AppendUint64(b, math.Float64bits(x))
+	case string:
+		return msgpackAppendString(b, x)
+	case []byte:
+		switch {
+		case len(x) <= math.MaxUint8:
+			b = append(b, 0xc4, byte(len(x)))
+		case len(x) <= math.MaxUint16:
+			b = append(b, 0xc5)
+			b = binary.BigEndian.AppendUint16(b, uint16(len(x)))
+		default:
+			b = append(b, 0xc6)
+			b = binary.BigEndian.AppendUint32(b, uint32(len(x)))
+		}
+		return append(b, x...)
+	case []any:
+		b = msgpackAppendArrayHeader(b, len(x))
+		for _, item := range x {
+			b = msgpackAppend(b, item)
+		}
+		return b
+	case map[string]any:
+		b = msgpackAppendMapHeader(b, len(x))
+		for _, k := range slices.Sorted(maps.Keys(x)) {
+			b = msgpackAppendString(b, k)
+			b = msgpackAppend(b, x[k])
+		}
+		return b
+	}
+	// Fall back to the JSON representation
+	normalized := normalizeProperties(map[string]any{"": v})
+	return msgpackAppend(b, normalized[""])
+}
+
+// msgpackAppendInt appends a signed integer in its most compact form.
+func msgpackAppendInt(b []byte, v int64) []byte {
+	switch {
+	case v >= 0:
+		return msgpackAppendUint(b, uint64(v))
+	case v >= -32:
+		return append(b, byte(v))
+	case v >= math.MinInt8:
+		return append(b, 0xd0, byte(v))
+	case v >= math.MinInt16:
+		b = append(b, 0xd1)
+		return binary.BigEndian.AppendUint16(b, uint16(v))
+	case v >= math.MinInt32:
+		b = append(b, 0xd2)
+		return binary.BigEndian.AppendUint32(b, uint32(v))
+	default:
+		b = append(b, 0xd3)
+		return binary.BigEndian.AppendUint64(b, uint64(v))
+	}
+}
+
+// msgpackAppendUint appends an unsigned integer in its most compact form.
+func msgpackAppendUint(b []byte, v uint64) []byte {
+	switch {
+	case v < 128:
+		return append(b, byte(v))
+	case v <= math.MaxUint8:
+		return append(b, 0xcc, byte(v))
+	case v <= math.MaxUint16:
+		b = append(b, 0xcd)
+		return binary.BigEndian.AppendUint16(b, uint16(v))
+	case v <= math.MaxUint32:
+		b = append(b, 0xce)
+		return binary.BigEndian.AppendUint32(b, uint32(v))
+	default:
+		b = append(b, 0xcf)
+		return binary.BigEndian.AppendUint64(b, v)
+	}
+}
+
+// msgpackAppendString appends a string.
+func msgpackAppendString(b []byte, s string) []byte {
+	switch {
+	case len(s) < 32:
+		b = append(b, 0xa0|byte(len(s)))
+	case len(s) <= math.MaxUint8:
+		b = append(b, 0xd9, byte(len(s)))
+	case len(s) <= math.MaxUint16:
+		b = append(b, 0xda)
+		b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	default:
+		b = append(b, 0xdb)
+		b = binary.BigEndian.AppendUint32(b, uint32(len(s)))
+	}
+	return append(b, s...)
+}
+
+// msgpackAppendArrayHeader appends the header of an array of n elements.
+func msgpackAppendArrayHeader(b []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(b, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		b = append(b, 0xdc)
+		return binary.BigEndian.AppendUint16(b, uint16(n))
+	default:
+		b = append(b, 0xdd)
+		return binary.BigEndian.AppendUint32(b, uint32(n))
+	}
+}
+
+// msgpackAppendMapHeader appends the header of a map of n entries.
+func msgpackAppendMapHeader(b []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(b, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		b = append(b, 0xde)
+		return binary.BigEndian.AppendUint16(b, uint16(n))
+	default:
+		b = append(b, 0xdf)
+		return binary.BigEndian.AppendUint32(b, uint32(n))
+	}
+}
+
+// msgpackDecode decodes a single value and returns the remaining data.
+// Maps are decoded as map[string]any, arrays as []any and integers as int64 or uint64.
+func msgpackDecode(data []byte, depth int) (v any, rest []byte, err error) {
+	if depth > 32 {
+		return nil, nil, New("msgpack value nested too deeply")
+	}
+	if len(data) == 0 {
+		return nil, nil, New("unexpected end of msgpack data")
+	}
+	c := data[0]
+	data = data[1:]
+	// next returns the next n bytes
+	next := func(n int) ([]byte, bool) {
+		if n < 0 || len(data) < n {
+			return nil, false
+		}
+		p := data[:n]
+		data = data[n:]
+		return p, true
+	}
+	// length reads a big-endian length of the given size
+	length := func(size int) (int, bool) {
+		p, ok := next(size)
+		if !ok {
+			return 0, false
+		}
+		switch size {
+		case 1:
+			return int(p[0]), true
+		case 2:
+			return int(binary.BigEndian.Uint16(p)), true
+		default:
+			return int(binary.BigEndian.Uint32(p)), true
+		}
+	}
+
+	var n int
+	var ok bool
+	switch {
+	case c < 0x80:
+		return int64(c), data, nil
+	case c >= 0xe0:
+		return int64(int8(c)), data, nil
+	case c&0xf0 == 0x80:
+		return msgpackDecodeMap(data, int(c&0x0f), depth)
+	case c&0xf0 == 0x90:
+		return msgpackDecodeArray(data, int(c&0x0f), depth)
+	case c&0xe0 == 0xa0:
+		p, ok := next(int(c & 0x1f))
+		if !ok {
+			return nil, nil, New("truncated msgpack data")
+		}
+		return string(p), data, nil
+	}
+	switch c {
+	case 0xc0:
+		return nil, data, nil
+	case 0xc2:
+		return false, data, nil
+	case 0xc3:
+		return true, data, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, ok = length(1 << (c - 0xc4))
+		if !ok {
+			return nil, nil, New("truncated msgpack data")
+		}
+		p, ok := next(n)
+		if !ok {
+			return nil, nil, New("truncated msgpack data")
+		}
+		return slices.Clone(p), data, nil
+	case 0xca:
+		p, ok := next(4)
+		if !ok {
+			return nil, nil, New("truncated msgpack data")
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(p))), data, nil
+	case 0xcb:
+		p, ok := next(8)
+		if !ok {
+			return nil, nil, New("truncated msgpack data")
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(p)), data, nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		p, ok := next(1 << (c - 0xcc))
+		if !ok {
+			return nil, nil, New("truncated msgpack data")
+		}
+		var u uint64
+		for _, x := range p {
+			u = u<<8 | uint64(x)
+		}
+		if u <= math.MaxInt64 {
+			return int64(u), data, nil
+		}
+		return u, data, nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		p, ok := next(1 << (c - 0xd0))
+		if !ok {
+			return nil, nil, New("truncated msgpack data")
+		}
+		switch len(p) {
+		case 1:
+			return int64(int8(p[0])), data, nil
+		case 2:
+			return int64(int16(binary.BigEndian.Uint16(p))), data, nil
+		case 4:
+			return int64(int32(binary.BigEndian.Uint32(p))), data, nil
+		default:
+			return int64(binary.BigEndian.Uint64(p)), data, nil
+		}
+	case 0xd9, 0xda, 0xdb:
+		n, ok = length(1 << (c - 0xd9))
+		if !ok {
+			return nil, nil, New("truncated msgpack data")
+		}
+		p, ok := next(n)
+		if !ok {
+			return nil, nil, New("truncated msgpack data")
+		}
+		return string(p), data, nil
+	case 0xdc, 0xdd:
+		n, ok = length(2 << (c - 0xdc))
+		if !ok {
+			return nil, nil, New("truncated msgpack data")
+		}
+		return msgpackDecodeArray(data, n, depth)
+	case 0xde, 0xdf:
+		n, ok = length(2 << (c - 0xde))
+		if !ok {
+			return nil, nil, New("truncated msgpack data")
+		}
+		return msgpackDecodeMap(data, n, depth)
+	}
+	return nil, nil, New("unsupported msgpack type 0x%02x", c)
+}
+
+// msgpackDecodeArray decodes n array elements.
+func msgpackDecodeArray(data []byte, n int, depth int) (any, []byte, error) {
+	if n > len(data) {
+		return nil, nil, New("truncated msgpack data")
+	}
+	arr := make([]any, 0, n)
+	for range n {
+		item, rest, err := msgpackDecode(data, depth+1)
+		if err != nil {
+			return nil, nil, err
+		}
+		arr = append(arr, item)
+		data = rest
+	}
+	return arr, data, nil
+}
+
+// msgpackDecodeMap decodes n map entries. Keys must be strings.
+func msgpackDecodeMap(data []byte, n int, depth int) (any, []byte, error) {
+	if n > len(data) {
+		return nil, nil, New("truncated msgpack data")
+	}
+	m := make(map[string]any, n)
+	for range n {
+		k, rest, err := msgpackDecode(data, depth+1)
+		if err != nil {
+			return nil, nil, err
+		}
+		key, ok := k.(string)
+		if !ok {
+			return nil, nil, New("msgpack map key is not a string")
+		}
+		v, rest, err := msgpackDecode(rest, depth+1)
+		if err != nil {
+			return nil, nil, err
+		}
+		m[key] = v
+		data = rest
+	}
+	return m, data, nil
+}
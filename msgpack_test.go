@@ -0,0 +1,93 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestErrors_MarshalBinary(t *testing.T) {
+	t.Parallel()
+
+	err := New("oops", 409, "00-0123456789abcdef0123456789abcdef-0123456789abcdef-01",
+		"str", "value",
+		"small", 5,
+		"negative", -300,
+		"big", uint64(1<<63),
+		"float", 1.5,
+		"flag", true,
+		"nothing", nil,
+		"bytes", []byte{1, 2, 3},
+		"list", []any{"a", 1},
+		"nested", map[string]any{"x": "y"},
+		"long", strings.Repeat("x", 300),
+		"struct", struct{ A int }{A: 1},
+	)
+	err = Trace(err)
+	err = AddSuppressed(err, New("rollback failed", "table", "users"))
+	original := Convert(err)
+
+	b, marshalErr := original.MarshalBinary()
+	assertNil(t, marshalErr)
+	var decoded TracedError
+	marshalErr = decoded.UnmarshalBinary(b)
+	assertNil(t, marshalErr)
+	assertEqual(t, "oops", decoded.Error())
+	assertEqual(t, 409, decoded.StatusCode)
+	assertEqual(t, "0123456789abcdef0123456789abcdef", decoded.Trace)
+	assertEqual(t, "0123456789abcdef", decoded.Span)
+	assertEqual(t, original.Stack, decoded.Stack)
+	assertEqual(t, "value", decoded.Properties["str"])
+	assertEqual(t, int64(5), decoded.Properties["small"])
+	assertEqual(t, int64(-300), decoded.Properties["negative"])
+	assertEqual(t, uint64(1<<63), decoded.Properties["big"])
+	assertEqual(t, 1.5, decoded.Properties["float"])
+	assertEqual(t, true, decoded.Properties["flag"])
+	assertEqual(t, nil, decoded.Properties["nothing"])
+	assertEqual(t, []byte{1, 2, 3}, decoded.Properties["bytes"])
+	assertEqual(t, []any{"a", int64(1)}, decoded.Properties["list"])
+	assertEqual(t, map[string]any{"x": "y"}, decoded.Properties["nested"])
+	assertEqual(t, strings.Repeat("x", 300), decoded.Properties["long"])
+	assertEqual(t, map[string]any{"A": 1.0}, decoded.Properties["struct"])
+	assertEqual(t, 1, len(decoded.Suppressed))
+	assertEqual(t, "rollback failed", decoded.Suppressed[0].Error())
+	assertEqual(t, "users", Convert(decoded.Suppressed[0]).Properties["table"])
+
+	// Smaller than JSON
+	j, _ := json.Marshal(original)
+	assertTrue(t, len(b) < len(j))
+
+	// AppendBinary appends
+	prefixed, marshalErr := original.AppendBinary([]byte("prefix"))
+	assertNil(t, marshalErr)
+	assertEqual(t, "prefix", string(prefixed[:6]))
+	assertEqual(t, b, prefixed[6:])
+}
+
+func TestErrors_UnmarshalBinaryMalformed(t *testing.T) {
+	t.Parallel()
+
+	var e TracedError
+	assertError(t, e.UnmarshalBinary(nil))
+	assertError(t, e.UnmarshalBinary([]byte{0x81, 0xa5, 'e'}))
+	assertError(t, e.UnmarshalBinary([]byte{0x01}))
+	assertError(t, e.UnmarshalBinary([]byte{0x80, 0x80}))
+	assertError(t, e.UnmarshalBinary([]byte{0xdf, 0xff, 0xff, 0xff, 0xff}))
+	assertError(t, e.UnmarshalBinary([]byte{0xc1}))
+}
@@ -0,0 +1,49 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+/*
+Must returns the value if the error is nil, or panics with the traced error otherwise.
+It is intended for initialization code where an error is not expected.
+
+	var tmpl = errors.Must(template.ParseFiles("index.html"))
+*/
+func Must[T any](v T, err error) T {
+	if err != nil {
+		panic(Trace(err))
+	}
+	return v
+}
+
+/*
+MustOK returns the value if ok is true, or panics with a traced error otherwise.
+
+	port := errors.MustOK(os.LookupEnv("PORT"))
+*/
+func MustOK[T any](v T, ok bool) T {
+	if !ok {
+		panic(New("value not ok"))
+	}
+	return v
+}
+
+// Check panics with the traced error if it is not nil.
+func Check(err error) {
+	if err != nil {
+		panic(Trace(err))
+	}
+}
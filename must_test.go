@@ -0,0 +1,67 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestErrors_Must(t *testing.T) {
+	t.Parallel()
+
+	assertEqual(t, 123, Must(strconv.Atoi("123")))
+
+	err := CatchPanic(func() error {
+		Must(strconv.Atoi("nan"))
+		return nil
+	})
+	assertError(t, err)
+	assertContains(t, err.Error(), "invalid syntax")
+	assertContains(t, Convert(err).Stack[0].Function, "TestErrors_Must")
+}
+
+func TestErrors_MustOK(t *testing.T) {
+	t.Parallel()
+
+	m := map[string]int{"one": 1}
+	v, ok := m["one"]
+	assertEqual(t, 1, MustOK(v, ok))
+
+	err := CatchPanic(func() error {
+		v, ok := m["two"]
+		MustOK(v, ok)
+		return nil
+	})
+	assertError(t, err)
+	assertContains(t, Convert(err).Stack[0].Function, "TestErrors_MustOK")
+}
+
+func TestErrors_Check(t *testing.T) {
+	t.Parallel()
+
+	Check(nil)
+
+	err := CatchPanic(func() error {
+		_, err := strconv.ParseBool("maybe")
+		Check(err)
+		return nil
+	})
+	assertError(t, err)
+	assertContains(t, err.Error(), "invalid syntax")
+	assertContains(t, Convert(err).Stack[0].Function, "TestErrors_Check")
+}
@@ -0,0 +1,97 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"net"
+	"syscall"
+)
+
+/*
+FromIO traces the error of an I/O operation on a connection, attaching the details of the failure as properties:
+
+  - "op" is the operation, e.g. "read", "write" or "dial"
+  - "remoteAddr" and "localAddr" are the addresses of the connection, if known
+  - "bytes" is the number of bytes transferred before the failure, if indicated by an unnamed int64
+  - "timeout" indicates if the operation timed out
+  - "temporary" indicates if the failure is likely to be transient, such as a timeout or a reset connection
+
+The connection is typically a net.Conn but can be any value with a RemoteAddr method, or nil.
+Timeouts are given the status code 504 unless a status code is passed explicitly.
+Other arguments can be passed as with Trace.
+
+	n, err := io.Copy(dst, conn)
+	if err != nil {
+		return errors.FromIO(err, "copy", conn, n)
+	}
+*/
+func FromIO(err error, op string, conn any, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	props := make([]any, 0, 12+len(args))
+	if op != "" {
+		props = append(props, "op", op)
+	}
+	remoteAddr, localAddr := ioAddrs(err, conn)
+	if remoteAddr != "" {
+		props = append(props, "remoteAddr", remoteAddr)
+	}
+	if localAddr != "" {
+		props = append(props, "localAddr", localAddr)
+	}
+	var netErr net.Error
+	timeout := As(err, &netErr) && netErr.Timeout()
+	temporary := timeout
+	if !temporary {
+		var tempErr interface{ Temporary() bool }
+		temporary = (As(err, &tempErr) && tempErr.Temporary()) ||
+			Is(err, syscall.ECONNRESET) || Is(err, syscall.ECONNABORTED) || Is(err, syscall.ECONNREFUSED) || Is(err, syscall.EPIPE)
+	}
+	props = append(props, "timeout", timeout, "temporary", temporary)
+	if timeout {
+		props = append(props, 504)
+	}
+	for _, arg := range args {
+		if n, ok := arg.(int64); ok {
+			props = append(props, "bytes", n)
+		} else {
+			props = append(props, arg)
+		}
+	}
+	return Trace(err, props...)
+}
+
+// ioAddrs returns the remote and local addresses of the connection, or of the network operation that failed.
+func ioAddrs(err error, conn any) (remoteAddr string, localAddr string) {
+	if c, ok := conn.(interface{ RemoteAddr() net.Addr }); ok && c.RemoteAddr() != nil {
+		remoteAddr = c.RemoteAddr().String()
+	}
+	if c, ok := conn.(interface{ LocalAddr() net.Addr }); ok && c.LocalAddr() != nil {
+		localAddr = c.LocalAddr().String()
+	}
+	var opErr *net.OpError
+	if As(err, &opErr) {
+		if remoteAddr == "" && opErr.Addr != nil {
+			remoteAddr = opErr.Addr.String()
+		}
+		if localAddr == "" && opErr.Source != nil {
+			localAddr = opErr.Source.String()
+		}
+	}
+	return remoteAddr, localAddr
+}
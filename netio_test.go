@@ -0,0 +1,70 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestErrors_FromIO(t *testing.T) {
+	t.Parallel()
+
+	assertNil(t, FromIO(nil, "read", nil))
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assertNil(t, err)
+	defer lis.Close()
+	conn, err := net.Dial("tcp", lis.Addr().String())
+	assertNil(t, err)
+	defer conn.Close()
+
+	// Timeout
+	conn.SetReadDeadline(time.Now().Add(-time.Second))
+	_, readErr := conn.Read(make([]byte, 1))
+	err = FromIO(readErr, "read", conn, int64(12))
+	assertEqual(t, 504, StatusCode(err))
+	props := Convert(err).Properties
+	assertEqual(t, "read", props["op"])
+	assertEqual(t, lis.Addr().String(), props["remoteAddr"])
+	assertEqual(t, conn.LocalAddr().String(), props["localAddr"])
+	assertEqual(t, int64(12), props["bytes"])
+	assertEqual(t, true, props["timeout"])
+	assertEqual(t, true, props["temporary"])
+
+	// Explicit status code
+	err = FromIO(readErr, "read", conn, 503)
+	assertEqual(t, 503, StatusCode(err))
+
+	// Reset connection, with the address taken from the network operation
+	opErr := &net.OpError{Op: "write", Net: "tcp", Addr: lis.Addr(), Err: fmt.Errorf("write: %w", syscall.ECONNRESET)}
+	err = FromIO(opErr, "write", nil)
+	assertEqual(t, 500, StatusCode(err))
+	props = Convert(err).Properties
+	assertEqual(t, lis.Addr().String(), props["remoteAddr"])
+	assertEqual(t, false, props["timeout"])
+	assertEqual(t, true, props["temporary"])
+
+	// Not temporary
+	err = FromIO(New("bad frame"), "decode", nil, "frame", 7)
+	props = Convert(err).Properties
+	assertEqual(t, false, props["temporary"])
+	assertEqual(t, 7, props["frame"])
+}
@@ -0,0 +1,131 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	"net"
+)
+
+// Origin indicates where an error originated, so that local faults can be told apart from the faults of dependencies.
+// An origin other than local is carried by all the encodings of the error, so that it survives hops between services.
+type Origin int
+
+const (
+	// OriginLocal indicates that the error originated in this process.
+	OriginLocal Origin = iota
+	// OriginUpstream indicates that the error originated in an upstream dependency, such as a service called over HTTP or gRPC.
+	OriginUpstream
+	// OriginClient indicates that the error is the fault of the client, such as invalid input.
+	OriginClient
+)
+
+// String returns the name of the origin.
+func (o Origin) String() string {
+	switch o {
+	case OriginLocal:
+		return "local"
+	case OriginUpstream:
+		return "upstream"
+	case OriginClient:
+		return "client"
+	default:
+		return "unknown"
+	}
+}
+
+// parseOrigin parses the name of an origin. Unknown names are parsed as the local origin.
+func parseOrigin(s string) Origin {
+	switch s {
+	case "upstream":
+		return OriginUpstream
+	case "client":
+		return OriginClient
+	default:
+		return OriginLocal
+	}
+}
+
+/*
+OriginOf returns the origin of the error.
+Errors reconstructed from upstream responses by FromHTTPResponse, FromRequest or the RPC adapters are attributed to the upstream origin.
+Otherwise, errors with a 4xx status code are attributed to the client, and others are local.
+The origin of a traced error is respected even if it is wrapped by another error.
+
+	if errors.OriginOf(err) == errors.OriginUpstream && errors.StatusCode(err) == 500 {
+		err = errors.Trace(err, http.StatusBadGateway)
+	}
+*/
+func OriginOf(err error) Origin {
+	if err == nil {
+		return OriginLocal
+	}
+	if tracedErr := findTraced(err); tracedErr != nil && tracedErr.Origin != OriginLocal {
+		return tracedErr.Origin
+	}
+	if statusCode := StatusCode(err); statusCode >= 400 && statusCode < 500 {
+		return OriginClient
+	}
+	return OriginLocal
+}
+
+// WithOrigin attributes the error to the origin. The original error is not modified.
+func WithOrigin(err error, origin Origin) error {
+	if err == nil {
+		return nil
+	}
+	tracedErr := deriveTraced(err)
+	tracedErr.Origin = origin
+	return tracedErr
+}
+
+/*
+AsUpstream implements the semantics of a gateway for an error received from an upstream dependency.
+A 5xx status code is rewritten to 504 if the upstream timed out, or to 502 otherwise,
+and the original status code is preserved in the "upstreamStatusCode" property.
+Other status codes are kept as they are. In all cases, the error is attributed to the upstream origin.
+The original error is not modified.
+
+	res, err := http.DefaultClient.Do(req)
+	if err := errors.FromRequest(err, req, res); err != nil {
+		return errors.AsUpstream(err)
+	}
+*/
+func AsUpstream(err error) error {
+	if err == nil {
+		return nil
+	}
+	statusCode := StatusCode(err)
+	tracedErr := deriveTraced(err)
+	tracedErr.Origin = OriginUpstream
+	if statusCode < 500 || statusCode > 599 {
+		return tracedErr
+	}
+	gatewayStatusCode := 502
+	var netErr net.Error
+	if statusCode == 504 || Is(err, context.DeadlineExceeded) || (As(err, &netErr) && netErr.Timeout()) {
+		gatewayStatusCode = 504
+	}
+	tracedErr.StatusCode = gatewayStatusCode
+	if _, ok := tracedErr.Properties["upstreamStatusCode"]; !ok {
+		if tracedErr.Properties == nil {
+			tracedErr.Properties = map[string]any{}
+		}
+		tracedErr.Properties["upstreamStatusCode"] = statusCode
+	}
+	return tracedErr
+}
@@ -0,0 +1,188 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestErrors_OriginOf(t *testing.T) {
+	t.Parallel()
+
+	assertEqual(t, OriginLocal, OriginOf(nil))
+	assertEqual(t, OriginLocal, OriginOf(New("oops")))
+	assertEqual(t, OriginClient, OriginOf(New("bad input", 400)))
+
+	res := &http.Response{StatusCode: 500, Body: io.NopCloser(strings.NewReader("oops"))}
+	err := FromHTTPResponse(res)
+	assertEqual(t, OriginUpstream, OriginOf(err))
+	// Carried over when traced or wrapped
+	assertEqual(t, OriginUpstream, OriginOf(Trace(err)))
+	assertEqual(t, OriginUpstream, OriginOf(New("failed to call", err)))
+	assertEqual(t, OriginUpstream, OriginOf(Convert(err).derive()))
+
+	// Upstream 4xx errors are still attributed to the upstream origin
+	res = &http.Response{StatusCode: 404, Body: io.NopCloser(strings.NewReader("not found"))}
+	assertEqual(t, OriginUpstream, OriginOf(FromHTTPResponse(res)))
+
+	original := New("oops")
+	err = WithOrigin(original, OriginUpstream)
+	assertEqual(t, OriginUpstream, OriginOf(err))
+	assertEqual(t, OriginLocal, OriginOf(original))
+	assertNil(t, WithOrigin(nil, OriginClient))
+}
+
+func TestErrors_OriginString(t *testing.T) {
+	t.Parallel()
+
+	err := WithOrigin(New("oops"), OriginUpstream)
+	s := Convert(err).String()
+	assertContains(t, s, "\norigin=upstream")
+	assertTrue(t, !strings.Contains(Convert(New("oops")).String(), "origin="))
+
+	parsed, parseErr := ParseString(s)
+	assertNil(t, parseErr)
+	assertEqual(t, OriginUpstream, OriginOf(parsed))
+	assertEqual(t, 0, len(Convert(parsed).Properties))
+
+	assertEqual(t, "client", OriginClient.String())
+}
+
+func TestErrors_AsUpstream(t *testing.T) {
+	t.Parallel()
+
+	assertNil(t, AsUpstream(nil))
+
+	original := New("oops", 503)
+	err := AsUpstream(original)
+	assertEqual(t, 502, StatusCode(err))
+	assertEqual(t, 503, Convert(err).Properties["upstreamStatusCode"])
+	assertEqual(t, OriginUpstream, OriginOf(err))
+	assertEqual(t, 503, StatusCode(original))
+	assertTrue(t, Is(err, original))
+
+	// Applying twice keeps the original status code
+	err = AsUpstream(err)
+	assertEqual(t, 502, StatusCode(err))
+	assertEqual(t, 503, Convert(err).Properties["upstreamStatusCode"])
+
+	// Timeouts
+	err = AsUpstream(New("slow", 504))
+	assertEqual(t, 504, StatusCode(err))
+	err = AsUpstream(New("slow", context.DeadlineExceeded, 500))
+	assertEqual(t, 504, StatusCode(err))
+	assertEqual(t, 500, Convert(err).Properties["upstreamStatusCode"])
+
+	// Non-5xx status codes are kept
+	err = AsUpstream(New("not found", 404))
+	assertEqual(t, 404, StatusCode(err))
+	_, ok := Convert(err).Properties["upstreamStatusCode"]
+	assertTrue(t, !ok)
+	assertEqual(t, OriginUpstream, OriginOf(err))
+}
+
+func TestErrors_OriginRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	original := Convert(AsUpstream(New("bad gateway", 500, "key", "value")))
+	assertEqual(t, OriginUpstream, original.Origin)
+	local := Convert(New("oops", 400))
+
+	codecs := map[string]func(e *TracedError) (*TracedError, error){
+		"JSON": func(e *TracedError) (*TracedError, error) {
+			data, err := json.Marshal(e)
+			if err != nil {
+				return nil, err
+			}
+			var decoded TracedError
+			return &decoded, json.Unmarshal(data, &decoded)
+		},
+		"MessagePack": func(e *TracedError) (*TracedError, error) {
+			data, err := e.MarshalBinary()
+			if err != nil {
+				return nil, err
+			}
+			var decoded TracedError
+			return &decoded, decoded.UnmarshalBinary(data)
+		},
+		"CBOR": func(e *TracedError) (*TracedError, error) {
+			data, err := e.MarshalCBOR()
+			if err != nil {
+				return nil, err
+			}
+			var decoded TracedError
+			return &decoded, decoded.UnmarshalCBOR(data)
+		},
+		"gob": func(e *TracedError) (*TracedError, error) {
+			data, err := e.GobEncode()
+			if err != nil {
+				return nil, err
+			}
+			var decoded TracedError
+			return &decoded, decoded.GobDecode(data)
+		},
+		"proto": func(e *TracedError) (*TracedError, error) {
+			data, err := ToProto(e)
+			if err != nil {
+				return nil, err
+			}
+			return FromProto(data)
+		},
+		"text": func(e *TracedError) (*TracedError, error) {
+			data, err := e.MarshalText()
+			if err != nil {
+				return nil, err
+			}
+			var decoded TracedError
+			return &decoded, decoded.UnmarshalText(data)
+		},
+		"map": func(e *TracedError) (*TracedError, error) {
+			return Convert(DecodeFromMap(EncodeToMap(e))), nil
+		},
+		"header": func(e *TracedError) (*TracedError, error) {
+			h := http.Header{}
+			ToHeader(h, e)
+			return Convert(FromHeader(h)), nil
+		},
+	}
+	for name, codec := range codecs {
+		decoded, err := codec(original)
+		assertNil(t, err)
+		if decoded.Origin != OriginUpstream {
+			t.Errorf("%s: got origin %v, want upstream", name, decoded.Origin)
+		}
+		assertEqual(t, 502, decoded.StatusCode)
+		decoded, err = codec(local)
+		assertNil(t, err)
+		if decoded.Origin != OriginLocal {
+			t.Errorf("%s: got origin %v, want local", name, decoded.Origin)
+		}
+		assertEqual(t, 400, decoded.StatusCode)
+	}
+
+	text, _ := original.MarshalText()
+	assertContains(t, string(text), " | 502 upstream | ")
+	data, _ := json.Marshal(original)
+	assertContains(t, string(data), `"origin":"upstream"`)
+	_, ok := original.Properties["origin"]
+	assertTrue(t, !ok)
+}
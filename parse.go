@@ -0,0 +1,161 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	stderrors "errors"
+	"strconv"
+	"strings"
+)
+
+/*
+ParseString reconstructs a traced error from the output of its String method, for example when read from a plain-text log.
+The values of properties are restored as strings.
+Neither the type of the error nor any errors it wraps can be restored.
+
+	tracedErr, err := errors.ParseString(logLine)
+*/
+func ParseString(s string) (*TracedError, error) {
+	s = strings.TrimRight(s, "\n")
+	if strings.TrimSpace(s) == "" {
+		return nil, New("empty string")
+	}
+	lines := strings.Split(s, "\n")
+
+	// The header ends at the first blank line that is followed by a stack frame, a raw panic stack or a suppressed error
+	end := len(lines)
+	for i := 1; i < len(lines)-1; i++ {
+		if lines[i] == "" && (strings.HasPrefix(lines[i+1], "- ") || lines[i+1] == "raw panic stack:" || strings.HasPrefix(lines[i+1], "suppressed: ")) {
+			end = i
+			break
+		}
+	}
+	e := &TracedError{}
+	msg := []string{lines[0]}
+	metadata := false
+	for _, line := range lines[1:end] {
+		k, v, ok := strings.Cut(line, "=")
+		if !ok || k == "" || strings.ContainsAny(k, " \t") {
+			if !metadata {
+				msg = append(msg, line)
+				continue
+			}
+			return nil, New("malformed property '%s'", line)
+		}
+		metadata = true
+		switch k {
+		case "statusCode":
+			statusCode, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, New("malformed status code '%s'", v, err)
+			}
+			e.StatusCode = statusCode
+		case "trace":
+			e.Trace = v
+		case "span":
+			e.Span = v
+		case "origin":
+			e.Origin = parseOrigin(v)
+		default:
+			if e.Properties == nil {
+				e.Properties = map[string]any{}
+			}
+			e.Properties[k] = v
+		}
+	}
+	e.Err = stderrors.New(strings.Join(msg, "\n"))
+	if e.StatusCode == 0 {
+		e.StatusCode = 500
+	}
+
+	// Stack, raw panic stack and suppressed errors
+	for i := end; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "- "):
+			frame, n, err := parseStackFrame(lines[i:])
+			if err != nil {
+				return nil, err
+			}
+			e.Stack = append(e.Stack, frame)
+			i += n - 1
+		case line == "raw panic stack:":
+			var raw []string
+			for i+1 < len(lines) && strings.HasPrefix(lines[i+1], "  ") {
+				i++
+				raw = append(raw, lines[i][2:])
+			}
+			e.RawPanicStack = strings.Join(raw, "\n") + "\n"
+		case strings.HasPrefix(line, "suppressed: "):
+			nested := []string{strings.TrimPrefix(line, "suppressed: ")}
+			for i+1 < len(lines) && strings.HasPrefix(lines[i+1], "  ") {
+				i++
+				nested = append(nested, lines[i][2:])
+			}
+			suppressed, err := ParseString(strings.Join(nested, "\n"))
+			if err != nil {
+				return nil, err
+			}
+			e.Suppressed = append(e.Suppressed, suppressed)
+		default:
+			return nil, New("unexpected line '%s'", line)
+		}
+	}
+	return e, nil
+}
+
+// parseStackFrame parses a stack frame from the lines, returning the number of lines consumed.
+// Source code snippets that follow the frame are skipped.
+func parseStackFrame(lines []string) (frame *StackFrame, n int, err error) {
+	function := strings.TrimPrefix(lines[0], "- ")
+	frame = &StackFrame{Function: function}
+	if frame.isPseudo() {
+		return frame, 1, nil
+	}
+	if p := strings.LastIndex(function, " ["); p > 0 && strings.HasSuffix(function, "]") {
+		frame.Site = function[p+2 : len(function)-1]
+		function = function[:p]
+		frame.Function = function
+	}
+	if p := strings.LastIndex(function, " (x"); p > 0 && strings.HasSuffix(function, ")") {
+		repeat, err := strconv.Atoi(function[p+3 : len(function)-1])
+		if err == nil {
+			frame.Function = function[:p]
+			frame.Repeat = repeat
+		}
+	}
+	if len(lines) < 2 || !strings.HasPrefix(lines[1], "  ") {
+		return nil, 0, New("missing location of stack frame '%s'", function)
+	}
+	location := strings.TrimPrefix(lines[1], "  ")
+	p := strings.LastIndex(location, ":")
+	if p < 0 {
+		return nil, 0, New("malformed location '%s'", location)
+	}
+	frame.File = location[:p]
+	frame.Line, err = strconv.Atoi(location[p+1:])
+	if err != nil {
+		return nil, 0, New("malformed location '%s'", location, err)
+	}
+	n = 2
+	for n < len(lines) && strings.HasPrefix(lines[n], "  ") {
+		n++
+	}
+	return frame, n, nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	stderrors "errors"
+	"testing"
+)
+
+func TestErrors_ParseString(t *testing.T) {
+	t.Parallel()
+
+	err := New("failed to parse", 400, "0123456789abcdef0123456789abcdef", "key", "value", "id", "123")
+	err = Trace(err)
+	err = AddSuppressed(err, New("rollback failed", "table", "users"))
+	original := Convert(err)
+
+	parsed, parseErr := ParseString(original.String())
+	assertNil(t, parseErr)
+	assertEqual(t, original.Error(), parsed.Error())
+	assertEqual(t, 400, parsed.StatusCode)
+	assertEqual(t, "0123456789abcdef0123456789abcdef", parsed.Trace)
+	assertEqual(t, map[string]any{"key": "value", "id": "123"}, parsed.Properties)
+	assertEqual(t, original.Stack, parsed.Stack)
+	assertEqual(t, 1, len(parsed.Suppressed))
+	assertEqual(t, original.String(), parsed.String())
+
+	// Multi-line message, repeated and elided frames
+	tracedErr := Convert(New("", stderrors.Join(stderrors.New("line 1"), stderrors.New("line 2"))))
+	tracedErr.Stack = append(tracedErr.Stack, &StackFrame{Function: "main.main", File: "/src/main.go", Line: 5, Repeat: 3}, elisionFrame(4))
+	parsed, parseErr = ParseString(tracedErr.String())
+	assertNil(t, parseErr)
+	assertEqual(t, "line 1\nline 2", parsed.Error())
+	assertEqual(t, 500, parsed.StatusCode)
+	assertEqual(t, tracedErr.Stack, parsed.Stack)
+
+	// Malformed input
+	_, parseErr = ParseString("")
+	assertError(t, parseErr)
+	_, parseErr = ParseString("oops\nstatusCode=abc")
+	assertError(t, parseErr)
+	_, parseErr = ParseString("oops\n\n- main.main\n  /src/main.go:xyz")
+	assertError(t, parseErr)
+	_, parseErr = ParseString("oops\n\n- main.main")
+	assertError(t, parseErr)
+}
+
+func TestErrors_ParseStringDebugMode(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	SetDebugMode(true)
+	defer SetDebugMode(false)
+
+	err := Convert(New("oops"))
+	parsed, parseErr := ParseString(err.String())
+	assertNil(t, parseErr)
+	assertEqual(t, err.Stack, parsed.Stack)
+}
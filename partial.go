@@ -0,0 +1,113 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/json"
+)
+
+// Ensure interfaces
+var (
+	_ = json.Marshaler(Partial[any]{})
+	_ = json.Unmarshaler(&Partial[any]{})
+)
+
+/*
+Partial is the result of a batch operation that may succeed for some items and fail for others.
+It carries the value of the operation along with the failures of individual items,
+each identified by its index or key in the "index" or "key" property.
+
+	var result errors.Partial[[]User]
+	for i, id := range ids {
+		user, err := loadUser(ctx, id)
+		if err != nil {
+			result.FailAt(i, err)
+			continue
+		}
+		result.Value = append(result.Value, user)
+	}
+	return result
+*/
+type Partial[T any] struct {
+	Value T
+
+	failures []error
+}
+
+// FailAt records the failure of the item at the index.
+// The failure is traced and the index is attached to it as the "index" property. A nil error is ignored.
+func (p *Partial[T]) FailAt(index int, err error) {
+	if err == nil {
+		return
+	}
+	p.failures = append(p.failures, Trace(err, "index", index))
+}
+
+// FailKey records the failure of the item with the key.
+// The failure is traced and the key is attached to it as the "key" property. A nil error is ignored.
+func (p *Partial[T]) FailKey(key string, err error) {
+	if err == nil {
+		return
+	}
+	p.failures = append(p.failures, Trace(err, "key", key))
+}
+
+// Failures returns the failures of individual items, in the order they were recorded.
+func (p *Partial[T]) Failures() []error {
+	return append([]error(nil), p.failures...)
+}
+
+// Err returns the failures of individual items joined into a single traced error, or nil if there are none.
+func (p *Partial[T]) Err() error {
+	return Join(p.failures...)
+}
+
+// partialJSON is the JSON representation of a partial result.
+type partialJSON[T any] struct {
+	Value  T              `json:"value"`
+	Errors []*TracedError `json:"errors,omitzero"`
+}
+
+// MarshalJSON marshals the value and the failures of individual items to JSON.
+// It has a value receiver so that partial results are marshaled correctly when not referenced by a pointer.
+func (p Partial[T]) MarshalJSON() ([]byte, error) {
+	j := partialJSON[T]{
+		Value: p.Value,
+	}
+	for _, err := range p.failures {
+		j.Errors = append(j.Errors, Convert(err))
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON unmarshals the value and the failures of individual items from JSON.
+// The failures are restored as traced errors, subject to the limits of TracedError.UnmarshalJSON.
+func (p *Partial[T]) UnmarshalJSON(data []byte) error {
+	var j partialJSON[T]
+	err := json.Unmarshal(data, &j)
+	if err != nil {
+		return err
+	}
+	p.Value = j.Value
+	p.failures = nil
+	for _, e := range j.Errors {
+		if e != nil {
+			p.failures = append(p.failures, e)
+		}
+	}
+	return nil
+}
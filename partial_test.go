@@ -0,0 +1,73 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestErrors_Partial(t *testing.T) {
+	t.Parallel()
+
+	var p Partial[[]string]
+	assertNil(t, p.Err())
+	p.Value = append(p.Value, "a")
+	p.FailAt(1, New("bad item", 400))
+	p.FailKey("c", New("missing item", 404))
+	p.FailAt(3, nil)
+	p.Value = append(p.Value, "d")
+
+	failures := p.Failures()
+	assertEqual(t, 2, len(failures))
+	assertEqual(t, 1, Convert(failures[0]).Properties["index"])
+	assertEqual(t, "c", Convert(failures[1]).Properties["key"])
+	assertEqual(t, 400, StatusCode(failures[0]))
+
+	err := p.Err()
+	assertError(t, err)
+	assertContains(t, err.Error(), "bad item")
+	assertContains(t, err.Error(), "missing item")
+}
+
+func TestErrors_PartialJSON(t *testing.T) {
+	t.Parallel()
+
+	var p Partial[[]string]
+	p.Value = []string{"a", "d"}
+	p.FailAt(1, New("bad item", 400))
+	p.FailKey("c", New("missing item", 404))
+
+	b, err := json.Marshal(&p)
+	assertNil(t, err)
+
+	var unmarshaled Partial[[]string]
+	assertNil(t, json.Unmarshal(b, &unmarshaled))
+	assertEqual(t, 2, len(unmarshaled.Value))
+	assertEqual(t, "d", unmarshaled.Value[1])
+	failures := unmarshaled.Failures()
+	assertEqual(t, 2, len(failures))
+	assertEqual(t, "bad item", failures[0].Error())
+	assertEqual(t, 400, StatusCode(failures[0]))
+	assertEqual(t, float64(1), Convert(failures[0]).Properties["index"])
+	assertEqual(t, "c", Convert(failures[1]).Properties["key"])
+
+	// No failures
+	b, err = json.Marshal(Partial[int]{Value: 5})
+	assertNil(t, err)
+	assertEqual(t, `{"value":5}`, string(b))
+}
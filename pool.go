@@ -0,0 +1,103 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import "sync"
+
+var (
+	tracedErrorPool = sync.Pool{New: func() any { return &TracedError{} }}
+	stackFramePool  = sync.Pool{New: func() any { return &StackFrame{} }}
+)
+
+/*
+EnablePooling enables or disables allocating the traced errors and stack frames created from this point on from pools,
+so that services that create large numbers of short-lived errors can recycle them with Release, reducing GC pressure.
+Errors that are not released are garbage collected as usual.
+Pooling is disabled by default. Errors created while pooling was enabled can still be released after it is disabled.
+
+	func main() {
+		errors.EnablePooling(true)
+		...
+	}
+*/
+func EnablePooling(enabled bool) {
+	updateSettings(func(s *settings) {
+		s.pooling = enabled
+	})
+}
+
+// newTracedError returns a new empty traced error, allocated from the pool if pooling is enabled.
+func newTracedError() *TracedError {
+	if !loadSettings().pooling {
+		return &TracedError{}
+	}
+	e := tracedErrorPool.Get().(*TracedError)
+	e.pooled = true
+	return e
+}
+
+// allocCopy returns a copy of the stack frame, allocated from the pool if pooling is enabled.
+func allocCopy(frame StackFrame) *StackFrame {
+	if !loadSettings().pooling {
+		return &frame
+	}
+	pooledFrame := stackFramePool.Get().(*StackFrame)
+	*pooledFrame = frame
+	return pooledFrame
+}
+
+// ownFrame marks a newly captured stack frame as owned by the error if the error was allocated from the pool,
+// so that the frame is released along with the error.
+// Frames are shared by the errors derived from the error, but only the owner releases them.
+func (e *TracedError) ownFrame(frame *StackFrame) *StackFrame {
+	if e.pooled {
+		frame.owner = e
+	}
+	return frame
+}
+
+/*
+Release returns the traced error and the stack frames it captured to the pools, so that they can be recycled.
+Only errors that were allocated from the pools per EnablePooling are released.
+Sentinel errors are never released.
+
+The errors that the error wraps, its suppressed errors and the stack frames that it inherited from them are not released,
+because they may still be referenced by other errors, and are garbage collected as usual.
+It is therefore safe to release an error derived by Trace, With and the like and continue to use the original error.
+Conversely, an error must not be released while errors derived from it are in use,
+so it is the outermost error that should be released. An error must not be used after it is released.
+
+	err := process(req)
+	if err != nil {
+		logger.Error("Request failed", "err", err)
+		errors.Release(err)
+	}
+*/
+func Release(err error) {
+	tracedErr, ok := err.(*TracedError)
+	if !ok || !tracedErr.pooled {
+		return
+	}
+	for _, frame := range tracedErr.Stack {
+		if frame.owner == tracedErr {
+			*frame = StackFrame{}
+			stackFramePool.Put(frame)
+		}
+	}
+	*tracedErr = TracedError{}
+	tracedErrorPool.Put(tracedErr)
+}
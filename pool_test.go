@@ -0,0 +1,135 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	"io/fs"
+	"testing"
+)
+
+func TestErrors_Pooling(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	EnablePooling(true)
+	defer EnablePooling(false)
+
+	sentinel := Sentinel("not found", 404)
+	inner := New("inner", sentinel, "key", "value")
+	outer := Trace(inner)
+	err := AddSuppressed(outer, New("rollback failed"))
+	tracedErr := Convert(err)
+	assertTrue(t, Convert(inner).pooled)
+	assertTrue(t, Convert(outer).pooled)
+	assertTrue(t, tracedErr.pooled)
+	assertTrue(t, !Convert(sentinel).pooled)
+	assertEqual(t, 2, len(tracedErr.Stack))
+	innerFrame := Convert(inner).Stack[0]
+	outerFrame := Convert(outer).Stack[1]
+	assertEqual(t, inner, innerFrame.owner)
+	assertEqual(t, outer, outerFrame.owner)
+	suppressed := Convert(tracedErr.Suppressed[0])
+
+	// Only the outermost error and the frames it captured are released
+	Release(outer)
+	assertNil(t, outer.(*TracedError).Err)
+	assertEqual(t, "", outerFrame.Function)
+	assertEqual(t, "inner: not found", inner.Error())
+	assertEqual(t, "errors.TestErrors_Pooling", innerFrame.Function)
+	assertEqual(t, "rollback failed", suppressed.Error())
+
+	// Sentinel errors are not released
+	Release(sentinel)
+	assertEqual(t, "not found", sentinel.Error())
+	assertEqual(t, 404, StatusCode(sentinel))
+	assertTrue(t, Is(New("again", sentinel), sentinel))
+
+	// Errors not allocated from the pool are not released
+	Release(fs.ErrNotExist)
+	Release(nil)
+
+	// Errors created while pooling was enabled are released after it is disabled
+	pooled := New("pooled")
+	EnablePooling(false)
+	unpooled := New("unpooled")
+	assertTrue(t, !Convert(unpooled).pooled)
+	Release(pooled)
+	assertNil(t, pooled.(*TracedError).Err)
+	Release(unpooled)
+	assertEqual(t, "unpooled", unpooled.Error())
+}
+
+func TestErrors_ReleaseDerived(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	EnablePooling(true)
+	defer EnablePooling(false)
+
+	original := New("original", 400, "key", "value")
+	traced := Trace(original)
+	with := With(original, "more", "value")
+	public := Public(context.Background(), original)
+
+	Release(traced)
+	Release(with)
+	Release(public)
+
+	// The original error and its stack are intact
+	tracedErr := Convert(original)
+	assertEqual(t, "original", tracedErr.Error())
+	assertEqual(t, 400, tracedErr.StatusCode)
+	assertEqual(t, "value", tracedErr.Properties["key"])
+	assertEqual(t, 1, len(tracedErr.Stack))
+	assertEqual(t, "errors.TestErrors_ReleaseDerived", tracedErr.Stack[0].Function)
+
+	// Errors derived after the release do not reuse the stack frames of the original error
+	again := Convert(Trace(original))
+	assertEqual(t, "errors.TestErrors_ReleaseDerived", again.Stack[0].Function)
+	assertEqual(t, "errors.TestErrors_ReleaseDerived", again.Stack[1].Function)
+	Release(again)
+	assertEqual(t, "errors.TestErrors_ReleaseDerived", tracedErr.Stack[0].Function)
+
+	// Releasing the original error releases its frames
+	frame := tracedErr.Stack[0]
+	Release(original)
+	assertEqual(t, "", frame.Function)
+}
+
+func TestErrors_ReleaseWithoutPooling(t *testing.T) {
+	t.Parallel()
+
+	err := New("oops")
+	Release(err)
+	assertEqual(t, "oops", err.Error())
+	assertEqual(t, 1, len(Convert(err).Stack))
+}
+
+func BenchmarkErrors_New(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		err := New("oops", 400, "key", "value")
+		_ = Trace(err)
+	}
+}
+
+func BenchmarkErrors_NewPooled(b *testing.B) {
+	EnablePooling(true)
+	defer EnablePooling(false)
+	b.ReportAllocs()
+	for b.Loop() {
+		err := New("oops", 400, "key", "value")
+		Release(Trace(err))
+	}
+}
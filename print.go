@@ -0,0 +1,247 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiDim   = "\x1b[2m"
+	ansiRed   = "\x1b[31m"
+	ansiCyan  = "\x1b[36m"
+)
+
+// PrintOption customizes the output of Fprint.
+type PrintOption func(opts *printOptions)
+
+type printOptions struct {
+	color    *bool
+	indent   string
+	stack    bool
+	goFormat bool
+}
+
+// WithColor enables or disables ANSI colors.
+// By default, colors are enabled when writing to a terminal, unless the NO_COLOR environment variable is set.
+func WithColor(enabled bool) PrintOption {
+	return func(opts *printOptions) {
+		opts.color = &enabled
+	}
+}
+
+// WithIndent sets the string used to indent nested content, such as the causes of the error.
+// The default is two spaces.
+func WithIndent(indent string) PrintOption {
+	return func(opts *printOptions) {
+		opts.indent = indent
+	}
+}
+
+// WithStack includes or omits the stack trace. The stack trace is included by default.
+func WithStack(enabled bool) PrintOption {
+	return func(opts *printOptions) {
+		opts.stack = enabled
+	}
+}
+
+// WithGoroutineFormat renders the stack trace in the format of Go's panics and goroutine dumps,
+// which is recognized by tools that parse Go stack traces, such as IDEs and log viewers.
+// Functions are qualified by their full import path, as by runtime.Stack, if the stack frames were captured locally.
+//
+//	github.com/my-org/my-app/pkg.Func(...)
+//		/path/to/file.go:123
+func WithGoroutineFormat(enabled bool) PrintOption {
+	return func(opts *printOptions) {
+		opts.goFormat = enabled
+	}
+}
+
+/*
+Fprint writes a human-friendly representation of the error to the writer.
+The output includes the error message, status code, trace ID, properties, the chain of causes and the stack trace.
+Frames of the standard library and of third-party modules are dimmed to highlight the frames of the application.
+
+	errors.Fprint(os.Stderr, err)
+*/
+func Fprint(w io.Writer, err error, opts ...PrintOption) (n int, writeErr error) {
+	if err == nil {
+		return 0, nil
+	}
+	options := printOptions{
+		indent: "  ",
+		stack:  true,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	color := isTerminal(w)
+	if options.color != nil {
+		color = *options.color
+	}
+	paint := func(s string, codes ...string) string {
+		if !color || len(codes) == 0 {
+			return s
+		}
+		return strings.Join(codes, "") + s + ansiReset
+	}
+
+	var b strings.Builder
+	tracedErr := Convert(err).scrubbed()
+	b.WriteString(paint(tracedErr.Error(), ansiBold, ansiRed))
+	b.WriteString("\n")
+	if tracedErr.StatusCode != 0 && tracedErr.StatusCode != 500 {
+		fmt.Fprintf(&b, "%s%s%d\n", options.indent, paint("statusCode=", ansiDim), tracedErr.StatusCode)
+	}
+	if tracedErr.Trace != "" && tracedErr.Trace != zeroTrace {
+		fmt.Fprintf(&b, "%s%s%s\n", options.indent, paint("trace=", ansiDim), tracedErr.Trace)
+	}
+	for k, v := range flatProperties(tracedErr.Properties) {
+		fmt.Fprintf(&b, "%s%s%v\n", options.indent, paint(k+"=", ansiDim), v)
+	}
+
+	var causes strings.Builder
+	printCauses(&causes, tracedErr, 0, options.indent, true)
+	if causes.Len() > 0 {
+		b.WriteString(paint("caused by:", ansiBold))
+		b.WriteString("\n")
+		b.WriteString(causes.String())
+	}
+
+	if options.stack && len(tracedErr.Stack) > 0 {
+		b.WriteString(paint("stack:", ansiBold))
+		b.WriteString("\n")
+		for _, frame := range tracedErr.Stack {
+			if options.goFormat {
+				b.WriteString(frame.goroutineFormat())
+				b.WriteString("\n")
+				continue
+			}
+			if frame.isPseudo() {
+				fmt.Fprintf(&b, "%s%s\n", options.indent, paint(frame.Function, ansiDim))
+				continue
+			}
+			function := frame.Function
+			if frame.Repeat > 1 {
+				function = fmt.Sprintf("%s (x%d)", function, frame.Repeat)
+			}
+			location := fmt.Sprintf("%s:%d", frame.File, frame.Line)
+			if frame.isThirdParty() {
+				fmt.Fprintf(&b, "%s%s\n%s%s%s\n", options.indent, paint(function, ansiDim), options.indent, options.indent, paint(location, ansiDim))
+			} else {
+				fmt.Fprintf(&b, "%s%s\n%s%s%s\n", options.indent, paint(function, ansiBold), options.indent, options.indent, paint(location, ansiCyan))
+			}
+		}
+	}
+	return io.WriteString(w, b.String())
+}
+
+// printCauses writes the messages of the errors wrapped by the error, indenting each level of wrapping.
+// The scrub rules are applied to the messages.
+// Traced errors are transparent because their message is that of the error they wrap.
+func printCauses(b *strings.Builder, err error, level int, indent string, top bool) {
+	if err == nil {
+		return
+	}
+	if tracedErr, ok := err.(*TracedError); ok {
+		if !reaches(tracedErr.Err, tracedErr) {
+			printCauses(b, tracedErr.Err, level, indent, top)
+		}
+		return
+	}
+	if scrubbedErr, ok := err.(*scrubbedError); ok {
+		// The messages of the errors it wraps are scrubbed as they are printed
+		printCauses(b, scrubbedErr.err, level, indent, top)
+		return
+	}
+	if !top {
+		msg, _ := scrubString(err.Error(), loadSettings().scrubRules)
+		msg = strings.ReplaceAll(msg, "\n", "\n"+strings.Repeat(indent, level+1))
+		b.WriteString(strings.Repeat(indent, level+1))
+		b.WriteString(msg)
+		b.WriteString("\n")
+		level++
+	}
+	switch x := err.(type) {
+	case interface{ Unwrap() error }:
+		printCauses(b, x.Unwrap(), level, indent, false)
+	case interface{ Unwrap() []error }:
+		for _, e := range x.Unwrap() {
+			printCauses(b, e, level, indent, false)
+		}
+	}
+}
+
+// isTerminal determines if the writer is a terminal that supports ANSI colors.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// isThirdParty determines if the stack frame belongs to the standard library or to a third-party module.
+// The standard library is recognized by the import path of the function, which is known only for frames captured locally.
+func (t *StackFrame) isThirdParty() bool {
+	if isThirdPartyFile(t.File) {
+		return true
+	}
+	fullFunction := t.fullFunction()
+	return fullFunction != "" && isStdlibFunction(fullFunction)
+}
+
+// isThirdPartyFile determines if the file belongs to a third-party module in the module cache.
+func isThirdPartyFile(file string) bool {
+	return strings.Contains(file, "/pkg/mod/") || strings.Contains(file, "@v")
+}
+
+// isStdlibFunction determines if the function, qualified by its full import path, belongs to the standard library.
+// As in the go command, the first element of the import paths of the standard library does not contain a dot.
+func isStdlibFunction(function string) bool {
+	pkgPath := shortFunctionPrefix(function)
+	first, _, found := strings.Cut(pkgPath, "/")
+	if !found {
+		first, _, _ = strings.Cut(pkgPath, ".")
+	}
+	return first != "main" && !strings.Contains(first, ".")
+}
+
+// goroutineFormat returns the stack frame in the format of Go's panics and goroutine dumps.
+// The function is qualified by its full import path if the stack frame was captured locally.
+func (t *StackFrame) goroutineFormat() string {
+	if t.isPseudo() {
+		return t.Function
+	}
+	function := t.fullFunction()
+	if function == "" {
+		function = t.Function
+	}
+	return fmt.Sprintf("%s(...)\n\t%s:%d", function, t.File, t.Line)
+}
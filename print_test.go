@@ -0,0 +1,110 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"bytes"
+	stderrors "errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestErrors_Fprint(t *testing.T) {
+	t.Parallel()
+
+	root := stderrors.New("connection refused")
+	err := New("failed to query", fmt.Errorf("dialing db: %w", root), 503, "db", "users")
+
+	var buf bytes.Buffer
+	n, writeErr := Fprint(&buf, err)
+	assertNil(t, writeErr)
+	assertEqual(t, buf.Len(), n)
+	out := buf.String()
+	assertTrue(t, !strings.Contains(out, "\x1b["))
+	assertTrue(t, strings.HasPrefix(out, "failed to query: dialing db: connection refused\n"))
+	assertContains(t, out, "\n  statusCode=503\n")
+	assertContains(t, out, "\n  db=users\n")
+	assertContains(t, out, "caused by:\n  failed to query\n  dialing db: connection refused\n    connection refused\n")
+	assertContains(t, out, "stack:\n  errors.TestErrors_Fprint\n    ")
+	assertContains(t, out, "print_test.go:")
+
+	// Options
+	buf.Reset()
+	Fprint(&buf, err, WithColor(true), WithIndent("\t"), WithStack(false))
+	out = buf.String()
+	assertContains(t, out, ansiBold+ansiRed+"failed to query")
+	assertContains(t, out, "\t"+ansiDim+"statusCode="+ansiReset+"503")
+	assertTrue(t, !strings.Contains(out, "stack:"))
+
+	// Goroutine format
+	buf.Reset()
+	Fprint(&buf, err, WithGoroutineFormat(true))
+	out = buf.String()
+	assertContains(t, out, "stack:\ngithub.com/microbus-io/errors.TestErrors_Fprint(...)\n\t/")
+	assertContains(t, out, "/print_test.go:")
+
+	// Nil
+	buf.Reset()
+	n, _ = Fprint(&buf, nil)
+	assertEqual(t, 0, n)
+	assertEqual(t, 0, buf.Len())
+}
+
+func TestErrors_IsThirdPartyFile(t *testing.T) {
+	t.Parallel()
+
+	assertTrue(t, isThirdPartyFile("/root/go/pkg/mod/github.com/dep/pkg@v1.2.3/file.go"))
+	assertTrue(t, isThirdPartyFile("github.com/dep/pkg@v1.2.3/file.go"))
+	assertTrue(t, !isThirdPartyFile("/src/my-app/service.go"))
+}
+
+func TestErrors_IsStdlibFunction(t *testing.T) {
+	t.Parallel()
+
+	assertTrue(t, isStdlibFunction("net/http.(*Server).Serve"))
+	assertTrue(t, isStdlibFunction("runtime.goexit"))
+	assertTrue(t, isStdlibFunction("slices.SortFunc[...]"))
+	assertTrue(t, !isStdlibFunction("main.main"))
+	assertTrue(t, !isStdlibFunction("github.com/microbus-io/errors.New"))
+	assertTrue(t, !isStdlibFunction("example.com/calculator.(*Service).Square"))
+
+	// Frames captured locally are recognized by the import path of their function
+	frame := Convert(New("oops")).Stack[0]
+	assertTrue(t, !frame.isThirdParty())
+	local := stackFrameOf(runtime.Frame{Function: "net/http.(*Server).Serve", File: "/usr/local/go/src/net/http/server.go", Line: 100})
+	frame = &local
+	assertTrue(t, frame.isThirdParty())
+	assertEqual(t, "net/http.(*Server).Serve", frame.fullFunction())
+
+	// Frames unmarshaled from peers that were not captured locally are not recognized
+	frame = &StackFrame{Function: "http.(*Client).Do", File: "net/http/client.go", Line: 100}
+	assertTrue(t, !frame.isThirdParty())
+}
+
+func TestErrors_GoroutineFormat(t *testing.T) {
+	t.Parallel()
+
+	frame := &StackFrame{Function: "calculator.(*Service).Square", File: "/src/calculator/service.go", Line: 75}
+	assertEqual(t, "calculator.(*Service).Square(...)\n\t/src/calculator/service.go:75", frame.goroutineFormat())
+	assertEqual(t, "... 3 more", elisionFrame(3).goroutineFormat())
+
+	// Frames captured locally are qualified by the full import path
+	frame = Convert(New("oops")).Stack[0]
+	assertTrue(t, strings.HasPrefix(frame.goroutineFormat(), "github.com/microbus-io/errors.TestErrors_GoroutineFormat(...)\n\t"))
+}
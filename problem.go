@@ -0,0 +1,92 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+/*
+WriteProblem writes the error to the HTTP response as an RFC 9457 problem details object, with a content type of application/problem+json.
+The type is the help URL of the error, if any, or otherwise about:blank.
+The title is the status text, the status is the status code of the error, and the detail is the error message.
+The trace ID and properties of the error are added as extension members.
+The mask policy set by SetMaskPolicy is applied to the error.
+The stack trace is included only in debug mode, and properties are omitted in production render mode.
+
+It can be set as the serializer of WriteHTTP.
+
+	errors.SetHTTPSerializer(errors.WriteProblem)
+*/
+func WriteProblem(w http.ResponseWriter, err error) {
+	if err == nil {
+		return
+	}
+	tracedErr := applyMaskPolicy(Convert(err)).scrubbed()
+	h := w.Header()
+	h.Set("Content-Type", "application/problem+json")
+	h.Set("X-Content-Type-Options", "nosniff")
+	if tracedErr.Trace != "" && tracedErr.Trace != zeroTrace {
+		h.Set(HeaderErrorTrace, escapeHeaderValue(tracedErr.Trace))
+	}
+	if retryAfter, ok := RetryAfter(tracedErr); ok {
+		h.Set("Retry-After", formatRetryAfter(retryAfter))
+	}
+	body := problemBody(tracedErr)
+	h.Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(tracedErr.StatusCode)
+	w.Write(body)
+}
+
+// problemBody returns the RFC 9457 problem details JSON representation of the error.
+// In production render mode, properties and the stack trace are omitted.
+func problemBody(tracedErr *TracedError) []byte {
+	s := loadSettings()
+	production := s.renderMode == RenderProduction
+	m := map[string]any{}
+	for k, v := range tracedErr.Properties {
+		if k != "help" && !production {
+			m[k] = v
+		}
+	}
+	if tracedErr.Trace != "" && tracedErr.Trace != zeroTrace {
+		m["trace"] = tracedErr.Trace
+	}
+	if s.debugMode && !production && len(tracedErr.Stack) > 0 {
+		m["stack"] = tracedErr.Stack
+	}
+	// The standard members take precedence over same-named properties
+	m["type"] = "about:blank"
+	if help := Help(tracedErr); help != "" {
+		m["type"] = help
+	}
+	m["title"] = http.StatusText(tracedErr.StatusCode)
+	m["status"] = tracedErr.StatusCode
+	m["detail"] = tracedErr.Error()
+	body, err := json.Marshal(m)
+	if err != nil {
+		body, _ = json.Marshal(map[string]any{
+			"type":   m["type"],
+			"title":  m["title"],
+			"status": m["status"],
+			"detail": m["detail"],
+		})
+	}
+	return body
+}
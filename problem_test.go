@@ -0,0 +1,59 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrors_WriteProblem(t *testing.T) {
+	t.Parallel()
+
+	w := httptest.NewRecorder()
+	WriteProblem(w, New("user not found", 404, "0123456789abcdef0123456789abcdef", "userID", 123, "help", "https://example.com/user-not-found"))
+	assertEqual(t, 404, w.Code)
+	assertEqual(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assertEqual(t, "0123456789abcdef0123456789abcdef", w.Header().Get(HeaderErrorTrace))
+
+	var body map[string]any
+	assertNil(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assertEqual(t, "https://example.com/user-not-found", body["type"])
+	assertEqual(t, "Not Found", body["title"])
+	assertEqual(t, 404.0, body["status"])
+	assertEqual(t, "user not found", body["detail"])
+	assertEqual(t, "0123456789abcdef0123456789abcdef", body["trace"])
+	assertEqual(t, 123.0, body["userID"])
+	_, ok := body["help"]
+	assertTrue(t, !ok)
+	_, ok = body["stack"]
+	assertTrue(t, !ok)
+
+	// No help
+	w = httptest.NewRecorder()
+	WriteProblem(w, New("oops", "status", "shadowed"))
+	body = nil
+	assertNil(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assertEqual(t, "about:blank", body["type"])
+	assertEqual(t, 500.0, body["status"])
+
+	// Nil error
+	w = httptest.NewRecorder()
+	WriteProblem(w, nil)
+	assertEqual(t, 0, w.Body.Len())
+}
@@ -0,0 +1,363 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+)
+
+// Protocol Buffers wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+/*
+ToProto marshals the error to the Protocol Buffers wire format of the TracedError message defined in tracederror.proto.
+The output can be embedded in a bytes field of an envelope, or unmarshaled by code generated from the schema.
+Properties are converted to a google.protobuf.Struct following their JSON representation.
+*/
+func ToProto(err error) ([]byte, error) {
+	if err == nil {
+		return nil, nil
+	}
+	var visited visitedSet
+	return Convert(err).scrubbed().appendProto(nil, &visited), nil
+}
+
+// FromProto unmarshals an error from the Protocol Buffers wire format of the TracedError message defined in tracederror.proto.
+// Neither the type of the error nor any errors it wraps can be restored.
+func FromProto(data []byte) (*TracedError, error) {
+	return fromProto(data, 0)
+}
+
+// appendProto appends the wire format of the error, skipping suppressed errors that were already visited.
+func (e *TracedError) appendProto(b []byte, visited *visitedSet) []byte {
+	visited.visit(e)
+	b = protoAppendString(b, 1, e.Error())
+	if e.StatusCode != 0 {
+		b = protoAppendVarint(b, 2, uint64(int64(e.StatusCode)))
+	}
+	if e.Trace != "" && e.Trace != zeroTrace {
+		b = protoAppendString(b, 3, e.Trace)
+	}
+	for _, frame := range e.Stack {
+		var f []byte
+		f = protoAppendString(f, 1, frame.Function)
+		f = protoAppendString(f, 2, frame.File)
+		if frame.Line != 0 {
+			f = protoAppendVarint(f, 3, uint64(int64(frame.Line)))
+		}
+		if frame.Repeat != 0 {
+			f = protoAppendVarint(f, 4, uint64(int64(frame.Repeat)))
+		}
+		if frame.Site != "" {
+			f = protoAppendString(f, 5, frame.Site)
+		}
+		b = protoAppendBytes(b, 4, f)
+	}
+	if len(e.Properties) > 0 {
+		b = protoAppendBytes(b, 5, protoStruct(normalizeProperties(e.Properties)))
+	}
+	for _, s := range e.Suppressed {
+		if s != nil && visited.visit(s) {
+			b = protoAppendBytes(b, 6, Convert(s).appendProto(nil, visited))
+		}
+	}
+	if e.Span != "" && e.Span != zeroSpan {
+		b = protoAppendString(b, 7, e.Span)
+	}
+	if e.Origin != OriginLocal {
+		b = protoAppendString(b, 8, e.Origin.String())
+	}
+	return b
+}
+
+// fromProto unmarshals an error, limiting the nesting of suppressed errors.
+func fromProto(data []byte, depth int) (*TracedError, error) {
+	if depth > 16 {
+		return nil, New("suppressed errors nested too deeply")
+	}
+	e := &TracedError{}
+	msg := ""
+	err := protoFields(data, func(num int, wireType int, v uint64, b []byte) error {
+		switch {
+		case num == 1 && wireType == wireBytes:
+			msg = string(b)
+		case num == 2 && wireType == wireVarint:
+			e.StatusCode = int(int32(v))
+		case num == 3 && wireType == wireBytes:
+			e.Trace = string(b)
+		case num == 4 && wireType == wireBytes:
+			frame := &StackFrame{}
+			err := protoFields(b, func(num int, wireType int, v uint64, b []byte) error {
+				switch {
+				case num == 1 && wireType == wireBytes:
+					frame.Function = string(b)
+				case num == 2 && wireType == wireBytes:
+					frame.File = string(b)
+				case num == 3 && wireType == wireVarint:
+					frame.Line = int(int32(v))
+				case num == 4 && wireType == wireVarint:
+					frame.Repeat = int(int32(v))
+				case num == 5 && wireType == wireBytes:
+					frame.Site = string(b)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			e.Stack = append(e.Stack, frame)
+		case num == 5 && wireType == wireBytes:
+			props, err := protoParseStruct(b, 0)
+			if err != nil {
+				return err
+			}
+			if len(props) > 0 {
+				e.Properties = props
+			}
+		case num == 6 && wireType == wireBytes:
+			suppressed, err := fromProto(b, depth+1)
+			if err != nil {
+				return err
+			}
+			e.Suppressed = append(e.Suppressed, suppressed)
+		case num == 7 && wireType == wireBytes:
+			e.Span = string(b)
+		case num == 8 && wireType == wireBytes:
+			e.Origin = parseOrigin(string(b))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	e.Err = stderrors.New(msg)
+	return e, nil
+}
+
+// normalizeProperties converts the values of the properties to their JSON representation,
+// i.e. nil, bool, float64, string, []any or map[string]any.
+// Values that cannot be represented in JSON are converted to strings marked with a !BADVALUE prefix.
+func normalizeProperties(props map[string]any) map[string]any {
+	normalized := make(map[string]any, len(props))
+	for k, v := range props {
+		b, err := json.Marshal(v)
+		if err == nil {
+			var n any
+			err = json.Unmarshal(b, &n)
+			v = n
+		}
+		if err != nil {
+			v = badPropertyValue(v)
+		}
+		normalized[k] = v
+	}
+	return normalized
+}
+
+// protoStruct returns the wire format of a google.protobuf.Struct.
+func protoStruct(m map[string]any) []byte {
+	var b []byte
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		var entry []byte
+		entry = protoAppendString(entry, 1, k)
+		entry = protoAppendBytes(entry, 2, protoValue(m[k]))
+		b = protoAppendBytes(b, 1, entry)
+	}
+	return b
+}
+
+// protoValue returns the wire format of a google.protobuf.Value.
+func protoValue(v any) []byte {
+	var b []byte
+	switch x := v.(type) {
+	case nil:
+		b = protoAppendVarint(b, 1, 0)
+	case float64:
+		b = protoAppendTag(b, 2, wireFixed64)
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(x))
+	case string:
+		b = protoAppendString(b, 3, x)
+	case bool:
+		var n uint64
+		if x {
+			n = 1
+		}
+		b = protoAppendVarint(b, 4, n)
+	case map[string]any:
+		b = protoAppendBytes(b, 5, protoStruct(x))
+	case []any:
+		var list []byte
+		for _, item := range x {
+			list = protoAppendBytes(list, 1, protoValue(item))
+		}
+		b = protoAppendBytes(b, 6, list)
+	default:
+		b = protoAppendString(b, 3, fmt.Sprintf("%v", x))
+	}
+	return b
+}
+
+// protoParseStruct parses a google.protobuf.Struct.
+func protoParseStruct(data []byte, depth int) (map[string]any, error) {
+	m := map[string]any{}
+	err := protoFields(data, func(num int, wireType int, _ uint64, b []byte) error {
+		if num != 1 || wireType != wireBytes {
+			return nil
+		}
+		var key string
+		var value any
+		err := protoFields(b, func(num int, wireType int, _ uint64, b []byte) error {
+			if num == 1 && wireType == wireBytes {
+				key = string(b)
+			}
+			if num == 2 && wireType == wireBytes {
+				var err error
+				value, err = protoParseValue(b, depth+1)
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		m[key] = value
+		return nil
+	})
+	return m, err
+}
+
+// protoParseValue parses a google.protobuf.Value.
+func protoParseValue(data []byte, depth int) (any, error) {
+	if depth > 32 {
+		return nil, New("properties nested too deeply")
+	}
+	var value any
+	err := protoFields(data, func(num int, wireType int, v uint64, b []byte) error {
+		var err error
+		switch {
+		case num == 1 && wireType == wireVarint:
+			value = nil
+		case num == 2 && wireType == wireFixed64:
+			value = math.Float64frombits(v)
+		case num == 3 && wireType == wireBytes:
+			value = string(b)
+		case num == 4 && wireType == wireVarint:
+			value = v != 0
+		case num == 5 && wireType == wireBytes:
+			value, err = protoParseStruct(b, depth)
+		case num == 6 && wireType == wireBytes:
+			list := []any{}
+			err = protoFields(b, func(num int, wireType int, _ uint64, b []byte) error {
+				if num != 1 || wireType != wireBytes {
+					return nil
+				}
+				item, err := protoParseValue(b, depth+1)
+				list = append(list, item)
+				return err
+			})
+			value = list
+		}
+		return err
+	})
+	return value, err
+}
+
+// protoAppendTag appends the tag of a field.
+func protoAppendTag(b []byte, num int, wireType int) []byte {
+	return binary.AppendUvarint(b, uint64(num)<<3|uint64(wireType))
+}
+
+// protoAppendVarint appends a varint field.
+func protoAppendVarint(b []byte, num int, v uint64) []byte {
+	b = protoAppendTag(b, num, wireVarint)
+	return binary.AppendUvarint(b, v)
+}
+
+// protoAppendBytes appends a length-delimited field.
+func protoAppendBytes(b []byte, num int, v []byte) []byte {
+	b = protoAppendTag(b, num, wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+// protoAppendString appends a string field.
+func protoAppendString(b []byte, num int, v string) []byte {
+	b = protoAppendTag(b, num, wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+// protoFields iterates over the fields of a message.
+// Varint and fixed values are passed in v, and length-delimited values in b.
+func protoFields(data []byte, f func(num int, wireType int, v uint64, b []byte) error) error {
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		if n <= 0 {
+			return New("malformed protobuf tag")
+		}
+		data = data[n:]
+		num := int(tag >> 3)
+		wireType := int(tag & 7)
+		var v uint64
+		var b []byte
+		switch wireType {
+		case wireVarint:
+			v, n = binary.Uvarint(data)
+			if n <= 0 {
+				return New("malformed protobuf varint")
+			}
+			data = data[n:]
+		case wireFixed64:
+			if len(data) < 8 {
+				return New("malformed protobuf fixed64")
+			}
+			v = binary.LittleEndian.Uint64(data)
+			data = data[8:]
+		case wireFixed32:
+			if len(data) < 4 {
+				return New("malformed protobuf fixed32")
+			}
+			v = uint64(binary.LittleEndian.Uint32(data))
+			data = data[4:]
+		case wireBytes:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || length > uint64(len(data)-n) {
+				return New("malformed protobuf length")
+			}
+			b = data[n : n+int(length)]
+			data = data[n+int(length):]
+		default:
+			return New("unsupported protobuf wire type %d", wireType)
+		}
+		err := f(num, wireType, v, b)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"testing"
+)
+
+func TestErrors_Proto(t *testing.T) {
+	t.Parallel()
+
+	err := New("oops", 409, "00-0123456789abcdef0123456789abcdef-0123456789abcdef-01",
+		"str", "value",
+		"num", 5,
+		"flag", true,
+		"nothing", nil,
+		"list", []any{"a", 1.5},
+		"nested", map[string]any{"x": "y"},
+		"func", func() {},
+	)
+	err = Trace(err)
+	err = AddSuppressed(err, New("rollback failed"))
+	original := Convert(err)
+
+	b, protoErr := ToProto(err)
+	assertNil(t, protoErr)
+	decoded, protoErr := FromProto(b)
+	assertNil(t, protoErr)
+	assertEqual(t, "oops", decoded.Error())
+	assertEqual(t, 409, decoded.StatusCode)
+	assertEqual(t, "0123456789abcdef0123456789abcdef", decoded.Trace)
+	assertEqual(t, "0123456789abcdef", decoded.Span)
+	assertEqual(t, original.Stack, decoded.Stack)
+	assertEqual(t, "value", decoded.Properties["str"])
+	assertEqual(t, 5.0, decoded.Properties["num"])
+	assertEqual(t, true, decoded.Properties["flag"])
+	assertEqual(t, nil, decoded.Properties["nothing"])
+	assertEqual(t, []any{"a", 1.5}, decoded.Properties["list"])
+	assertEqual(t, map[string]any{"x": "y"}, decoded.Properties["nested"])
+	assertContains(t, decoded.Properties["func"].(string), "0x")
+	assertEqual(t, 1, len(decoded.Suppressed))
+	assertEqual(t, "rollback failed", decoded.Suppressed[0].Error())
+
+	// Deterministic
+	b2, _ := ToProto(err)
+	assertEqual(t, b, b2)
+
+	// Nil
+	b, protoErr = ToProto(nil)
+	assertNil(t, protoErr)
+	assertEqual(t, 0, len(b))
+}
+
+func TestErrors_ProtoMalformed(t *testing.T) {
+	t.Parallel()
+
+	_, err := FromProto([]byte{0x0a, 0x10, 'x'})
+	assertError(t, err)
+	_, err = FromProto([]byte{0x0b})
+	assertError(t, err)
+
+	// Unknown fields are skipped
+	var b []byte
+	b = protoAppendString(b, 1, "oops")
+	b = protoAppendVarint(b, 99, 123)
+	decoded, err := FromProto(b)
+	assertNil(t, err)
+	assertEqual(t, "oops", decoded.Error())
+}
@@ -0,0 +1,53 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+/*
+Redefine presents the error with a different message, for example to translate a low-level message into domain language at an API boundary.
+The stack trace, status code, trace ID and properties of the error are kept, and Is and As continue to match the original error.
+The original message is not included in Error, String or JSON, but remains available by unwrapping the error.
+The original error is not modified.
+
+	return errors.Redefine(err, "the account is temporarily locked")
+*/
+func Redefine(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	tracedErr := deriveTraced(err)
+	tracedErr.Err = &redefinedError{
+		msg: msg,
+		err: tracedErr.Err,
+	}
+	return tracedErr
+}
+
+// redefinedError presents a different message than that of the error it wraps.
+type redefinedError struct {
+	msg string
+	err error
+}
+
+// Error returns the redefined message.
+func (e *redefinedError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the original error.
+func (e *redefinedError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,55 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/json"
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+func TestErrors_Redefine(t *testing.T) {
+	t.Parallel()
+
+	original := New("pq: duplicate key value violates unique constraint", fs.ErrExist, 409, "table", "users")
+	err := Redefine(original, "the username is taken")
+	assertEqual(t, "the username is taken", err.Error())
+	assertTrue(t, Is(err, fs.ErrExist))
+	assertTrue(t, Is(err, original))
+	assertEqual(t, 409, StatusCode(err))
+	assertEqual(t, "users", Convert(err).Properties["table"])
+	assertEqual(t, Convert(original).Stack, Convert(err).Stack)
+	assertEqual(t, []string{"the username is taken"}, Messages(err))
+
+	// The original error is not modified
+	assertEqual(t, "pq: duplicate key value violates unique constraint: file already exists", original.Error())
+
+	// The original message is not serialized
+	data, jsonErr := json.Marshal(err)
+	assertNil(t, jsonErr)
+	assertContains(t, string(data), "the username is taken")
+	assertTrue(t, !strings.Contains(string(data), "duplicate"))
+
+	// Standard errors
+	err = Redefine(fs.ErrNotExist, "no such document")
+	assertEqual(t, "no such document", err.Error())
+	assertTrue(t, Is(err, fs.ErrNotExist))
+	assertEqual(t, 404, StatusCode(err))
+
+	assertNil(t, Redefine(nil, "oops"))
+}
@@ -0,0 +1,73 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"strconv"
+	"strings"
+)
+
+// RenderMode determines how much detail String, the %+v verb and WriteHTTP include.
+type RenderMode int
+
+const (
+	// RenderDevelopment includes the properties, stack trace and suppressed errors. It is the default.
+	RenderDevelopment RenderMode = iota
+	// RenderProduction includes only the message, the status code and the trace ID,
+	// which can be used to look up the full details of the error in the logs.
+	RenderProduction
+)
+
+/*
+SetRenderMode sets the render mode of String, the %+v verb and WriteHTTP,
+so that output sites do not each need to remember to sanitize errors.
+Structured logging via LogValue and serialization via MarshalJSON are not affected.
+
+	if env == "prod" {
+		errors.SetRenderMode(errors.RenderProduction)
+	}
+*/
+func SetRenderMode(mode RenderMode) {
+	updateSettings(func(s *settings) {
+		s.renderMode = mode
+	})
+}
+
+// Render returns a human-friendly representation of the traced error in the indicated render mode,
+// regardless of the render mode set by SetRenderMode.
+func (e *TracedError) Render(mode RenderMode) string {
+	e = e.scrubbed()
+	if mode != RenderProduction {
+		var visited visitedSet
+		return e.string(&visited)
+	}
+	var b strings.Builder
+	b.WriteString(e.Error())
+	if e.StatusCode != 0 && e.StatusCode != 500 {
+		b.WriteString("\nstatusCode=")
+		b.WriteString(strconv.Itoa(e.StatusCode))
+	}
+	if e.Trace != "" && e.Trace != zeroTrace {
+		b.WriteString("\ntrace=")
+		b.WriteString(e.Trace)
+	}
+	if e.Span != "" && e.Span != zeroSpan {
+		b.WriteString("\nspan=")
+		b.WriteString(e.Span)
+	}
+	return b.String()
+}
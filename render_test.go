@@ -0,0 +1,70 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestErrors_RenderMode(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	traceID := "0123456789abcdef0123456789abcdef"
+	err := AddSuppressed(New("not found", 404, traceID, "key", "value"), New("rollback failed"))
+	tracedErr := Convert(err)
+
+	development := tracedErr.String()
+	assertContains(t, development, "key=value")
+	assertContains(t, development, "render_test.go")
+	assertContains(t, development, "rollback failed")
+
+	SetRenderMode(RenderProduction)
+	defer SetRenderMode(RenderDevelopment)
+
+	production := "not found\nstatusCode=404\ntrace=" + traceID
+	assertEqual(t, production, tracedErr.String())
+	assertEqual(t, production, fmt.Sprintf("%+v", err))
+	assertEqual(t, "not found", fmt.Sprintf("%v", err))
+	assertEqual(t, development, tracedErr.Render(RenderDevelopment))
+
+	w := httptest.NewRecorder()
+	WriteHTTP(w, err)
+	assertEqual(t, 404, w.Code)
+	var body map[string]any
+	assertNil(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assertEqual(t, map[string]any{"error": "not found", "statusCode": 404.0, "trace": traceID}, body["err"])
+
+	w = httptest.NewRecorder()
+	WriteProblem(w, err)
+	assertTrue(t, !strings.Contains(w.Body.String(), "key"))
+	assertContains(t, w.Body.String(), traceID)
+
+	// Debug mode does not override production mode
+	SetDebugMode(true)
+	defer SetDebugMode(false)
+	w = httptest.NewRecorder()
+	WriteHTTP(w, err)
+	assertTrue(t, !strings.Contains(w.Body.String(), "stack"))
+
+	// Serialization is not affected
+	data, jsonErr := json.Marshal(err)
+	assertNil(t, jsonErr)
+	assertContains(t, string(data), "stack")
+}
@@ -0,0 +1,138 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryOption customizes the behavior of Retry.
+type RetryOption func(opts *retryOptions)
+
+type retryOptions struct {
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	retryable      func(err error) bool
+}
+
+// WithMaxAttempts limits the total number of attempts, including the first one. The default is 3.
+func WithMaxAttempts(n int) RetryOption {
+	return func(opts *retryOptions) {
+		opts.maxAttempts = max(n, 1)
+	}
+}
+
+// WithBackoff sets the initial delay between attempts, which doubles after each attempt up to the maximum.
+// The default is an initial delay of 100ms and a maximum of 10s.
+func WithBackoff(initial time.Duration, maximum time.Duration) RetryOption {
+	return func(opts *retryOptions) {
+		opts.initialBackoff = max(initial, 0)
+		opts.maxBackoff = max(maximum, opts.initialBackoff)
+	}
+}
+
+// WithRetryable overrides IsRetryable in determining which errors are retried.
+func WithRetryable(retryable func(err error) bool) RetryOption {
+	return func(opts *retryOptions) {
+		opts.retryable = retryable
+	}
+}
+
+/*
+IsRetryable indicates if the operation that returned the error may succeed if retried.
+A "retryable" property of the error takes precedence, if present.
+Otherwise, an error is retryable if it indicates a duration to wait per RetryAfter,
+or if its status code is 408, 429, 502, 503 or 504.
+Canceled contexts are never retryable.
+*/
+func IsRetryable(err error) bool {
+	if err == nil || Is(err, context.Canceled) {
+		return false
+	}
+	props := tracedProperties(err)
+	if retryable, ok := props["retryable"].(bool); ok {
+		return retryable
+	}
+	if _, ok := props["retryAfter"]; ok {
+		return true
+	}
+	switch StatusCode(err) {
+	case 408, 429, 502, 503, 504:
+		return true
+	}
+	return false
+}
+
+/*
+Retry calls the function until it succeeds, returns an error that is not retryable, or the attempts are exhausted.
+Attempts are separated by an exponential backoff with jitter, unless the error indicates a duration
+to wait per RetryAfter. The duration indicated by the error is capped at the maximum backoff.
+The final error is traced with an "attempts" property indicating the number of attempts made.
+If the context is done while waiting, or if its deadline would pass before the next attempt, the last error is returned.
+
+	err := errors.Retry(ctx, func() error {
+		return callUpstream(ctx)
+	}, errors.WithMaxAttempts(5))
+*/
+func Retry(ctx context.Context, f func() error, opts ...RetryOption) error {
+	options := retryOptions{
+		maxAttempts:    3,
+		initialBackoff: 100 * time.Millisecond,
+		maxBackoff:     10 * time.Second,
+		retryable:      IsRetryable,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.retryable == nil {
+		options.retryable = IsRetryable
+	}
+	if err := ctx.Err(); err != nil {
+		return Trace(err, "attempts", 0)
+	}
+
+	backoff := options.initialBackoff
+	for attempt := 1; ; attempt++ {
+		err := f()
+		if err == nil {
+			return nil
+		}
+		if attempt >= options.maxAttempts || !options.retryable(err) {
+			return Trace(err, "attempts", attempt)
+		}
+
+		// Wait with equal jitter, unless instructed otherwise by the error
+		delay := backoff/2 + rand.N(backoff/2+1)
+		if retryAfter, ok := RetryAfter(err); ok && retryAfter > 0 {
+			delay = min(retryAfter, options.maxBackoff)
+		}
+		backoff = min(backoff*2, options.maxBackoff)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return Trace(err, "attempts", attempt)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return Trace(err, "attempts", attempt)
+		case <-timer.C:
+		}
+	}
+}
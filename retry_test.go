@@ -0,0 +1,147 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestErrors_IsRetryable(t *testing.T) {
+	t.Parallel()
+
+	assertTrue(t, !IsRetryable(nil))
+	assertTrue(t, !IsRetryable(New("oops")))
+	assertTrue(t, !IsRetryable(New("bad", 400)))
+	assertTrue(t, IsRetryable(New("busy", 503)))
+	assertTrue(t, IsRetryable(New("slow down", 429)))
+	assertTrue(t, IsRetryable(New("flaky", "retryable", true)))
+	assertTrue(t, !IsRetryable(New("busy", 503, "retryable", false)))
+	assertTrue(t, IsRetryable(New("later", "retryAfter", time.Second)))
+	assertTrue(t, !IsRetryable(Trace(context.Canceled)))
+	assertTrue(t, IsRetryable(Trace(context.DeadlineExceeded)))
+}
+
+func TestErrors_Retry(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Success after retries
+	attempts := 0
+	err := Retry(ctx, func() error {
+		attempts++
+		if attempts < 3 {
+			return New("busy", 503)
+		}
+		return nil
+	}, WithBackoff(time.Millisecond, 2*time.Millisecond))
+	assertNil(t, err)
+	assertEqual(t, 3, attempts)
+
+	// Exhausted
+	attempts = 0
+	err = Retry(ctx, func() error {
+		attempts++
+		return New("busy", 503)
+	}, WithMaxAttempts(4), WithBackoff(time.Millisecond, time.Millisecond))
+	assertError(t, err)
+	assertEqual(t, 4, attempts)
+	assertEqual(t, 4, Convert(err).Properties["attempts"])
+	assertEqual(t, 503, StatusCode(err))
+
+	// Permanent error
+	attempts = 0
+	err = Retry(ctx, func() error {
+		attempts++
+		return New("bad", 400)
+	}, WithBackoff(time.Millisecond, time.Millisecond))
+	assertError(t, err)
+	assertEqual(t, 1, attempts)
+	assertEqual(t, 1, Convert(err).Properties["attempts"])
+
+	// Custom classification
+	attempts = 0
+	err = Retry(ctx, func() error {
+		attempts++
+		return New("bad", 400)
+	}, WithBackoff(time.Millisecond, time.Millisecond), WithRetryable(func(err error) bool { return true }))
+	assertError(t, err)
+	assertEqual(t, 3, attempts)
+
+	// Retry-After property
+	attempts = 0
+	t0 := time.Now()
+	err = Retry(ctx, func() error {
+		attempts++
+		if attempts == 1 {
+			return New("later", "retryAfter", 20*time.Millisecond)
+		}
+		return nil
+	}, WithBackoff(time.Millisecond, time.Second))
+	assertNil(t, err)
+	assertTrue(t, time.Since(t0) >= 20*time.Millisecond)
+
+	// Retry-After is capped at the maximum backoff
+	attempts = 0
+	t0 = time.Now()
+	err = Retry(ctx, func() error {
+		attempts++
+		if attempts == 1 {
+			return New("later", "retryAfter", time.Hour)
+		}
+		return nil
+	}, WithBackoff(time.Millisecond, 2*time.Millisecond))
+	assertNil(t, err)
+	assertEqual(t, 2, attempts)
+	assertTrue(t, time.Since(t0) < time.Minute)
+
+	// Retry-After beyond the deadline of the context
+	deadlineCtx, deadlineCancel := context.WithTimeout(context.Background(), time.Minute)
+	defer deadlineCancel()
+	attempts = 0
+	t0 = time.Now()
+	err = Retry(deadlineCtx, func() error {
+		attempts++
+		return New("later", "retryAfter", time.Hour)
+	}, WithBackoff(time.Millisecond, 2*time.Hour))
+	assertError(t, err)
+	assertEqual(t, 1, attempts)
+	assertEqual(t, 1, Convert(err).Properties["attempts"])
+	assertTrue(t, time.Since(t0) < time.Minute)
+
+	// Context canceled while waiting
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	time.AfterFunc(20*time.Millisecond, cancel)
+	attempts = 0
+	err = Retry(ctx, func() error {
+		attempts++
+		return New("busy", 503)
+	}, WithBackoff(time.Hour, time.Hour))
+	assertError(t, err)
+	assertEqual(t, 1, attempts)
+	assertEqual(t, "busy", err.Error())
+
+	// Context already canceled
+	err = Retry(ctx, func() error {
+		t.Fail()
+		return nil
+	})
+	assertTrue(t, Is(err, context.Canceled))
+}
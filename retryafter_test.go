@@ -0,0 +1,74 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestErrors_RetryAfter(t *testing.T) {
+	t.Parallel()
+
+	err := WithRetryAfter(New("rate limited", 429), 90*time.Second)
+	d, ok := RetryAfter(err)
+	assertTrue(t, ok)
+	assertEqual(t, 90*time.Second, d)
+	assertTrue(t, IsRetryable(err))
+
+	_, ok = RetryAfter(New("oops"))
+	assertTrue(t, !ok)
+	_, ok = RetryAfter(nil)
+	assertTrue(t, !ok)
+	assertNil(t, WithRetryAfter(nil, time.Second))
+
+	// Emitted by WriteHTTP
+	w := httptest.NewRecorder()
+	WriteHTTP(w, WithRetryAfter(New("busy", 503), 1500*time.Millisecond))
+	assertEqual(t, "2", w.Header().Get("Retry-After"))
+
+	// Populated by FromHTTPResponse
+	res := w.Result()
+	err = FromHTTPResponse(res)
+	d, ok = RetryAfter(err)
+	assertTrue(t, ok)
+	assertEqual(t, 2*time.Second, d)
+}
+
+func TestErrors_ParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, ok := parseRetryAfter("120", now)
+	assertTrue(t, ok)
+	assertEqual(t, 120*time.Second, d)
+
+	d, ok = parseRetryAfter(now.Add(time.Minute).Format(http.TimeFormat), now)
+	assertTrue(t, ok)
+	assertEqual(t, time.Minute, d)
+
+	d, ok = parseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now)
+	assertTrue(t, ok)
+	assertEqual(t, time.Duration(0), d)
+
+	_, ok = parseRetryAfter("soon", now)
+	assertTrue(t, !ok)
+	_, ok = parseRetryAfter("", now)
+	assertTrue(t, !ok)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxBodySnippetLen limits the length of the snippet of the body of an upstream response that is attached to errors.
+const maxBodySnippetLen = 256
+
+/*
+FromRequest returns an error describing the failure of an HTTP client round trip, or nil if the round trip succeeded.
+The round trip failed if the error of the transport is not nil, or if the status code of the response indicates an error,
+in which case the error is reconstructed from the response as by FromHTTPResponse.
+The following properties are attached:
+
+  - "method" is the method of the request
+  - "url" is the URL of the request, with the password and the values of query arguments named by Config.RedactedProperties replaced by "!REDACTED"
+  - "body" is a snippet of the body of the response, if it indicates an error
+
+The status code and retry-after duration of the response are respected, as by FromHTTPResponse.
+The error is attributed to the upstream origin.
+The body of the response is read but not closed, and remains readable by the caller.
+
+	res, err := http.DefaultClient.Do(req)
+	if err := errors.FromRequest(err, req, res); err != nil {
+		return err
+	}
+	defer res.Body.Close()
+*/
+func FromRequest(err error, req *http.Request, res *http.Response) error {
+	var props []any
+	if req != nil {
+		props = append(props, "method", req.Method)
+		if req.URL != nil {
+			props = append(props, "url", redactURL(req.URL))
+		}
+	}
+	if err != nil {
+		tracedErr := Convert(Trace(err, props...))
+		tracedErr.Origin = OriginUpstream
+		return tracedErr
+	}
+	if res == nil || res.StatusCode < 400 {
+		return nil
+	}
+	var body []byte
+	original := res.Body
+	if original != nil {
+		body, _ = io.ReadAll(io.LimitReader(original, maxResponseBodyLen))
+		res.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	if snippet := strings.TrimSpace(string(body)); snippet != "" && utf8.ValidString(snippet) {
+		props = append(props, "body", truncateString(snippet, maxBodySnippetLen))
+	}
+	resErr := FromHTTPResponse(res)
+	if original != nil {
+		// The body remains readable by the caller, who is responsible for closing it
+		res.Body = struct {
+			io.Reader
+			io.Closer
+		}{bytes.NewReader(body), original}
+	}
+	// The location of the caller is already traced by FromHTTPResponse
+	return With(resErr, props...)
+}
+
+// redactURL returns the URL with its password and the values of query arguments named by the redacted properties replaced by "!REDACTED".
+// The order of the query arguments is preserved.
+func redactURL(u *url.URL) string {
+	redacted := loadSettings().redactedProperties
+	if len(redacted) == 0 || u.RawQuery == "" {
+		return u.Redacted()
+	}
+	args := strings.Split(u.RawQuery, "&")
+	changed := false
+	for i, arg := range args {
+		k, _, _ := strings.Cut(arg, "=")
+		if name, err := url.QueryUnescape(k); err == nil {
+			k = name
+		}
+		for _, r := range redacted {
+			if strings.EqualFold(k, r) {
+				args[i] = url.QueryEscape(k) + "=!REDACTED"
+				changed = true
+				break
+			}
+		}
+	}
+	if !changed {
+		return u.Redacted()
+	}
+	clone := *u
+	clone.RawQuery = strings.Join(args, "&")
+	return clone.Redacted()
+}
@@ -0,0 +1,55 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+)
+
+/*
+SetStackSampler sets a sampler that is consulted whenever New or Trace is about to capture a stack location,
+in order to bound the overhead of stack capture during error storms.
+If the sampler returns false, the stack location is not captured.
+Stack locations of errors with a 5xx status code are always captured.
+Calling it with nil disables sampling.
+
+	errors.SetStackSampler(errors.RateSampler(1000, 0.9))
+*/
+func SetStackSampler(sampler func() bool) {
+	updateSettings(func(s *settings) {
+		s.stackSampler = sampler
+	})
+}
+
+// RateSampler returns a stack sampler that captures all stack locations as long as the rate of errors
+// does not exceed the threshold per second, and skips the indicated fraction of the rest.
+// A skip fraction of 1 skips all stack locations beyond the threshold.
+func RateSampler(threshold int, skipFraction float64) func() bool {
+	var window, count atomic.Int64
+	return func() bool {
+		now := time.Now().Unix()
+		if window.Load() != now && window.Swap(now) != now {
+			count.Store(0)
+		}
+		if count.Add(1) <= int64(threshold) {
+			return true
+		}
+		return rand.Float64() >= skipFraction
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import "testing"
+
+func TestErrors_StackSampler(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	SetStackSampler(func() bool { return false })
+	defer SetStackSampler(nil)
+
+	assertEqual(t, 0, len(Convert(New("not found", 404)).Stack))
+	assertEqual(t, 1, len(Convert(New("oops")).Stack))
+	assertEqual(t, 1, len(Convert(New("unavailable", 503)).Stack))
+
+	err := New("bad request", 400)
+	err = Trace(err)
+	assertEqual(t, 0, len(Convert(err).Stack))
+	assertEqual(t, 400, StatusCode(err))
+	assertEqual(t, "bad request", err.Error())
+
+	SetStackSampler(nil)
+	assertEqual(t, 1, len(Convert(New("not found", 404)).Stack))
+}
+
+func TestErrors_RateSampler(t *testing.T) {
+	t.Parallel()
+
+	sampler := RateSampler(10, 1)
+	captured := 0
+	for range 100 {
+		if sampler() {
+			captured++
+		}
+	}
+	// The window may have rolled over once during the loop
+	assertTrue(t, captured >= 10 && captured <= 20)
+
+	sampler = RateSampler(10, 0)
+	captured = 0
+	for range 100 {
+		if sampler() {
+			captured++
+		}
+	}
+	assertEqual(t, 100, captured)
+}
@@ -0,0 +1,135 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+/*
+JSONSchema returns the JSON Schema (draft 2020-12) of the JSON representation of a traced error.
+Properties of the error are serialized alongside the standard fields and are therefore allowed as additional properties.
+
+	b, _ := json.MarshalIndent(errors.JSONSchema(), "", "  ")
+*/
+func JSONSchema() map[string]any {
+	schema := errorSchema("#", "#/$defs/StackFrame")
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["$defs"] = map[string]any{
+		"StackFrame": stackFrameSchema(),
+	}
+	return schema
+}
+
+/*
+OpenAPISchema returns the OpenAPI 3.1 component schemas of the JSON representation of a traced error,
+keyed by their name, to be merged into the components.schemas section of an OpenAPI document.
+The error schema is named TracedError and references the StackFrame schema.
+
+	for name, schema := range errors.OpenAPISchema() {
+		doc.Components.Schemas[name] = schema
+	}
+*/
+func OpenAPISchema() map[string]any {
+	return map[string]any{
+		"TracedError": errorSchema("#/components/schemas/TracedError", "#/components/schemas/StackFrame"),
+		"StackFrame":  stackFrameSchema(),
+	}
+}
+
+// errorSchema returns the schema of a traced error, referencing itself and the stack frame schema by the indicated references.
+// The names of the fields of the message and status code are per SetJSONConfig.
+func errorSchema(errorRef string, stackFrameRef string) map[string]any {
+	cfg := loadJSONConfig()
+	return map[string]any{
+		"title":       "TracedError",
+		"description": "An error with a status code, trace ID, stack trace and properties. Properties appear alongside the standard fields.",
+		"type":        "object",
+		"properties": map[string]any{
+			cfg.MessageField: map[string]any{
+				"type":        "string",
+				"description": "The error message",
+				"examples":    []any{"message"},
+			},
+			cfg.CodeField: map[string]any{
+				"type":        "integer",
+				"description": "The HTTP status code associated with the error",
+				"examples":    []any{500},
+			},
+			"trace": map[string]any{
+				"type":        "string",
+				"description": "The trace ID",
+				"pattern":     "^[0-9a-f]{32}$",
+			},
+			"span": map[string]any{
+				"type":        "string",
+				"description": "The span ID",
+				"pattern":     "^[0-9a-f]{16}$",
+			},
+			"origin": map[string]any{
+				"type":        "string",
+				"description": "Where the error originated, if not locally",
+				"enum":        []any{"upstream", "client"},
+			},
+			"stack": map[string]any{
+				"type":        "array",
+				"description": "The stack trace, from the origin of the error to the last location it was traced",
+				"items": map[string]any{
+					"$ref": stackFrameRef,
+				},
+			},
+			"suppressed": map[string]any{
+				"type":        "array",
+				"description": "Secondary errors that occurred while handling the error",
+				"items": map[string]any{
+					"$ref": errorRef,
+				},
+			},
+		},
+		"required":             []any{cfg.MessageField},
+		"additionalProperties": true,
+	}
+}
+
+// stackFrameSchema returns the schema of a stack frame.
+func stackFrameSchema() map[string]any {
+	return map[string]any{
+		"title":       "StackFrame",
+		"description": "A single stack location",
+		"type":        "object",
+		"properties": map[string]any{
+			"func": map[string]any{
+				"type":        "string",
+				"description": "The function, qualified by its package name",
+			},
+			"file": map[string]any{
+				"type":        "string",
+				"description": "The source file",
+			},
+			"line": map[string]any{
+				"type":        "integer",
+				"description": "The line number in the source file",
+			},
+			"repeat": map[string]any{
+				"type":        "integer",
+				"description": "The number of consecutive times the location was traced, if more than once",
+			},
+			"site": map[string]any{
+				"type":        "string",
+				"description": "The label of the call that captured the frame, if the frame is the first it captured",
+			},
+		},
+		"required":             []any{"func", "file", "line"},
+		"additionalProperties": false,
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestErrors_JSONSchema(t *testing.T) {
+	t.Parallel()
+
+	schema := JSONSchema()
+	_, err := json.Marshal(schema)
+	assertNil(t, err)
+	assertEqual(t, "object", schema["type"])
+	assertEqual(t, true, schema["additionalProperties"])
+
+	// The schema covers every field of the JSON representation
+	b, err := json.Marshal(New("oops", 400, "0123456789abcdef0123456789abcdef"))
+	assertNil(t, err)
+	var m map[string]any
+	assertNil(t, json.Unmarshal(b, &m))
+	props := schema["properties"].(map[string]any)
+	for k := range m {
+		_, ok := props[k]
+		assertTrue(t, ok)
+	}
+	stack := m["stack"].([]any)
+	frameProps := schema["$defs"].(map[string]any)["StackFrame"].(map[string]any)["properties"].(map[string]any)
+	for k := range stack[0].(map[string]any) {
+		_, ok := frameProps[k]
+		assertTrue(t, ok)
+	}
+	assertEqual(t, "#/$defs/StackFrame", props["stack"].(map[string]any)["items"].(map[string]any)["$ref"])
+	assertEqual(t, "#", props["suppressed"].(map[string]any)["items"].(map[string]any)["$ref"])
+}
+
+func TestErrors_OpenAPISchema(t *testing.T) {
+	t.Parallel()
+
+	schemas := OpenAPISchema()
+	_, err := json.Marshal(schemas)
+	assertNil(t, err)
+	errorSchema := schemas["TracedError"].(map[string]any)
+	props := errorSchema["properties"].(map[string]any)
+	assertEqual(t, "#/components/schemas/StackFrame", props["stack"].(map[string]any)["items"].(map[string]any)["$ref"])
+	assertEqual(t, "#/components/schemas/TracedError", props["suppressed"].(map[string]any)["items"].(map[string]any)["$ref"])
+	assertTrue(t, slices.Contains(errorSchema["required"].([]any), any("error")))
+	_, ok := schemas["StackFrame"]
+	assertTrue(t, ok)
+}
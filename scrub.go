@@ -0,0 +1,306 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"reflect"
+	"regexp"
+	"slices"
+	"sync/atomic"
+)
+
+// ScrubRule identifies personally identifiable information, or other sensitive data, to be redacted from errors.
+type ScrubRule struct {
+	// Name identifies the rule in the counts returned by ScrubCounts
+	Name string
+	// Pattern matches the sensitive substrings of the message and of property values.
+	// Values that are not strings are matched by their string representation
+	Pattern *regexp.Regexp
+	// Keys are patterns of the keys of properties whose values are redacted in full, in the syntax of path.Match
+	Keys []string
+}
+
+// Predefined scrub rules.
+var (
+	// ScrubEmails redacts email addresses
+	ScrubEmails = ScrubRule{
+		Name:    "email",
+		Pattern: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`),
+	}
+	// ScrubCreditCards redacts numbers of 13 to 19 digits, optionally separated by spaces or dashes
+	ScrubCreditCards = ScrubRule{
+		Name:    "creditCard",
+		Pattern: regexp.MustCompile(`\b\d(?:[ \-]?\d){12,18}\b`),
+	}
+	// ScrubBearerTokens redacts bearer tokens and the values of the authorization property
+	ScrubBearerTokens = ScrubRule{
+		Name:    "bearerToken",
+		Pattern: regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`),
+		Keys:    []string{"authorization", "Authorization"},
+	}
+)
+
+// scrubRule is a scrub rule along with the count of redactions it made.
+type scrubRule struct {
+	ScrubRule
+	count atomic.Int64
+}
+
+/*
+SetScrubRules sets the rules applied to the message and properties of errors before they are serialized in any format,
+written to HTTP responses or headers, logged, printed or reported to sinks.
+Sensitive data matched by the rules is replaced by !REDACTED.
+The number of redactions made by each rule is counted and can be audited with ScrubCounts.
+Calling SetScrubRules with no rules disables scrubbing.
+
+	errors.SetScrubRules(errors.ScrubEmails, errors.ScrubCreditCards, errors.ScrubBearerTokens, errors.ScrubRule{
+		Name:    "ssn",
+		Pattern: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`),
+		Keys:    []string{"ssn"},
+	})
+*/
+func SetScrubRules(rules ...ScrubRule) {
+	scrubRules := make([]*scrubRule, 0, len(rules))
+	for _, r := range rules {
+		scrubRules = append(scrubRules, &scrubRule{ScrubRule: r})
+	}
+	updateSettings(func(s *settings) {
+		s.scrubRules = scrubRules
+	})
+}
+
+// ScrubCounts returns the number of redactions made by each of the rules set by SetScrubRules, keyed by the name of the rule.
+// The counts are reset when the rules are set.
+func ScrubCounts() map[string]int64 {
+	counts := map[string]int64{}
+	for _, r := range loadSettings().scrubRules {
+		counts[r.Name] += r.count.Load()
+	}
+	return counts
+}
+
+// scrubbedError replaces the message of an error while still wrapping it, so that it can be matched by Is and As.
+type scrubbedError struct {
+	msg string
+	err error
+}
+
+// Error returns the scrubbed message.
+func (e *scrubbedError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the original error.
+func (e *scrubbedError) Unwrap() error {
+	return e.err
+}
+
+/*
+Scrub returns the traced error with the scrub rules set by SetScrubRules applied to its message, properties,
+raw panic stack and suppressed errors. It is the view of the error that is serialized, logged, printed and reported to sinks,
+and is intended to be used by integrations that output the error by other means.
+The error itself is returned if nothing was redacted, otherwise a shallow copy that wraps it is returned.
+
+	e := errors.Scrub(err)
+	fields["error"] = e.Error()
+	fields["properties"] = e.Properties
+*/
+func Scrub(err error) *TracedError {
+	if err == nil {
+		return nil
+	}
+	return Convert(err).scrubbed()
+}
+
+// scrubbed returns the traced error with the scrub rules set by SetScrubRules applied.
+func (e *TracedError) scrubbed() *TracedError {
+	rules := loadSettings().scrubRules
+	if len(rules) == 0 {
+		return e
+	}
+	var visited visitedSet
+	return e.scrubWith(rules, &visited)
+}
+
+// scrubWith returns a shallow copy of the traced error with the scrub rules applied to its message, properties,
+// raw panic stack and suppressed errors, or the error itself if nothing was redacted.
+func (e *TracedError) scrubWith(rules []*scrubRule, visited *visitedSet) *TracedError {
+	visited.visit(e)
+	msg := e.Error()
+	scrubbedMsg, msgOK := scrubString(msg, rules)
+	props, propsOK := scrubProperties(e.Properties, rules)
+	rawPanicStack, rawOK := scrubString(e.RawPanicStack, rules)
+	var suppressed []error
+	for i, s := range e.Suppressed {
+		if s == nil || !visited.visit(s) {
+			continue
+		}
+		scrubbedErr := scrubError(s, rules, visited)
+		if scrubbedErr == s {
+			continue
+		}
+		if suppressed == nil {
+			suppressed = slices.Clone(e.Suppressed)
+		}
+		suppressed[i] = scrubbedErr
+	}
+	if !msgOK && !propsOK && !rawOK && suppressed == nil {
+		return e
+	}
+	clone := *e
+	if msgOK {
+		clone.Err = &scrubbedError{msg: scrubbedMsg, err: e}
+	}
+	if propsOK {
+		clone.Properties = props
+	}
+	if rawOK {
+		clone.RawPanicStack = rawPanicStack
+	}
+	if suppressed != nil {
+		clone.Suppressed = suppressed
+	}
+	return &clone
+}
+
+// scrubError applies the scrub rules to an error that may or may not be traced.
+// The error itself is returned if nothing was redacted.
+func scrubError(err error, rules []*scrubRule, visited *visitedSet) error {
+	if findTraced(err) != nil {
+		tracedErr := Convert(err)
+		if scrubbedErr := tracedErr.scrubWith(rules, visited); scrubbedErr != tracedErr {
+			return scrubbedErr
+		}
+		return err
+	}
+	if msg, ok := scrubString(err.Error(), rules); ok {
+		return &scrubbedError{msg: msg, err: err}
+	}
+	return err
+}
+
+// scrubString applies the patterns of the scrub rules to the string.
+func scrubString(s string, rules []*scrubRule) (scrubbed string, ok bool) {
+	scrubbed = s
+	for _, r := range rules {
+		if r.Pattern == nil {
+			continue
+		}
+		scrubbed = r.Pattern.ReplaceAllStringFunc(scrubbed, func(string) string {
+			r.count.Add(1)
+			ok = true
+			return "!REDACTED"
+		})
+	}
+	return scrubbed, ok
+}
+
+// scrubProperties applies the scrub rules to the properties, including grouped properties.
+// The properties are cloned only if a redaction is made.
+func scrubProperties(props map[string]any, rules []*scrubRule) (scrubbed map[string]any, ok bool) {
+	for k, v := range props {
+		var redacted any
+		var changed bool
+		if scrubKey(k, rules) {
+			redacted, changed = "!REDACTED", true
+		} else {
+			redacted, changed = scrubValue(v, rules)
+		}
+		if !changed {
+			continue
+		}
+		if !ok {
+			scrubbed = maps.Clone(props)
+			ok = true
+		}
+		scrubbed[k] = redacted
+	}
+	return scrubbed, ok
+}
+
+// scrubValue applies the scrub rules to the value of a property.
+// Lazy values are resolved, slices, arrays and maps are scrubbed element by element,
+// and other values are scrubbed by their string representation.
+// The value is replaced only if a redaction is made.
+func scrubValue(v any, rules []*scrubRule) (scrubbed any, ok bool) {
+	switch v := v.(type) {
+	case nil:
+		return nil, false
+	case string:
+		return scrubString(v, rules)
+	case []byte:
+		return scrubString(string(v), rules)
+	case *LazyValue:
+		return scrubValue(v.Value(), rules)
+	case GroupedProperties:
+		g, ok := scrubProperties(v, rules)
+		return GroupedProperties(g), ok
+	case map[string]any:
+		return scrubProperties(v, rules)
+	case []any:
+		var elems []any
+		for i, elem := range v {
+			if redacted, changed := scrubValue(elem, rules); changed {
+				if elems == nil {
+					elems = slices.Clone(v)
+				}
+				elems[i] = redacted
+			}
+		}
+		return elems, elems != nil
+	}
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.Slice, reflect.Array:
+		var elems []any
+		for i := range val.Len() {
+			elem := val.Index(i).Interface()
+			redacted, changed := scrubValue(elem, rules)
+			if changed && elems == nil {
+				elems = make([]any, val.Len())
+				for j := range i {
+					elems[j] = val.Index(j).Interface()
+				}
+			}
+			if elems != nil {
+				if changed {
+					elems[i] = redacted
+				} else {
+					elems[i] = elem
+				}
+			}
+		}
+		return elems, elems != nil
+	}
+	return scrubString(fmt.Sprintf("%v", v), rules)
+}
+
+// scrubKey indicates if the value of the property with the key is to be redacted in full, and counts the redaction if so.
+func scrubKey(k string, rules []*scrubRule) bool {
+	for _, r := range rules {
+		for _, pattern := range r.Keys {
+			if match, _ := path.Match(pattern, k); match {
+				r.count.Add(1)
+				return true
+			}
+		}
+	}
+	return false
+}
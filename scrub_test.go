@@ -0,0 +1,259 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestErrors_ScrubRules(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	SetScrubRules(ScrubEmails, ScrubCreditCards, ScrubBearerTokens, ScrubRule{
+		Name:    "ssn",
+		Pattern: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`),
+		Keys:    []string{"ssn*"},
+	})
+	defer SetScrubRules()
+
+	original := New("failed to charge jane@example.com",
+		"card", "4111 1111 1111 1111",
+		"header", "Bearer abc.def-ghi",
+		"authorization", "secret",
+		"ssnLast", "1234",
+		"note", "SSN 123-45-6789 on file",
+		"count", 5,
+		Group("user", "email", "john.doe@mail.example.org"),
+	)
+	data, err := json.Marshal(original)
+	assertNil(t, err)
+	var m map[string]any
+	assertNil(t, json.Unmarshal(data, &m))
+	assertEqual(t, "failed to charge !REDACTED", m["error"])
+	assertEqual(t, "!REDACTED", m["card"])
+	assertEqual(t, "!REDACTED", m["header"])
+	assertEqual(t, "!REDACTED", m["authorization"])
+	assertEqual(t, "!REDACTED", m["ssnLast"])
+	assertEqual(t, "SSN !REDACTED on file", m["note"])
+	assertEqual(t, 5.0, m["count"])
+	assertEqual(t, map[string]any{"email": "!REDACTED"}, m["user"])
+	assertEqual(t, map[string]int64{"email": 2, "creditCard": 1, "bearerToken": 2, "ssn": 2}, ScrubCounts())
+
+	// The original error is not modified
+	assertContains(t, original.Error(), "jane@example.com")
+	assertEqual(t, "4111 1111 1111 1111", Convert(original).Properties["card"])
+
+	// Errors without sensitive data are not counted
+	_, err = json.Marshal(New("nothing to see", "count", 1234))
+	assertNil(t, err)
+	assertEqual(t, int64(2), ScrubCounts()["email"])
+
+	// Resetting the rules resets the counts
+	SetScrubRules(ScrubEmails)
+	assertEqual(t, map[string]int64{"email": 0}, ScrubCounts())
+	SetScrubRules()
+	assertEqual(t, 0, len(ScrubCounts()))
+	data, err = json.Marshal(original)
+	assertNil(t, err)
+	assertContains(t, string(data), "jane@example.com")
+}
+
+func TestErrors_ScrubReport(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	SetScrubRules(ScrubEmails)
+	defer SetScrubRules()
+
+	var reported error
+	defer RegisterSink(func(ctx context.Context, err error) {
+		if strings.HasPrefix(err.Error(), "scrub report") {
+			reported = err
+		}
+	})()
+
+	original := New("scrub report for jane@example.com", 404, "email", "jane@example.com")
+	Report(context.Background(), original)
+	assertEqual(t, "scrub report for !REDACTED", reported.Error())
+	assertEqual(t, "!REDACTED", Convert(reported).Properties["email"])
+	assertEqual(t, 404, StatusCode(reported))
+	assertTrue(t, Is(reported, original))
+
+	// Errors without sensitive data are reported as they are
+	original = New("scrub report")
+	Report(context.Background(), original)
+	assertEqual(t, original, reported)
+}
+
+func TestErrors_ScrubValueTypes(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	SetScrubRules(ScrubEmails)
+	defer SetScrubRules()
+
+	type contact struct {
+		Name  string
+		Email string
+	}
+	resolved := false
+	err := New("scrub value types",
+		"lazy", Lazy(func() any { return "bob@example.com" }),
+		"list", []any{"ok", "carol@example.com"},
+		"strings", []string{"ok", "dave@example.com"},
+		"array", [2]string{"ok", "erin@example.com"},
+		"bytes", []byte("frank@example.com"),
+		"struct", contact{Name: "Grace", Email: "grace@example.com"},
+		"nested", map[string]any{"list": []any{"heidi@example.com"}},
+		"clean", Lazy(func() any { resolved = true; return "nothing to see" }),
+		"number", 42,
+	)
+	scrubbed := Convert(err).scrubbed()
+	assertEqual(t, "!REDACTED", scrubbed.Properties["lazy"])
+	assertEqual(t, []any{"ok", "!REDACTED"}, scrubbed.Properties["list"])
+	assertEqual(t, []any{"ok", "!REDACTED"}, scrubbed.Properties["strings"])
+	assertEqual(t, []any{"ok", "!REDACTED"}, scrubbed.Properties["array"])
+	assertEqual(t, "!REDACTED", scrubbed.Properties["bytes"])
+	assertEqual(t, "{Grace !REDACTED}", scrubbed.Properties["struct"])
+	assertEqual(t, map[string]any{"list": []any{"!REDACTED"}}, scrubbed.Properties["nested"])
+	assertEqual(t, 42, scrubbed.Properties["number"])
+	assertTrue(t, resolved)
+
+	// The original properties are not modified
+	props := Convert(err).Properties
+	assertEqual(t, []any{"ok", "carol@example.com"}, props["list"])
+	assertEqual(t, []string{"ok", "dave@example.com"}, props["strings"])
+
+	// No output leaks the values
+	b, _ := json.Marshal(err)
+	assertTrue(t, !strings.Contains(string(b), "@example.com"))
+	assertTrue(t, !strings.Contains(Convert(err).String(), "@example.com"))
+	var buf bytes.Buffer
+	Log(context.Background(), slog.New(slog.NewJSONHandler(&buf, nil)), err)
+	assertTrue(t, !strings.Contains(buf.String(), "@example.com"))
+}
+
+func TestErrors_ScrubOutputs(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	SetScrubRules(ScrubEmails)
+	defer SetScrubRules()
+
+	const secret = "jane@example.com"
+	err := New("failed to notify %s", secret, 409, "email", secret, "code", "E_"+secret)
+	err = AddSuppressed(err, New("rollback for "+secret+" failed"))
+	err = AddSuppressed(err, fmt.Errorf("plain error for %s", secret))
+	tracedErr := Convert(err)
+
+	outputs := map[string]func() string{
+		"MarshalJSON": func() string {
+			b, _ := json.Marshal(err)
+			return string(b)
+		},
+		"MarshalText": func() string {
+			b, _ := tracedErr.MarshalText()
+			return string(b)
+		},
+		"MarshalBinary": func() string {
+			b, _ := tracedErr.MarshalBinary()
+			return string(b)
+		},
+		"MarshalCBOR": func() string {
+			b, _ := tracedErr.MarshalCBOR()
+			return string(b)
+		},
+		"Gob": func() string {
+			var buf bytes.Buffer
+			gob.NewEncoder(&buf).Encode(tracedErr)
+			return buf.String()
+		},
+		"ToProto": func() string {
+			b, _ := ToProto(err)
+			return string(b)
+		},
+		"EncodeToMap": func() string {
+			return fmt.Sprintf("%v", EncodeToMap(err))
+		},
+		"ToHeader": func() string {
+			h := http.Header{}
+			ToHeader(h, err)
+			return fmt.Sprintf("%v", h)
+		},
+		"String": func() string {
+			return tracedErr.String()
+		},
+		"Render": func() string {
+			return tracedErr.Render(RenderProduction)
+		},
+		"Format": func() string {
+			return fmt.Sprintf("%+v", err)
+		},
+		"Fprint": func() string {
+			var buf bytes.Buffer
+			Fprint(&buf, fmt.Errorf("wrapped for %s: %w", secret, err))
+			return buf.String()
+		},
+		"SlogHandler": func() string {
+			var buf bytes.Buffer
+			slog.New(SlogHandler(slog.NewJSONHandler(&buf, nil))).Error("failed", "err", err)
+			return buf.String()
+		},
+		"Log": func() string {
+			var buf bytes.Buffer
+			Log(context.Background(), slog.New(slog.NewJSONHandler(&buf, nil)), err)
+			return buf.String()
+		},
+		"WriteHTTP": func() string {
+			w := httptest.NewRecorder()
+			WriteHTTP(w, err)
+			return fmt.Sprintf("%v %s", w.Header(), w.Body.String())
+		},
+		"WriteProblem": func() string {
+			w := httptest.NewRecorder()
+			WriteProblem(w, err)
+			return fmt.Sprintf("%v %s", w.Header(), w.Body.String())
+		},
+		"Public": func() string {
+			return Public(context.Background(), err).Error() + Convert(Public(context.Background(), err)).String()
+		},
+		"ToGraphQLError": func() string {
+			b, _ := json.Marshal(ToGraphQLError(err))
+			return string(b)
+		},
+		"ToAPIGatewayResponse": func() string {
+			return fmt.Sprintf("%v", ToAPIGatewayResponse(err))
+		},
+	}
+	for name, output := range outputs {
+		out := output()
+		if strings.Contains(out, secret) {
+			t.Errorf("%s leaked the scrubbed value: %s", name, out)
+		}
+		if !strings.Contains(out, "!REDACTED") {
+			t.Errorf("%s did not include the redaction: %s", name, out)
+		}
+	}
+
+	// The original error is not modified
+	assertContains(t, err.Error(), secret)
+	assertTrue(t, Is(Scrub(err), err))
+	assertNil(t, Scrub(nil))
+}
@@ -0,0 +1,84 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import "strings"
+
+// StackSegment is a section of the stack trace of an error that was captured by a single call, or on one side of an async boundary.
+type StackSegment struct {
+	// Label describes the segment, e.g. "new", "trace" or "wrap" for the segment captured by New, Trace or a wrapping New,
+	// "async boundary" for the segment that follows Handoff, or "origin" for leading frames that are not labeled
+	Label string
+	// Frames are the stack frames of the segment, not including the boundary pseudo frame
+	Frames []*StackFrame
+}
+
+/*
+Segments returns the stack trace of the error divided at the sites where it was created, traced or wrapped,
+and at async boundaries, i.e. at the boundary pseudo frames added by Handoff,
+so that the frames captured by each call and by each goroutine can be told apart.
+Each segment is labeled by the site that captured it, per the Site of its first frame,
+or by the boundary that precedes it.
+Segments are derived from the flat Stack, which remains the representation of the error.
+Because the sites and boundary pseudo frames are part of the Stack, String shows the segments as distinct sections,
+and the segments are restored when the error is unmarshaled.
+
+	for _, seg := range tracedErr.Segments() {
+		fmt.Println(seg.Label)
+		for _, frame := range seg.Frames {
+			fmt.Println(frame)
+		}
+	}
+*/
+func (e *TracedError) Segments() []StackSegment {
+	if len(e.Stack) == 0 {
+		return nil
+	}
+	segments := []StackSegment{
+		{Label: "origin"},
+	}
+	for i, frame := range e.Stack {
+		last := &segments[len(segments)-1]
+		switch {
+		case frame.isBoundary():
+			segments = append(segments, StackSegment{
+				Label: strings.Trim(frame.Function, "— "),
+			})
+			continue
+		case frame.Site != "" && i == 0:
+			last.Label = frame.Site
+		case frame.Site != "" && len(last.Frames) > 0:
+			segments = append(segments, StackSegment{
+				Label: frame.Site,
+			})
+			last = &segments[len(segments)-1]
+		}
+		last.Frames = append(last.Frames, frame)
+	}
+	return segments
+}
+
+// markSite labels the first of the frames captured since the stack of the error had n frames with the site that captured them,
+// so that the frames start a new segment of the stack.
+// Frames captured by an earlier call are never modified because the stack may be shared with other errors.
+func markSite(err error, n int, site string) error {
+	tracedErr, ok := err.(*TracedError)
+	if ok && len(tracedErr.Stack) > n && !tracedErr.Stack[n].isPseudo() {
+		tracedErr.Stack[n].Site = site
+	}
+	return err
+}
@@ -0,0 +1,138 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestErrors_Segments(t *testing.T) {
+	t.Parallel()
+
+	err := New("oops")
+	err = Trace(err)
+	tracedErr := Convert(err)
+	segments := tracedErr.Segments()
+	assertEqual(t, 2, len(segments))
+	assertEqual(t, "new", segments[0].Label)
+	assertEqual(t, 1, len(segments[0].Frames))
+	assertEqual(t, "trace", segments[1].Label)
+	assertEqual(t, 1, len(segments[1].Frames))
+
+	tracedErr = &TracedError{
+		Err: New("async"),
+		Stack: []*StackFrame{
+			{Function: "producer", File: "producer.go", Line: 1},
+			{Function: asyncBoundary},
+			{Function: "consumer", File: "consumer.go", Line: 2},
+			{Function: "main", File: "main.go", Line: 3},
+		},
+	}
+	segments = tracedErr.Segments()
+	assertEqual(t, 2, len(segments))
+	assertEqual(t, "origin", segments[0].Label)
+	assertEqual(t, 1, len(segments[0].Frames))
+	assertEqual(t, "async boundary", segments[1].Label)
+	assertEqual(t, 2, len(segments[1].Frames))
+	assertEqual(t, "consumer", segments[1].Frames[0].Function)
+
+	// Sections are separated in the string representation
+	assertContains(t, tracedErr.String(), "producer.go:1\n\n- "+asyncBoundary+"\n- consumer")
+
+	// Segments are restored when unmarshaled
+	data, jsonErr := json.Marshal(tracedErr)
+	assertNil(t, jsonErr)
+	var unmarshaled TracedError
+	assertNil(t, json.Unmarshal(data, &unmarshaled))
+	assertEqual(t, segments, unmarshaled.Segments())
+
+	// Async boundaries divide the stack along with the sites
+	err = New("oops")
+	err = Handoff(err)
+	err = Trace(err)
+	segments = Convert(err).Segments()
+	assertEqual(t, 3, len(segments))
+	assertEqual(t, "new", segments[0].Label)
+	assertEqual(t, "handoff", segments[1].Label)
+	assertEqual(t, "async boundary", segments[2].Label)
+	assertEqual(t, 1, len(segments[2].Frames))
+
+	assertNil(t, (&TracedError{}).Segments())
+}
+
+func TestErrors_SegmentsSites(t *testing.T) {
+	t.Parallel()
+
+	err := New("oops", StackDepth(2))
+	err = Trace(err)
+	err = fmt.Errorf("wrapped by fmt: %w", err)
+	err = Trace(err)
+	err = New("failed to process", err)
+	err = With(err, "key", "value")
+	tracedErr := Convert(err)
+	segments := tracedErr.Segments()
+	assertEqual(t, 4, len(segments))
+	assertEqual(t, "new", segments[0].Label)
+	assertEqual(t, 2, len(segments[0].Frames))
+	assertEqual(t, "trace", segments[1].Label)
+	assertEqual(t, "trace", segments[2].Label)
+	assertEqual(t, "wrap", segments[3].Label)
+	for _, seg := range segments[1:] {
+		assertEqual(t, 1, len(seg.Frames))
+		assertEqual(t, "errors.TestErrors_SegmentsSites", seg.Frames[0].Function)
+	}
+
+	// Sites are shown as distinct sections
+	s := tracedErr.String()
+	assertContains(t, s, "\n\n- errors.TestErrors_SegmentsSites [new]\n")
+	assertContains(t, s, "\n\n- errors.TestErrors_SegmentsSites [trace]\n")
+	assertContains(t, s, "\n\n- errors.TestErrors_SegmentsSites [wrap]\n")
+
+	// Sites are restored when unmarshaled
+	data, jsonErr := json.Marshal(tracedErr)
+	assertNil(t, jsonErr)
+	var unmarshaled TracedError
+	assertNil(t, json.Unmarshal(data, &unmarshaled))
+	assertEqual(t, segments, unmarshaled.Segments())
+
+	data, binErr := tracedErr.MarshalBinary()
+	assertNil(t, binErr)
+	unmarshaled = TracedError{}
+	assertNil(t, unmarshaled.UnmarshalBinary(data))
+	assertEqual(t, segments, unmarshaled.Segments())
+
+	data, protoErr := ToProto(tracedErr)
+	assertNil(t, protoErr)
+	fromProto, protoErr := FromProto(data)
+	assertNil(t, protoErr)
+	assertEqual(t, segments, Convert(fromProto).Segments())
+
+	parsed, parseErr := ParseString(s)
+	assertNil(t, parseErr)
+	assertEqual(t, segments, parsed.Segments())
+
+	// Repeated tracing from the same location does not start a new segment
+	err = New("oops")
+	for range 3 {
+		err = Trace(err)
+	}
+	segments = Convert(err).Segments()
+	assertEqual(t, 2, len(segments))
+	assertEqual(t, 3, segments[1].Frames[0].Repeat)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	"sync"
+)
+
+// Sink receives the errors passed to Report, typically in order to forward them to a logger or an error tracking service.
+type Sink func(ctx context.Context, err error)
+
+var (
+	sinks    []*Sink
+	sinksMux sync.RWMutex
+)
+
+/*
+RegisterSink registers a sink to receive the errors passed to Report.
+Sinks are called in the order of their registration.
+The returned function unregisters the sink, for example at the end of a test.
+
+	errors.RegisterSink(func(ctx context.Context, err error) {
+		slog.ErrorContext(ctx, "Unexpected error", "err", err)
+	})
+*/
+func RegisterSink(sink Sink) (unregister func()) {
+	if sink == nil {
+		return func() {}
+	}
+	entry := &sink
+	sinksMux.Lock()
+	sinks = append(sinks, entry)
+	sinksMux.Unlock()
+	return func() {
+		sinksMux.Lock()
+		sinks = withoutEntry(sinks, entry)
+		sinksMux.Unlock()
+	}
+}
+
+// Report passes the error to all registered sinks. Nil errors are not reported.
+// The scrub rules set by SetScrubRules are applied to the message and properties of the error before it is passed to the sinks.
+func Report(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+	sinksMux.RLock()
+	registered := sinks
+	sinksMux.RUnlock()
+	if len(registered) > 0 && len(loadSettings().scrubRules) > 0 {
+		tracedErr := Convert(err)
+		if scrubbed := tracedErr.scrubbed(); scrubbed != tracedErr {
+			err = scrubbed
+		}
+	}
+	for _, sink := range registered {
+		(*sink)(ctx, err)
+	}
+}
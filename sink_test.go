@@ -0,0 +1,52 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestErrors_Report(t *testing.T) {
+	t.Parallel()
+
+	var mux sync.Mutex
+	var reported []string
+	unregister := RegisterSink(func(ctx context.Context, err error) {
+		if ctx.Value("test") == "TestErrors_Report" {
+			mux.Lock()
+			reported = append(reported, err.Error())
+			mux.Unlock()
+		}
+	})
+	RegisterSink(nil)()
+
+	ctx := context.WithValue(context.Background(), "test", "TestErrors_Report")
+	Report(ctx, New("oops"))
+	Report(ctx, nil)
+	Report(context.Background(), New("elsewhere"))
+
+	// Unregistered sinks are no longer called
+	unregister()
+	unregister()
+	Report(ctx, New("after"))
+
+	mux.Lock()
+	defer mux.Unlock()
+	assertEqual(t, []string{"oops"}, reported)
+}
@@ -0,0 +1,176 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+)
+
+/*
+SlogHandler wraps a slog handler and expands attributes whose value is a traced error into a group
+with the message, status code, trace ID, properties, stack and suppressed errors of the error.
+Errors that are not traced are passed through as-is.
+
+	logger := slog.New(errors.SlogHandler(slog.NewJSONHandler(os.Stderr, nil)))
+	logger.Error("Request failed", "err", err)
+*/
+func SlogHandler(inner slog.Handler) slog.Handler {
+	if h, ok := inner.(*slogHandler); ok {
+		return h
+	}
+	return &slogHandler{inner: inner}
+}
+
+// slogHandler is the slog handler returned by SlogHandler.
+type slogHandler struct {
+	inner slog.Handler
+}
+
+// Enabled delegates to the inner handler.
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+// Handle expands the traced errors in the record before passing it to the inner handler.
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	expanded := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		expanded.AddAttrs(expandSlogAttr(a))
+		return true
+	})
+	return h.inner.Handle(ctx, expanded)
+}
+
+// WithAttrs expands the traced errors in the attributes before passing them to the inner handler.
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	expanded := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		expanded = append(expanded, expandSlogAttr(a))
+	}
+	return &slogHandler{inner: h.inner.WithAttrs(expanded)}
+}
+
+// WithGroup delegates to the inner handler.
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	return &slogHandler{inner: h.inner.WithGroup(name)}
+}
+
+// expandSlogAttr expands the attribute into a group if its value is a traced error.
+func expandSlogAttr(a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		group := a.Value.Group()
+		expanded := make([]any, 0, len(group))
+		for _, ga := range group {
+			expanded = append(expanded, expandSlogAttr(ga))
+		}
+		return slog.Group(a.Key, expanded...)
+	case slog.KindAny:
+		err, ok := a.Value.Any().(error)
+		if !ok || findTraced(err) == nil {
+			return a
+		}
+		return slog.Attr{Key: a.Key, Value: slogValue(Convert(err))}
+	}
+	return a
+}
+
+// slogValue returns the group value of the traced error.
+func slogValue(e *TracedError) slog.Value {
+	e = e.scrubbed()
+	attrs := []slog.Attr{
+		slog.String("msg", e.Error()),
+	}
+	if e.StatusCode != 0 {
+		attrs = append(attrs, slog.Int("statusCode", e.StatusCode))
+	}
+	if e.Trace != "" && e.Trace != zeroTrace {
+		attrs = append(attrs, slog.String("trace", e.Trace))
+	}
+	if e.Span != "" && e.Span != zeroSpan {
+		attrs = append(attrs, slog.String("span", e.Span))
+	}
+	if e.Origin != OriginLocal {
+		attrs = append(attrs, slog.String("origin", e.Origin.String()))
+	}
+	if len(e.Properties) > 0 {
+		props := make([]slog.Attr, 0, len(e.Properties))
+		for _, k := range slices.Sorted(maps.Keys(e.Properties)) {
+			props = append(props, slog.Any(k, e.Properties[k]))
+		}
+		attrs = append(attrs, slog.Attr{Key: "properties", Value: slog.GroupValue(props...)})
+	}
+	if len(e.Stack) > 0 {
+		stack := make([]string, 0, len(e.Stack))
+		for _, frame := range e.Stack {
+			switch {
+			case frame.isPseudo():
+				stack = append(stack, frame.Function)
+			case frame.Repeat > 1:
+				stack = append(stack, fmt.Sprintf("%s (x%d) %s:%d", frame.Function, frame.Repeat, frame.File, frame.Line))
+			default:
+				stack = append(stack, fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line))
+			}
+		}
+		attrs = append(attrs, slog.Any("stack", stack))
+	}
+	if len(e.Suppressed) > 0 {
+		suppressed := make([]string, 0, len(e.Suppressed))
+		for _, s := range e.Suppressed {
+			if s != nil {
+				suppressed = append(suppressed, s.Error())
+			}
+		}
+		attrs = append(attrs, slog.Any("suppressed", suppressed))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// foldSlogAttr adds the slog attribute to the properties.
+// Group attributes become grouped properties, except for groups with an empty key, whose attributes are inlined.
+func foldSlogAttr(props map[string]any, a slog.Attr) {
+	v := a.Value.Resolve()
+	if v.Kind() != slog.KindGroup {
+		if a.Key != "" {
+			props[a.Key] = v.Any()
+		}
+		return
+	}
+	attrs := v.Group()
+	if len(attrs) == 0 {
+		return
+	}
+	if a.Key == "" {
+		for _, attr := range attrs {
+			foldSlogAttr(props, attr)
+		}
+		return
+	}
+	g := PropertyGroup{
+		Name:       a.Key,
+		Properties: GroupedProperties{},
+	}
+	for _, attr := range attrs {
+		foldSlogAttr(g.Properties, attr)
+	}
+	mergeGroup(props, g)
+}
@@ -0,0 +1,105 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestErrors_SlogHandler(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := slog.New(SlogHandler(slog.NewJSONHandler(&buf, nil)))
+
+	err := New("oops", 409, "0123456789abcdef0123456789abcdef", "key", "value")
+	err = AddSuppressed(err, New("rollback failed"))
+	logger.Error("Request failed", "err", err, "plain", stderrors.New("plain"), slog.Group("g", "nested", err))
+
+	var m map[string]any
+	assertNil(t, json.Unmarshal(buf.Bytes(), &m))
+	e, ok := m["err"].(map[string]any)
+	assertTrue(t, ok)
+	assertEqual(t, "oops", e["msg"])
+	assertEqual(t, 409.0, e["statusCode"])
+	assertEqual(t, "0123456789abcdef0123456789abcdef", e["trace"])
+	assertEqual(t, map[string]any{"key": "value"}, e["properties"])
+	stack, ok := e["stack"].([]any)
+	assertTrue(t, ok)
+	assertTrue(t, len(stack) > 0)
+	assertContains(t, stack[0].(string), "errors/slog_test.go:")
+	assertEqual(t, []any{"rollback failed"}, e["suppressed"])
+
+	// Non-traced errors are passed through
+	assertEqual(t, "plain", m["plain"])
+
+	// Groups are expanded recursively
+	g, ok := m["g"].(map[string]any)
+	assertTrue(t, ok)
+	nested, ok := g["nested"].(map[string]any)
+	assertTrue(t, ok)
+	assertEqual(t, "oops", nested["msg"])
+
+	// WithAttrs
+	buf.Reset()
+	logger.With("err", err).WithGroup("grp").Info("Hello")
+	m = nil
+	assertNil(t, json.Unmarshal(buf.Bytes(), &m))
+	e, ok = m["err"].(map[string]any)
+	assertTrue(t, ok)
+	assertEqual(t, "oops", e["msg"])
+
+	// Not wrapped twice
+	h := SlogHandler(slog.NewJSONHandler(&buf, nil))
+	assertEqual(t, h, SlogHandler(h))
+}
+
+func TestErrors_SlogAttrArgs(t *testing.T) {
+	t.Parallel()
+
+	type userID string
+	err := New("query failed",
+		slog.String("query", "SELECT 1"),
+		slog.Int("rows", 5),
+		slog.Group("conn", slog.String("host", "localhost"), slog.Group("pool", "size", 10)),
+		slog.Group("", slog.Bool("inlined", true)),
+		slog.Group("empty"),
+		slog.Any("user", slog.AnyValue(userID("u123"))),
+	)
+	err = Trace(err, slog.Duration("elapsed", time.Second))
+	err = With(err, slog.Any("status", "retrying"))
+	props := Convert(err).Properties
+	assertEqual(t, "SELECT 1", props["query"])
+	assertEqual(t, int64(5), props["rows"])
+	assertEqual(t, true, props["inlined"])
+	assertEqual(t, time.Second, props["elapsed"])
+	assertEqual(t, "retrying", props["status"])
+	assertEqual(t, userID("u123"), props["user"])
+	conn := props["conn"].(GroupedProperties)
+	assertEqual(t, "localhost", conn["host"])
+	assertEqual(t, int64(10), conn["pool"].(GroupedProperties)["size"])
+	_, ok := props["empty"]
+	assertTrue(t, !ok)
+
+	g := Group("db", slog.String("table", "users"))
+	assertEqual(t, "users", g.Properties["table"])
+}
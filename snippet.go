@@ -0,0 +1,140 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"container/list"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	// maxSnippetFiles limits the number of source files whose lines are cached for snippets
+	maxSnippetFiles = 32
+	// maxSnippetFileSize limits the size of the source files read for snippets
+	maxSnippetFileSize = 1024 * 1024
+)
+
+var (
+	// localFiles maps the file names of locally captured stack frames to the full paths of the source files.
+	// Only these source files are read for snippets, so that frames unmarshaled from untrusted peers cannot name arbitrary files.
+	localFiles registry[string, string]
+	// sourceFiles caches the lines of the source files read for snippets.
+	sourceFiles = newLRUCache(maxSnippetFiles)
+)
+
+// registerLocalFile records the full path of the source file of a locally captured stack frame.
+func registerLocalFile(file string, fullPath string) {
+	localFiles.register(file, fullPath)
+}
+
+// sourceLines returns the lines of the source file of a locally captured stack frame,
+// or nil if the file is not available or the frame was not captured locally.
+func sourceLines(file string) []string {
+	fullPath, ok := localFiles.lookup(file)
+	if !ok {
+		return nil
+	}
+	if cached, ok := sourceFiles.get(fullPath); ok {
+		return cached
+	}
+	var lines []string
+	if info, err := os.Stat(fullPath); err == nil && info.Mode().IsRegular() && info.Size() <= maxSnippetFileSize {
+		body, err := os.ReadFile(fullPath)
+		if err == nil {
+			lines = strings.Split(string(body), "\n")
+		}
+	}
+	sourceFiles.put(fullPath, lines)
+	return lines
+}
+
+// lruCache is a cache of the lines of source files that evicts the least recently used file when full.
+type lruCache struct {
+	capacity int
+	order    *list.List // Of *lruEntry, most recently used first
+	entries  map[string]*list.Element
+	mux      sync.Mutex
+}
+
+// lruEntry is an entry in the LRU cache.
+type lruEntry struct {
+	key   string
+	lines []string
+}
+
+// newLRUCache creates a new LRU cache of the indicated capacity.
+func newLRUCache(capacity int) *lruCache {
+	return &lruCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  map[string]*list.Element{},
+	}
+}
+
+// get returns the cached lines of the file, marking it as recently used.
+func (c *lruCache) get(key string) ([]string, bool) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry).lines, true
+}
+
+// put caches the lines of the file, evicting the least recently used file if the cache is full.
+func (c *lruCache) put(key string, lines []string) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*lruEntry).lines = lines
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, lines: lines})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// snippet returns the line of source code of the stack frame, surrounded by one line of context on each side.
+// An empty string is returned if the source file is not available or if the frame was not captured locally.
+func (t *StackFrame) snippet() string {
+	if t.isPseudo() || t.Line <= 0 {
+		return ""
+	}
+	lines := sourceLines(t.File)
+	if t.Line > len(lines) {
+		return ""
+	}
+	width := len(fmt.Sprintf("%d", t.Line+1))
+	var b strings.Builder
+	for n := max(t.Line-1, 1); n <= min(t.Line+1, len(lines)); n++ {
+		marker := " "
+		if n == t.Line {
+			marker = ">"
+		}
+		fmt.Fprintf(&b, "\n  %s %*d | %s", marker, width, n, strings.TrimRight(lines[n-1], " \t\r"))
+	}
+	return b.String()
+}
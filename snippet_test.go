@@ -0,0 +1,92 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestErrors_Snippet(t *testing.T) {
+	t.Parallel()
+
+	err := New("snippet") // Marker line
+	frame := Convert(err).Stack[0]
+	snippet := frame.snippet()
+	lines := strings.Split(strings.TrimPrefix(snippet, "\n"), "\n")
+	assertEqual(t, 3, len(lines))
+	assertContains(t, lines[0], fmt.Sprintf("  %d | ", frame.Line-1))
+	assertContains(t, lines[1], fmt.Sprintf("> %d | ", frame.Line))
+	assertContains(t, lines[1], "// Marker line")
+	assertContains(t, lines[2], "frame := Convert(err).Stack[0]")
+
+	// Missing source file
+	frame = &StackFrame{Function: "main.main", File: "non/existent.go", Line: 10}
+	assertEqual(t, "", frame.snippet())
+	frame = &StackFrame{Function: "main.main", File: "snippet_test.go", Line: 100000}
+	assertEqual(t, "", frame.snippet())
+}
+
+func TestErrors_SnippetUntrustedFrame(t *testing.T) {
+	t.Parallel()
+
+	// Frames that were not captured locally do not read source files
+	wd, _ := os.Getwd()
+	frame := &StackFrame{Function: "main.main", File: filepath.Join(wd, "go.mod"), Line: 1}
+	assertEqual(t, "", frame.snippet())
+
+	// Frames unmarshaled from a peer do not read source files, even if they name a locally captured file
+	local := Convert(New("snippet")).Stack[0]
+	var unmarshaled TracedError
+	data, _ := json.Marshal(&TracedError{Err: New("remote"), Stack: []*StackFrame{{Function: "main.main", File: filepath.Join(wd, "go.mod"), Line: 1}}})
+	assertNil(t, json.Unmarshal(data, &unmarshaled))
+	assertEqual(t, "", unmarshaled.Stack[0].snippet())
+	assertTrue(t, local.snippet() != "")
+}
+
+func TestErrors_SnippetCacheBounded(t *testing.T) {
+	t.Parallel()
+
+	cache := newLRUCache(2)
+	cache.put("a", []string{"a"})
+	cache.put("b", []string{"b"})
+	_, ok := cache.get("a")
+	assertTrue(t, ok)
+	cache.put("c", []string{"c"})
+	_, ok = cache.get("b")
+	assertTrue(t, !ok)
+	_, ok = cache.get("a")
+	assertTrue(t, ok)
+	_, ok = cache.get("c")
+	assertTrue(t, ok)
+	assertEqual(t, 2, len(cache.entries))
+}
+
+func TestErrors_DebugModeString(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	err := New("snippet") // Marker line
+	assertTrue(t, !strings.Contains(Convert(err).String(), "// Marker line"))
+
+	SetDebugMode(true)
+	defer SetDebugMode(false)
+	assertContains(t, Convert(err).String(), "// Marker line")
+	assertContains(t, fmt.Sprintf("%+v", err), "// Marker line")
+}
@@ -0,0 +1,51 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"database/sql/driver"
+)
+
+// Value implements driver.Valuer so that the error can be stored in a JSON or text column.
+// The error is stored in its JSON representation.
+func (e *TracedError) Value() (driver.Value, error) {
+	if e == nil {
+		return nil, nil
+	}
+	b, err := e.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner so that the error can be loaded from a JSON or text column.
+// A NULL value resets the error to its zero value.
+// Neither the type of the error nor any errors it wraps can be restored.
+func (e *TracedError) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*e = TracedError{}
+		return nil
+	case []byte:
+		return e.UnmarshalJSON(v)
+	case string:
+		return e.UnmarshalJSON([]byte(v))
+	default:
+		return New("unsupported scan type %T", src)
+	}
+}
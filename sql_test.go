@@ -0,0 +1,65 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+)
+
+func TestErrors_ValueScan(t *testing.T) {
+	t.Parallel()
+
+	var _ driver.Valuer = &TracedError{}
+	var _ sql.Scanner = &TracedError{}
+
+	original := Convert(New("oops", 409, "0123456789abcdef0123456789abcdef", "key", "value"))
+	v, err := original.Value()
+	assertNil(t, err)
+	s, ok := v.(string)
+	assertTrue(t, ok)
+	assertContains(t, s, `"error":"oops"`)
+
+	for _, src := range []any{s, []byte(s)} {
+		var scanned TracedError
+		err = scanned.Scan(src)
+		assertNil(t, err)
+		assertEqual(t, "oops", scanned.Error())
+		assertEqual(t, 409, scanned.StatusCode)
+		assertEqual(t, "0123456789abcdef0123456789abcdef", scanned.Trace)
+		assertEqual(t, "value", scanned.Properties["key"])
+		assertEqual(t, original.Stack, scanned.Stack)
+	}
+
+	// NULL
+	var nilErr *TracedError
+	v, err = nilErr.Value()
+	assertNil(t, err)
+	assertNil(t, v)
+	scanned := *original
+	err = scanned.Scan(nil)
+	assertNil(t, err)
+	assertEqual(t, 0, scanned.StatusCode)
+	assertNil(t, scanned.Stack)
+
+	// Unsupported
+	err = scanned.Scan(123)
+	assertError(t, err)
+	err = scanned.Scan("not json")
+	assertError(t, err)
+}
@@ -0,0 +1,140 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// statsSlots is the number of slots that the window of Stats is divided into.
+// The window rolls forward one slot at a time.
+const statsSlots = 60
+
+/*
+Stats maintains counts of errors over a rolling window of time, by status class, by error code and by fingerprint.
+It is intended to be registered as a sink, and queried by health endpoints and load shedding logic.
+
+	stats := errors.NewStats(time.Minute)
+	errors.RegisterSink(stats.Observe)
+	...
+	if stats.Snapshot().ByStatusClass["5xx"] > threshold {
+		...
+	}
+*/
+type Stats struct {
+	window   time.Duration
+	slotSize time.Duration
+	slots    [statsSlots]statsSlot
+	mux      sync.Mutex
+	now      func() time.Time
+}
+
+// statsSlot holds the counts of a single slot of the window.
+type statsSlot struct {
+	epoch         int64
+	total         int
+	byStatusClass map[string]int
+	byCode        map[string]int
+	byFingerprint map[string]int
+}
+
+// StatsSnapshot is the counts of errors over the window of Stats at a point in time.
+type StatsSnapshot struct {
+	Window time.Duration
+	Total  int
+	// ByStatusClass is keyed by the class of the status code, e.g. "4xx" or "5xx"
+	ByStatusClass map[string]int
+	// ByCode is keyed by the "code" property of the errors that have one
+	ByCode map[string]int
+	// ByFingerprint is keyed by the fingerprint of the errors, as returned by Key
+	ByFingerprint map[string]int
+}
+
+// NewStats creates a new collector of statistics over a rolling window of the indicated duration.
+// The window rolls forward in increments of 1/60 of its duration.
+func NewStats(window time.Duration) *Stats {
+	window = max(window, statsSlots)
+	return &Stats{
+		window:   window,
+		slotSize: window / statsSlots,
+		now:      time.Now,
+	}
+}
+
+// Observe counts the error. It has the signature of a Sink so that it can be passed to RegisterSink.
+func (s *Stats) Observe(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+	tracedErr := Convert(err)
+	statusClass := fmt.Sprintf("%dxx", tracedErr.StatusCode/100)
+	fingerprint := tracedErr.Key()
+	code, hasCode := tracedErr.Properties["code"]
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	epoch := s.now().UnixNano() / int64(s.slotSize)
+	slot := &s.slots[epoch%statsSlots]
+	if slot.epoch != epoch || slot.total == 0 {
+		*slot = statsSlot{
+			epoch:         epoch,
+			byStatusClass: map[string]int{},
+			byCode:        map[string]int{},
+			byFingerprint: map[string]int{},
+		}
+	}
+	slot.total++
+	slot.byStatusClass[statusClass]++
+	slot.byFingerprint[fingerprint]++
+	if hasCode {
+		slot.byCode[fmt.Sprintf("%v", code)]++
+	}
+}
+
+// Snapshot returns the counts of the errors observed during the window.
+func (s *Stats) Snapshot() StatsSnapshot {
+	snapshot := StatsSnapshot{
+		Window:        s.window,
+		ByStatusClass: map[string]int{},
+		ByCode:        map[string]int{},
+		ByFingerprint: map[string]int{},
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	epoch := s.now().UnixNano() / int64(s.slotSize)
+	for i := range s.slots {
+		slot := &s.slots[i]
+		if slot.total == 0 || slot.epoch <= epoch-statsSlots || slot.epoch > epoch {
+			continue
+		}
+		snapshot.Total += slot.total
+		addCounts(snapshot.ByStatusClass, slot.byStatusClass)
+		addCounts(snapshot.ByCode, slot.byCode)
+		addCounts(snapshot.ByFingerprint, slot.byFingerprint)
+	}
+	return snapshot
+}
+
+// addCounts adds the counts of the source to the destination.
+func addCounts(dst, src map[string]int) {
+	for k, n := range src {
+		dst[k] += n
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestErrors_Stats(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	stats := NewStats(time.Minute)
+	stats.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	newNotFound := func() error {
+		return New("not found", 404, "code", "NOT_FOUND")
+	}
+	stats.Observe(ctx, newNotFound())
+	stats.Observe(ctx, newNotFound())
+	stats.Observe(ctx, New("oops"))
+	stats.Observe(ctx, nil)
+
+	snapshot := stats.Snapshot()
+	assertEqual(t, time.Minute, snapshot.Window)
+	assertEqual(t, 3, snapshot.Total)
+	assertEqual(t, map[string]int{"4xx": 2, "5xx": 1}, snapshot.ByStatusClass)
+	assertEqual(t, map[string]int{"NOT_FOUND": 2}, snapshot.ByCode)
+	assertEqual(t, 2, len(snapshot.ByFingerprint))
+	assertEqual(t, 2, snapshot.ByFingerprint[Convert(newNotFound()).Key()])
+
+	// Roll the window partially
+	now = now.Add(30 * time.Second)
+	stats.Observe(ctx, New("oops", 503))
+	snapshot = stats.Snapshot()
+	assertEqual(t, 4, snapshot.Total)
+	assertEqual(t, map[string]int{"4xx": 2, "5xx": 2}, snapshot.ByStatusClass)
+
+	// The first errors fall out of the window
+	now = now.Add(45 * time.Second)
+	snapshot = stats.Snapshot()
+	assertEqual(t, 1, snapshot.Total)
+	assertEqual(t, map[string]int{"5xx": 1}, snapshot.ByStatusClass)
+	assertEqual(t, map[string]int{}, snapshot.ByCode)
+
+	// The slots are reused
+	now = now.Add(time.Hour)
+	stats.Observe(ctx, newNotFound())
+	snapshot = stats.Snapshot()
+	assertEqual(t, 1, snapshot.Total)
+	assertEqual(t, map[string]int{"4xx": 1}, snapshot.ByStatusClass)
+}
+
+func TestErrors_StatsConcurrency(t *testing.T) {
+	t.Parallel()
+
+	stats := NewStats(time.Minute)
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				stats.Observe(context.Background(), New("oops"))
+				stats.Snapshot()
+			}
+		}()
+	}
+	wg.Wait()
+	assertEqual(t, 800, stats.Snapshot().Total)
+}
@@ -0,0 +1,113 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"slices"
+	"sync"
+)
+
+// StatusMapper maps an error that is not a traced error to an HTTP status code.
+// It returns false if it does not recognize the error.
+type StatusMapper func(err error) (statusCode int, ok bool)
+
+var (
+	statusMappers    []*StatusMapper
+	statusMappersMux sync.RWMutex
+)
+
+// builtInStatusCodes are the status codes of well-known sentinel errors of the standard library.
+var builtInStatusCodes = []struct {
+	target     error
+	statusCode int
+}{
+	{fs.ErrNotExist, 404},
+	{fs.ErrPermission, 403},
+	{context.DeadlineExceeded, 504},
+	{os.ErrDeadlineExceeded, 504},
+}
+
+/*
+RegisterStatusMapper registers a mapper that determines the status code of errors that are not traced errors,
+typically sentinel errors or error types of third-party libraries.
+Mappers are consulted in the order of their registration, before the built-in mapping of standard library errors.
+The returned function unregisters the mapper, for example at the end of a test.
+
+	errors.RegisterStatusMapper(func(err error) (int, bool) {
+		if errors.Is(err, sql.ErrNoRows) {
+			return http.StatusNotFound, true
+		}
+		return 0, false
+	})
+*/
+func RegisterStatusMapper(mapper StatusMapper) (unregister func()) {
+	if mapper == nil {
+		return func() {}
+	}
+	entry := &mapper
+	statusMappersMux.Lock()
+	statusMappers = append(statusMappers, entry)
+	statusMappersMux.Unlock()
+	return func() {
+		statusMappersMux.Lock()
+		statusMappers = withoutEntry(statusMappers, entry)
+		statusMappersMux.Unlock()
+	}
+}
+
+// RegisterStatusCode registers a status code for errors that match the target error per Is.
+// The returned function unregisters the status code.
+func RegisterStatusCode(target error, statusCode int) (unregister func()) {
+	return RegisterStatusMapper(func(err error) (int, bool) {
+		if Is(err, target) {
+			return statusCode, true
+		}
+		return 0, false
+	})
+}
+
+// withoutEntry returns a copy of the registered entries without the entry.
+// The entries are copied rather than modified because readers may be iterating over them without holding the lock.
+func withoutEntry[T any](entries []*T, entry *T) []*T {
+	return slices.DeleteFunc(slices.Clone(entries), func(e *T) bool {
+		return e == entry
+	})
+}
+
+// mapStatusCode returns the status code associated with the error by the status mappers, or 500 if none is found.
+func mapStatusCode(err error) int {
+	if err == nil {
+		return 500
+	}
+	statusMappersMux.RLock()
+	mappers := statusMappers
+	statusMappersMux.RUnlock()
+	for _, mapper := range mappers {
+		if statusCode, ok := (*mapper)(err); ok && statusCode != 0 {
+			return statusCode
+		}
+	}
+	for _, m := range builtInStatusCodes {
+		if Is(err, m.target) {
+			return m.statusCode
+		}
+	}
+	return 500
+}
@@ -0,0 +1,73 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestErrors_StatusCodeWrapped(t *testing.T) {
+	t.Parallel()
+
+	err := New("not found", 404)
+	err = fmt.Errorf("third party: %w", err)
+	assertEqual(t, 404, StatusCode(err))
+	err = fmt.Errorf("again: %w", err)
+	assertEqual(t, 404, StatusCode(err))
+	assertEqual(t, 0, StatusCode(nil))
+	assertEqual(t, 500, StatusCode(stderrors.New("plain")))
+}
+
+func TestErrors_StatusCodeBuiltIn(t *testing.T) {
+	t.Parallel()
+
+	_, err := os.Open("non/existent.file")
+	assertEqual(t, 404, StatusCode(err))
+	assertEqual(t, 404, StatusCode(Trace(err)))
+	assertEqual(t, 404, StatusCode(New("failed to open", err)))
+	assertEqual(t, 400, StatusCode(Trace(err, 400)))
+	assertEqual(t, 504, StatusCode(fmt.Errorf("waiting: %w", context.DeadlineExceeded)))
+	assertEqual(t, 403, StatusCode(os.ErrPermission))
+}
+
+func TestErrors_RegisterStatusMapper(t *testing.T) {
+	t.Parallel()
+
+	errConflict := stderrors.New("conflict sentinel")
+	errTeapot := stderrors.New("teapot sentinel")
+	unregister := RegisterStatusCode(errConflict, 409)
+	defer RegisterStatusMapper(func(err error) (int, bool) {
+		if Is(err, errTeapot) {
+			return 418, true
+		}
+		return 0, false
+	})()
+
+	assertEqual(t, 409, StatusCode(errConflict))
+	assertEqual(t, 409, StatusCode(fmt.Errorf("wrapped: %w", errConflict)))
+	assertEqual(t, 409, StatusCode(Trace(errConflict)))
+	assertEqual(t, 418, StatusCode(New("brewing", errTeapot)))
+	assertEqual(t, 500, StatusCode(New("brewing")))
+
+	unregister()
+	assertEqual(t, 500, StatusCode(errConflict))
+	assertEqual(t, 418, StatusCode(New("brewing", errTeapot)))
+}
@@ -0,0 +1,174 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"slices"
+)
+
+/*
+WithTenant scopes the error to a tenant of a multi-tenant system.
+The tenant ID is stored in the "tenant" property of the error, which can also be set at construction.
+
+	return errors.WithTenant(err, tenantID)
+	return errors.New("quota exceeded", http.StatusTooManyRequests, "tenant", tenantID)
+*/
+func WithTenant(err error, tenantID string) error {
+	if err == nil {
+		return nil
+	}
+	return With(err, "tenant", tenantID)
+}
+
+// Tenant returns the ID of the tenant the error is scoped to, if any.
+func Tenant(err error) string {
+	if err == nil {
+		return ""
+	}
+	tenant, ok := tracedProperties(err)["tenant"]
+	if !ok || tenant == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", tenant)
+}
+
+/*
+ForTenant prepares the error to be returned to the indicated tenant, so that the identifiers of one tenant never reach another.
+An error that is scoped to another tenant, or that wraps or joins any error scoped to another tenant,
+is replaced by an error that retains only its status code, trace ID and "code" property,
+with the status text as its message. The replacement does not wrap the original error.
+Suppressed errors that are scoped to another tenant, or that wrap or suppress such errors, are dropped.
+Errors that are not scoped to any tenant, or are scoped to the indicated tenant, are returned as they are.
+ForTenant is applied automatically by Public, WriteHTTPCtx, RecoverHandler and AttachWarnings
+to a context marked by ContextWithTenant.
+
+	err = errors.ForTenant(err, requestTenantID)
+	errors.WriteHTTP(w, err)
+*/
+func ForTenant(err error, tenantID string) error {
+	if err == nil {
+		return nil
+	}
+	tracedErr := Convert(err)
+	if !scopedToTenant(tracedErr, tenantID) || wrapsOtherTenant(tracedErr.Err, tenantID) {
+		msg := statusText[tracedErr.StatusCode]
+		if msg == "" {
+			msg = statusText[500]
+		}
+		scrubbed := &TracedError{
+			Err:        stderrors.New(msg),
+			StatusCode: tracedErr.StatusCode,
+			Trace:      tracedErr.Trace,
+			Span:       tracedErr.Span,
+		}
+		if code, ok := tracedErr.Properties["code"]; ok {
+			scrubbed.Properties = map[string]any{"code": code}
+		}
+		return scrubbed
+	}
+	otherTenant := func(s error) bool {
+		var visited visitedSet
+		visited.visit(tracedErr)
+		return exposesOtherTenant(s, tenantID, &visited)
+	}
+	if !slices.ContainsFunc(tracedErr.Suppressed, otherTenant) {
+		return err
+	}
+	tracedErr = deriveTraced(err)
+	tracedErr.Suppressed = slices.DeleteFunc(slices.Clone(tracedErr.Suppressed), otherTenant)
+	return tracedErr
+}
+
+// scopedToTenant indicates if the traced error is either not scoped to any tenant, or is scoped to the indicated tenant.
+func scopedToTenant(tracedErr *TracedError, tenantID string) bool {
+	tenant, ok := tracedErr.Properties["tenant"]
+	if !ok || tenant == nil {
+		return true
+	}
+	return fmt.Sprintf("%v", tenant) == tenantID
+}
+
+// wrapsOtherTenant indicates if any traced error in the error tree, including wrapped and joined errors,
+// is scoped to a tenant other than the indicated one.
+func wrapsOtherTenant(err error, tenantID string) bool {
+	found := false
+	Walk(err, func(e error) bool {
+		if tracedErr, ok := e.(*TracedError); ok && !scopedToTenant(tracedErr, tenantID) {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// exposesOtherTenant indicates if the error, the errors it wraps or its suppressed errors
+// are scoped to a tenant other than the indicated one.
+func exposesOtherTenant(err error, tenantID string, visited *visitedSet) bool {
+	if err == nil || !visited.visit(err) {
+		return false
+	}
+	tracedErr := Convert(err)
+	if !scopedToTenant(tracedErr, tenantID) || wrapsOtherTenant(tracedErr.Err, tenantID) {
+		return true
+	}
+	for _, s := range tracedErr.Suppressed {
+		if exposesOtherTenant(s, tenantID, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// tenantContextKey is the key of the tenant of the request in the context.
+type tenantContextKey struct{}
+
+/*
+ContextWithTenant marks the context with the ID of the tenant on whose behalf the request is served,
+so that errors exposed to it by Public, WriteHTTPCtx, RecoverHandler and AttachWarnings are prepared per ForTenant.
+Marking the context once, typically in a middleware, enforces the isolation of tenants for all the responses of the request.
+
+	ctx := errors.ContextWithTenant(r.Context(), tenantID)
+	...
+	errors.WriteHTTPCtx(ctx, w, err)
+*/
+func ContextWithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+// RequestTenant returns the ID of the tenant the context was marked with by ContextWithTenant, if any.
+func RequestTenant(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	tenantID, _ := ctx.Value(tenantContextKey{}).(string)
+	return tenantID
+}
+
+// forRequestTenant applies ForTenant to the error with the tenant of the context, if it was marked by ContextWithTenant.
+func forRequestTenant(ctx context.Context, err error) error {
+	if ctx == nil {
+		return err
+	}
+	tenantID, ok := ctx.Value(tenantContextKey{}).(string)
+	if !ok {
+		return err
+	}
+	return ForTenant(err, tenantID)
+}
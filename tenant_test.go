@@ -0,0 +1,146 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestErrors_Tenant(t *testing.T) {
+	t.Parallel()
+
+	err := New("quota exceeded", 429, "code", "QUOTA")
+	assertEqual(t, "", Tenant(err))
+	scoped := WithTenant(err, "acme")
+	assertEqual(t, "acme", Tenant(scoped))
+	assertEqual(t, "", Tenant(err))
+	assertEqual(t, "acme", Tenant(New("failed", scoped)))
+	assertEqual(t, "globex", Tenant(New("failed", "tenant", "globex")))
+
+	data, jsonErr := json.Marshal(scoped)
+	assertNil(t, jsonErr)
+	assertContains(t, string(data), `"tenant":"acme"`)
+
+	assertNil(t, WithTenant(nil, "acme"))
+	assertEqual(t, "", Tenant(nil))
+}
+
+func TestErrors_ForTenant(t *testing.T) {
+	t.Parallel()
+
+	traceID := "0123456789abcdef0123456789abcdef"
+	err := New("document 123 of acme not found", 404, traceID, "code", "NOT_FOUND", "tenant", "acme", "doc", "123")
+
+	// Same tenant
+	assertEqual(t, err, ForTenant(err, "acme"))
+
+	// Another tenant
+	scrubbed := ForTenant(err, "globex")
+	assertEqual(t, "not found", scrubbed.Error())
+	assertEqual(t, 404, StatusCode(scrubbed))
+	assertEqual(t, traceID, Convert(scrubbed).Trace)
+	assertEqual(t, map[string]any{"code": "NOT_FOUND"}, Convert(scrubbed).Properties)
+	assertTrue(t, !Is(scrubbed, err))
+	data, jsonErr := json.Marshal(scrubbed)
+	assertNil(t, jsonErr)
+	assertTrue(t, !strings.Contains(string(data), "acme"))
+
+	// Unscoped errors
+	unscoped := New("oops")
+	assertEqual(t, unscoped, ForTenant(unscoped, "globex"))
+
+	// Suppressed errors of another tenant are dropped
+	withSuppressed := AddSuppressed(New("failed", "tenant", "globex"), New("rollback of acme failed", "tenant", "acme"))
+	withSuppressed = AddSuppressed(withSuppressed, New("cleanup failed"))
+	forGlobex := ForTenant(withSuppressed, "globex")
+	assertEqual(t, 1, len(Convert(forGlobex).Suppressed))
+	assertEqual(t, "cleanup failed", Convert(forGlobex).Suppressed[0].Error())
+	assertEqual(t, 2, len(Convert(withSuppressed).Suppressed))
+
+	// Joined errors of another tenant
+	joined := stderrors.Join(New("user alice@a.com not found", 404, "tenant", "acme"), New("other"))
+	scrubbed = ForTenant(joined, "globex")
+	assertTrue(t, !strings.Contains(scrubbed.Error(), "alice"))
+	assertEqual(t, 404, StatusCode(scrubbed))
+	assertEqual(t, joined, ForTenant(joined, "acme"))
+
+	// Outer wrapper re-tagged with another tenant
+	retagged := New("outer", WithTenant(New("user alice@a.com not found", 404), "acme"), "tenant", "globex")
+	assertEqual(t, "globex", Tenant(retagged))
+	scrubbed = ForTenant(retagged, "globex")
+	assertTrue(t, !strings.Contains(scrubbed.Error(), "alice"))
+	scrubbed = ForTenant(retagged, "acme")
+	assertTrue(t, !strings.Contains(scrubbed.Error(), "alice"))
+
+	// Suppressed errors that wrap errors of another tenant are dropped
+	wrapping := New("rollback failed", New("row of acme locked", "tenant", "acme"))
+	withSuppressed = AddSuppressed(New("failed", "tenant", "globex"), wrapping)
+	withSuppressed = AddSuppressed(withSuppressed, wrapping)
+	assertEqual(t, 0, len(Convert(ForTenant(withSuppressed, "globex")).Suppressed))
+
+	assertNil(t, ForTenant(nil, "acme"))
+}
+
+func TestErrors_ContextWithTenant(t *testing.T) {
+	t.Parallel()
+
+	err := New("document 123 of acme not found", 404, "code", "NOT_FOUND", "tenant", "acme")
+	assertEqual(t, "", RequestTenant(context.Background()))
+	globex := ContextWithTenant(context.Background(), "globex")
+	acme := ContextWithTenant(context.Background(), "acme")
+	assertEqual(t, "globex", RequestTenant(globex))
+
+	// Public
+	assertEqual(t, "not found", Public(globex, err).Error())
+	assertEqual(t, "document 123 of acme not found", Public(acme, err).Error())
+	assertEqual(t, "document 123 of acme not found", Public(context.Background(), err).Error())
+	assertEqual(t, "document 123 of acme not found", Public(nil, err).Error())
+
+	// WriteHTTPCtx
+	w := httptest.NewRecorder()
+	WriteHTTPCtx(globex, w, err)
+	assertEqual(t, 404, w.Code)
+	assertTrue(t, !strings.Contains(w.Body.String(), "acme"))
+	w = httptest.NewRecorder()
+	WriteHTTPCtx(acme, w, err)
+	assertContains(t, w.Body.String(), "acme")
+
+	// RecoverHandler
+	handler := RecoverHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(err)
+	}))
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil).WithContext(globex)
+	handler.ServeHTTP(w, r)
+	assertEqual(t, 404, w.Code)
+	assertTrue(t, !strings.Contains(w.Body.String(), "acme"))
+
+	// AttachWarnings
+	ctx := ContextWithWarnings(globex)
+	Warn(ctx, "row of acme skipped", "tenant", "acme")
+	envelope := map[string]any{}
+	AttachWarnings(ctx, envelope)
+	data, jsonErr := json.Marshal(envelope)
+	assertNil(t, jsonErr)
+	assertTrue(t, !strings.Contains(string(data), "acme"))
+}
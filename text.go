@@ -0,0 +1,204 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+/*
+MarshalText marshals the error to a compact single-line representation that omits the stack trace.
+Fields are separated by a pipe, and the values of properties are JSON-encoded.
+The span ID, if any, follows the trace ID separated by a dash, and the origin, if not local, follows the status code separated by a space.
+Pipes, backslashes and line breaks are escaped with a backslash, as are equal signs in the names of properties.
+
+	oops | 502 upstream | 0123456789abcdef0123456789abcdef-0123456789abcdef | id=123 | name="x"
+*/
+func (e *TracedError) MarshalText() ([]byte, error) {
+	e = e.scrubbed()
+	var b strings.Builder
+	b.WriteString(escapeTextField(e.Error()))
+	b.WriteString(" | ")
+	if e.StatusCode != 0 || e.Origin != OriginLocal {
+		b.WriteString(strconv.Itoa(e.StatusCode))
+	}
+	if e.Origin != OriginLocal {
+		b.WriteString(" ")
+		b.WriteString(e.Origin.String())
+	}
+	b.WriteString(" | ")
+	if e.Trace != "" && e.Trace != zeroTrace {
+		b.WriteString(e.Trace)
+		if e.Span != "" && e.Span != zeroSpan {
+			b.WriteString("-")
+			b.WriteString(e.Span)
+		}
+	}
+	for _, k := range slices.Sorted(maps.Keys(e.Properties)) {
+		v, err := json.Marshal(e.Properties[k])
+		if err != nil {
+			v, _ = json.Marshal(fmt.Sprintf("%v", e.Properties[k]))
+		}
+		b.WriteString(" | ")
+		b.WriteString(escapeTextKey(k))
+		b.WriteString("=")
+		b.WriteString(escapeTextField(string(v)))
+	}
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText unmarshals the error from the single-line representation produced by MarshalText.
+// Values of properties that are not valid JSON are restored as strings.
+// Neither the type of the error, the stack trace, nor any errors it wraps can be restored.
+func (e *TracedError) UnmarshalText(text []byte) error {
+	fields := splitTextFields(string(text))
+	if len(fields) < 3 {
+		return New("malformed error text")
+	}
+	e.Err = stderrors.New(unescapeTextField(strings.TrimSuffix(fields[0], " ")))
+	e.StatusCode = 0
+	status, origin, _ := strings.Cut(strings.TrimSpace(fields[1]), " ")
+	e.Origin = parseOrigin(origin)
+	if status != "" {
+		code, err := strconv.Atoi(status)
+		if err != nil {
+			return New("malformed status code '%s'", status, err)
+		}
+		e.StatusCode = code
+	}
+	e.Trace, e.Span, _ = strings.Cut(strings.TrimSpace(fields[2]), "-")
+	e.Stack = nil
+	e.Suppressed = nil
+	e.Properties = nil
+	for _, field := range fields[3:] {
+		field = strings.TrimPrefix(strings.TrimSuffix(field, " "), " ")
+		k, v, ok := cutUnescaped(field, '=')
+		if !ok {
+			return New("malformed property '%s'", field)
+		}
+		k = unescapeTextField(k)
+		v = unescapeTextField(v)
+		var value any
+		if json.Unmarshal([]byte(v), &value) != nil {
+			value = v
+		}
+		if e.Properties == nil {
+			e.Properties = map[string]any{}
+		}
+		e.Properties[k] = value
+	}
+	return nil
+}
+
+// escapeTextField escapes pipes, backslashes and line breaks.
+func escapeTextField(s string) string {
+	if !strings.ContainsAny(s, "|\\\n\r") {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '|':
+			b.WriteString(`\|`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// escapeTextKey escapes the name of a property as does escapeTextField, as well as equal signs,
+// which separate the name of a property from its value.
+func escapeTextKey(k string) string {
+	return strings.ReplaceAll(escapeTextField(k), "=", `\=`)
+}
+
+// unescapeTextField reverses escapeTextField and escapeTextKey.
+func unescapeTextField(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	escaped := false
+	for _, r := range s {
+		if escaped {
+			switch r {
+			case 'n':
+				b.WriteRune('\n')
+			case 'r':
+				b.WriteRune('\r')
+			default:
+				b.WriteRune(r)
+			}
+			escaped = false
+			continue
+		}
+		if r == '\\' {
+			escaped = true
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// cutUnescaped slices the text around the first instance of the separator that is not escaped.
+func cutUnescaped(s string, sep byte) (before string, after string, found bool) {
+	escaped := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case s[i] == '\\':
+			escaped = true
+		case s[i] == sep:
+			return s[:i], s[i+1:], true
+		}
+	}
+	return s, "", false
+}
+
+// splitTextFields splits the text on pipes that are not escaped.
+func splitTextFields(s string) []string {
+	var fields []string
+	start := 0
+	escaped := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case s[i] == '\\':
+			escaped = true
+		case s[i] == '|':
+			fields = append(fields, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(fields, s[start:])
+}
@@ -0,0 +1,80 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestErrors_MarshalText(t *testing.T) {
+	t.Parallel()
+
+	err := New("oops | multi\nline \\ message", 404, "00-0123456789abcdef0123456789abcdef-0123456789abcdef-01",
+		"id", 123,
+		"name", "x | y",
+		"func", func() {},
+	)
+	text, marshalErr := Convert(err).MarshalText()
+	assertNil(t, marshalErr)
+	assertTrue(t, !strings.Contains(string(text), "\n"))
+	assertTrue(t, strings.HasPrefix(string(text), `oops \| multi\nline \\ message | 404 | 0123456789abcdef0123456789abcdef-0123456789abcdef | func="0x`))
+	assertTrue(t, strings.HasSuffix(string(text), ` | id=123 | name="x \| y"`))
+
+	var decoded TracedError
+	marshalErr = decoded.UnmarshalText(text)
+	assertNil(t, marshalErr)
+	assertEqual(t, "oops | multi\nline \\ message", decoded.Error())
+	assertEqual(t, 404, decoded.StatusCode)
+	assertEqual(t, "0123456789abcdef0123456789abcdef", decoded.Trace)
+	assertEqual(t, "0123456789abcdef", decoded.Span)
+	assertEqual(t, 123.0, decoded.Properties["id"])
+	assertEqual(t, "x | y", decoded.Properties["name"])
+	assertEqual(t, 0, len(decoded.Stack))
+
+	// No status code, trace or properties
+	text, _ = Convert(New("plain")).MarshalText()
+	assertEqual(t, "plain | 500 | ", string(text))
+	text, _ = (&TracedError{Err: New("bare")}).MarshalText()
+	assertEqual(t, "bare |  | ", string(text))
+	marshalErr = decoded.UnmarshalText(text)
+	assertNil(t, marshalErr)
+	assertEqual(t, "bare", decoded.Error())
+	assertEqual(t, 0, decoded.StatusCode)
+	assertEqual(t, "", decoded.Trace)
+	assertEqual(t, "", decoded.Span)
+	assertNil(t, decoded.Properties)
+
+	// Equal signs in the names of properties
+	text, _ = Convert(New("oops", "a=b", "c=d", "x\\=", 1)).MarshalText()
+	assertContains(t, string(text), ` | a\=b="c=d" | `)
+	marshalErr = decoded.UnmarshalText(text)
+	assertNil(t, marshalErr)
+	assertEqual(t, "c=d", decoded.Properties["a=b"])
+	assertEqual(t, 1.0, decoded.Properties["x\\="])
+	assertEqual(t, 2, len(decoded.Properties))
+
+	// Non-JSON values are restored as strings
+	marshalErr = decoded.UnmarshalText([]byte("oops | 400 |  | user=bob"))
+	assertNil(t, marshalErr)
+	assertEqual(t, "bob", decoded.Properties["user"])
+
+	// Malformed
+	assertError(t, decoded.UnmarshalText([]byte("oops")))
+	assertError(t, decoded.UnmarshalText([]byte("oops | abc | ")))
+	assertError(t, decoded.UnmarshalText([]byte("oops | 400 |  | novalue")))
+}
@@ -0,0 +1,153 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	"time"
+)
+
+// startTimeContextKey is the key of the start time of the operation in the context.
+type startTimeContextKey struct{}
+
+// ContextWithStartTime marks the context with the current time as the start time of the operation,
+// so that WithTimeout can determine how long the operation ran before failing.
+func ContextWithStartTime(ctx context.Context) context.Context {
+	return context.WithValue(ctx, startTimeContextKey{}, time.Now())
+}
+
+/*
+WithTimeout annotates the error with the timing of the operation per its context.
+If the error or the context indicates that the context is done, the error is given the status code 504 if the deadline was exceeded
+or 499 if the context was canceled, unless the error already has a status code other than 500.
+The following properties are attached:
+
+  - "deadlineIn" is the time remaining until the deadline of the context, if it has one. It is negative if the deadline had passed
+  - "elapsed" is the time since the start of the operation, if the context was marked by ContextWithStartTime
+  - "ctxErr" is the error of the context, if it is done
+
+If the error is the generic error of a context that was canceled with a specific cause, per context.Cause,
+the cause is wrapped along with the error so that the actual reason for the cancellation appears in the chain,
+e.g. "context canceled: database is down".
+The original error is not modified.
+
+	ctx = errors.ContextWithStartTime(ctx)
+	err := query(ctx)
+	if err != nil {
+		return errors.WithTimeout(ctx, err)
+	}
+*/
+func WithTimeout(ctx context.Context, err error) error {
+	if err == nil || ctx == nil {
+		return err
+	}
+	err = wrapCancelCause(ctx, err)
+	args := timeoutProperties(ctx)
+	if StatusCode(err) == 500 {
+		switch {
+		case Is(err, context.Canceled):
+			args = append(args, 499)
+		case Is(err, context.DeadlineExceeded), Is(ctx.Err(), context.DeadlineExceeded):
+			args = append(args, 504)
+		case ctx.Err() != nil:
+			args = append(args, 499)
+		}
+	}
+	if len(args) == 0 {
+		return err
+	}
+	return With(err, args...)
+}
+
+// timeoutProperties returns the properties describing the timing of the operation per its context.
+func timeoutProperties(ctx context.Context) []any {
+	var args []any
+	now := time.Now()
+	if deadline, ok := ctx.Deadline(); ok {
+		args = append(args, "deadlineIn", deadline.Sub(now).Round(time.Millisecond).String())
+	}
+	if start, ok := ctx.Value(startTimeContextKey{}).(time.Time); ok {
+		args = append(args, "elapsed", now.Sub(start).Round(time.Millisecond).String())
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		args = append(args, "ctxErr", ctxErr.Error())
+	}
+	return args
+}
+
+// wrapCancelCause wraps the cause of the cancellation of the context along with the error,
+// if the error is the generic error of the context and the context was canceled with a specific cause.
+func wrapCancelCause(ctx context.Context, err error) error {
+	ctxErr := ctx.Err()
+	if ctxErr == nil || !Is(err, ctxErr) {
+		return err
+	}
+	cause := context.Cause(ctx)
+	if cause == nil || cause == ctxErr || Is(err, cause) {
+		return err
+	}
+	return New("", err, cause)
+}
+
+/*
+CancelCause returns the cause of the cancellation of the context as a traced error, annotated per WithTimeout,
+or nil if the context is not done.
+The cause is that passed to the cancel function of context.WithCancelCause, or otherwise the error of the context.
+
+	select {
+	case <-ctx.Done():
+		return errors.CancelCause(ctx)
+	case res := <-results:
+		...
+	}
+*/
+func CancelCause(ctx context.Context) error {
+	if ctx == nil {
+		return nil
+	}
+	cause := context.Cause(ctx)
+	if cause == nil {
+		return nil
+	}
+	return WithTimeout(ctx, Trace(cause))
+}
+
+/*
+WithCancelCause is like context.WithCancelCause, except that the cause passed to the cancel function is traced,
+so that consumers of context.Cause, such as CancelCause, see where the context was canceled in addition to why.
+Canceling with a nil cause traces context.Canceled.
+
+	ctx, cancel := errors.WithCancelCause(ctx)
+	defer cancel(nil)
+	...
+	if err != nil {
+		cancel(err) // The location of this line is appended to the stack of err
+	}
+*/
+func WithCancelCause(parent context.Context) (context.Context, context.CancelCauseFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+	return ctx, func(cause error) {
+		if ctx.Err() != nil {
+			// Only the first cancellation takes effect
+			return
+		}
+		if cause == nil {
+			cause = context.Canceled
+		}
+		cancel(Trace(cause))
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestErrors_WithTimeout(t *testing.T) {
+	t.Parallel()
+
+	// Deadline exceeded
+	ctx, cancel := context.WithTimeout(ContextWithStartTime(context.Background()), 10*time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+	err := WithTimeout(ctx, New("query failed"))
+	assertEqual(t, 504, StatusCode(err))
+	props := Convert(err).Properties
+	assertEqual(t, "context deadline exceeded", props["ctxErr"])
+	elapsed, parseErr := time.ParseDuration(props["elapsed"].(string))
+	assertNil(t, parseErr)
+	assertTrue(t, elapsed >= 10*time.Millisecond)
+	deadlineIn, parseErr := time.ParseDuration(props["deadlineIn"].(string))
+	assertNil(t, parseErr)
+	assertTrue(t, deadlineIn <= 0)
+
+	// Client cancelation
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	err = WithTimeout(ctx, New("query failed"))
+	assertEqual(t, 499, StatusCode(err))
+	props = Convert(err).Properties
+	assertEqual(t, "context canceled", props["ctxErr"])
+	_, ok := props["elapsed"]
+	assertTrue(t, !ok)
+	_, ok = props["deadlineIn"]
+	assertTrue(t, !ok)
+
+	// Context error returned after the context is replaced
+	err = WithTimeout(context.Background(), context.DeadlineExceeded)
+	assertEqual(t, 504, StatusCode(err))
+	err = WithTimeout(context.Background(), context.Canceled)
+	assertEqual(t, 499, StatusCode(err))
+
+	// Context not done
+	ctx, cancel = context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	original := New("bad input", 400)
+	err = WithTimeout(ctx, original)
+	assertEqual(t, 400, StatusCode(err))
+	_, ok = Convert(err).Properties["ctxErr"]
+	assertTrue(t, !ok)
+	_, ok = Convert(err).Properties["deadlineIn"]
+	assertTrue(t, ok)
+	_, ok = Convert(original).Properties["deadlineIn"]
+	assertTrue(t, !ok)
+
+	// No annotations
+	assertEqual(t, original, WithTimeout(context.Background(), original))
+	assertNil(t, WithTimeout(ctx, nil))
+}
+
+func TestErrors_WithTimeoutCancelCause(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cause := New("database is down", 503)
+	cancel(cause)
+
+	err := WithTimeout(ctx, ctx.Err())
+	assertEqual(t, "context canceled: database is down", err.Error())
+	assertTrue(t, Is(err, context.Canceled))
+	assertTrue(t, Is(err, cause))
+	// The status code of the cause is respected
+	assertEqual(t, 503, StatusCode(err))
+
+	// Errors that are not the generic error of the context are not affected
+	err = WithTimeout(ctx, New("oops"))
+	assertEqual(t, "oops", err.Error())
+
+	// The cause is not wrapped twice
+	err = WithTimeout(ctx, WithTimeout(ctx, ctx.Err()))
+	assertEqual(t, "context canceled: database is down", err.Error())
+
+	// Generic cancellation
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	cancelFunc()
+	err = WithTimeout(ctx, ctx.Err())
+	assertEqual(t, "context canceled", err.Error())
+}
+
+func TestErrors_CancelCause(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	assertNil(t, CancelCause(ctx))
+	assertNil(t, CancelCause(nil))
+
+	cause := New("database is down", 503)
+	cancel(cause)
+	err := CancelCause(ctx)
+	assertEqual(t, "database is down", err.Error())
+	assertEqual(t, 503, StatusCode(err))
+	assertTrue(t, Is(err, cause))
+	assertEqual(t, "context canceled", Convert(err).Properties["ctxErr"])
+	stack := Convert(err).Stack
+	assertEqual(t, "errors.TestErrors_CancelCause", stack[len(stack)-1].Function)
+
+	// Generic cancellation
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	cancelFunc()
+	err = CancelCause(ctx)
+	assertTrue(t, Is(err, context.Canceled))
+	assertEqual(t, 499, StatusCode(err))
+}
+
+func TestErrors_WithCancelCause(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := WithCancelCause(context.Background())
+	cause := New("database is down", 503)
+	cancel(cause) // Cancellation site
+	cancel(New("ignored"))
+	assertTrue(t, Is(ctx.Err(), context.Canceled))
+
+	err := context.Cause(ctx)
+	assertTrue(t, Is(err, cause))
+	assertEqual(t, "database is down", err.Error())
+	stack := Convert(err).Stack
+	assertEqual(t, 2, len(stack))
+	assertEqual(t, "errors.TestErrors_WithCancelCause", stack[1].Function)
+	assertEqual(t, stack[0].Line+1, stack[1].Line)
+
+	// Nil cause
+	ctx, cancel = WithCancelCause(context.Background())
+	cancel(nil)
+	err = context.Cause(ctx)
+	assertTrue(t, Is(err, context.Canceled))
+	assertEqual(t, 1, len(Convert(err).Stack))
+	assertEqual(t, 499, StatusCode(CancelCause(ctx)))
+}
+
+func TestErrors_TraceWithContext(t *testing.T) {
+	t.Parallel()
+
+	// No deadline
+	err := Trace(New("oops"), context.Background())
+	assertEqual(t, 0, len(Convert(err).Properties))
+
+	// Deadline not yet reached
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	err = Trace(New("oops"), ctx)
+	deadlineIn, parseErr := time.ParseDuration(Convert(err).Properties["deadlineIn"].(string))
+	assertNil(t, parseErr)
+	assertTrue(t, deadlineIn > 59*time.Minute)
+	_, ok := Convert(err).Properties["ctxErr"]
+	assertTrue(t, !ok)
+
+	// Done
+	cancel()
+	err = New("oops", ctx)
+	assertEqual(t, "context canceled", Convert(err).Properties["ctxErr"])
+
+	// Explicit properties are not overridden
+	err = New("oops", ctx, "ctxErr", "explicit")
+	assertEqual(t, "explicit", Convert(err).Properties["ctxErr"])
+}
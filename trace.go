@@ -17,8 +17,13 @@ limitations under the License.
 package errors
 
 import (
+	"fmt"
+	"maps"
 	"runtime"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // traceCaller appends the stack location of the caller to the error's stack trace.
@@ -26,29 +31,65 @@ func traceCaller(err error) error {
 	if err == nil {
 		return nil
 	}
-	level := 1
 	tracedErr := Convert(err)
+	if loadSettings().disableStacks {
+		return tracedErr
+	}
+	frame, ok := callerFrame()
+	if !ok {
+		return tracedErr
+	}
+	if n := len(tracedErr.Stack); n > 0 && tracedErr.Stack[n-1].sameLocation(frame) {
+		// Collapse repeated tracing from the same location, e.g. in a retry loop
+		repeated := *tracedErr.Stack[n-1]
+		repeated.Repeat = max(repeated.Repeat, 1) + 1
+		repeated.owner = nil
+		// The stack may be shared with other errors so the frame is replaced rather than modified
+		tracedErr.Stack = slices.Clone(tracedErr.Stack)
+		tracedErr.Stack[n-1] = &repeated
+		return tracedErr
+	}
+	tracedErr.Stack = appendFrame(tracedErr.Stack, tracedErr.ownFrame(frame))
+	return tracedErr
+}
+
+// callerFrame returns the first frame of the call stack that is kept by the stack filters, starting at the caller of traceCaller.
+// The frame is usually among the first few, in which case the full call stack is not captured.
+func callerFrame() (*StackFrame, bool) {
+	pcs := make([]uintptr, 8)
+	n := runtime.Callers(3, pcs)
+	if frame, ok := firstKeptFrame(runtime.CallersFrames(pcs[:n])); ok || n < len(pcs) {
+		return frame, ok
+	}
+	return firstKeptFrame(callers(1))
+}
+
+// firstKeptFrame returns the first of the frames that is kept by the stack filters.
+func firstKeptFrame(frames *runtime.Frames) (*StackFrame, bool) {
 	for {
-		file, function, line, ok := runtimeTrace(level)
-		if !ok {
-			return tracedErr
+		runtimeFrame, more := frames.Next()
+		if runtimeFrame.PC == 0 {
+			return nil, false
 		}
-		if strings.HasPrefix(function, "runtime.") || (strings.HasPrefix(function, "errors.") && !strings.HasPrefix(function, "errors.Test")) {
-			level++
-			continue
+		if frame := stackFrameOf(runtimeFrame); keepFrame(frame) {
+			return allocCopy(frame), true
+		}
+		if !more {
+			return nil, false
 		}
-		tracedErr.Stack = append(tracedErr.Stack, &StackFrame{
-			File:     file,
-			Function: function,
-			Line:     line,
-		})
-		return tracedErr
 	}
 }
 
 // traceFull appends the full stack to the error's stack trace, starting at the indicated level.
 // Level 0 captures the location of the caller.
+// The number of frames is limited by the configured maximum stack depth.
 func traceFull(err error, level int) error {
+	return traceStack(err, level+1, loadSettings().maxStackDepth)
+}
+
+// traceStack appends up to maxDepth frames of the stack to the error's stack trace, starting at the indicated level.
+// Level 0 captures the location of the caller. A max depth of 0 indicates no limit.
+func traceStack(err error, level int, maxDepth int) error {
 	if err == nil {
 		return nil
 	}
@@ -56,43 +97,183 @@ func traceFull(err error, level int) error {
 		level = 0
 	}
 	tracedErr := Convert(err)
+	if loadSettings().disableStacks {
+		return tracedErr
+	}
 
-	levels := level - 1
+	captured := 0
+	elided := 0
+	frames := callers(level)
 	for {
-		levels++
-		file, function, line, ok := runtimeTrace(1 + levels)
-		if !ok {
+		runtimeFrame, more := frames.Next()
+		if runtimeFrame.PC == 0 {
 			break
 		}
-		if function == "errors.CatchPanic" {
+		frame := stackFrameOf(runtimeFrame)
+		if isPanicBoundary(frame.Function) {
 			break
 		}
-		if strings.HasPrefix(function, "runtime.") || (strings.HasPrefix(function, "errors.") && !strings.HasPrefix(function, "errors.Test")) {
-			continue
+		if keepFrame(frame) {
+			if maxDepth > 0 && captured >= maxDepth {
+				elided++
+			} else {
+				tracedErr.Stack = appendFrame(tracedErr.Stack, tracedErr.ownFrame(allocCopy(frame)))
+				captured++
+			}
+		}
+		if !more {
+			break
 		}
-		tracedErr.Stack = append(tracedErr.Stack, &StackFrame{
-			File:     file,
-			Function: function,
-			Line:     line,
-		})
+	}
+	if elided > 0 {
+		tracedErr.Stack = appendFrame(tracedErr.Stack, elisionFrame(elided))
 	}
 	return tracedErr
 }
 
-// runtimeTrace traces back by the amount of levels to retrieve the runtime information used for tracing.
-func runtimeTrace(levels int) (file string, function string, line int, ok bool) {
-	pc, file, line, ok := runtime.Caller(levels + 1)
-	if !ok {
-		return "", "", 0, false
+// isPanicBoundary indicates if the function recovers panics, in which case frames beyond it are not relevant to the panic.
+func isPanicBoundary(function string) bool {
+	return function == "errors.CatchPanic" || function == "errors.CatchPanicExcept" || function == "errors.CatchPanicCtx" || strings.HasPrefix(function, "errors.RecoverHandler.")
+}
+
+// appendFrame appends a frame to the stack, respecting the configured maximum number of frames.
+// Frames beyond the maximum are counted by a trailing elision frame.
+func appendFrame(stack []*StackFrame, frame *StackFrame) []*StackFrame {
+	maxFrames := loadSettings().maxStackFrames
+	if maxFrames <= 0 || len(stack) < maxFrames {
+		return append(stack, frame)
+	}
+	n := 1
+	if frame.isElision() {
+		n = frame.elided()
+	}
+	if last := stack[len(stack)-1]; last.isElision() && len(stack) > maxFrames {
+		// The stack may be shared with other errors so the elision frame is replaced rather than modified
+		stack = slices.Clone(stack)
+		stack[len(stack)-1] = elisionFrame(last.elided() + n)
+		return stack
+	}
+	return append(stack, elisionFrame(n))
+}
+
+// elisionFrame returns a pseudo frame indicating that a number of frames were omitted.
+func elisionFrame(n int) *StackFrame {
+	return &StackFrame{
+		Function: fmt.Sprintf("... %d more", n),
 	}
-	function = "?"
-	runtimeFunc := runtime.FuncForPC(pc)
-	if runtimeFunc != nil {
-		function = runtimeFunc.Name()
-		p := strings.LastIndex(function, "/")
-		if p >= 0 {
+}
+
+// callers returns the frames of the call stack, starting at the indicated level.
+// Level 0 is the caller of the function calling callers.
+// Inlined functions are reported as distinct frames.
+func callers(level int) *runtime.Frames {
+	pcs := make([]uintptr, 32)
+	for {
+		n := runtime.Callers(level+3, pcs)
+		if n < len(pcs) {
+			return runtime.CallersFrames(pcs[:n])
+		}
+		pcs = make([]uintptr, 2*len(pcs))
+	}
+}
+
+// newStackFrame creates a stack frame from a runtime frame.
+// The file is trimmed per the package settings and the function is qualified by the last element of its package path.
+func newStackFrame(runtimeFrame runtime.Frame) *StackFrame {
+	return allocCopy(stackFrameOf(runtimeFrame))
+}
+
+// stackFrameOf returns the stack frame of a runtime frame, without allocating it.
+// The file is trimmed per the package settings and the function is qualified by the last element of its package path.
+func stackFrameOf(runtimeFrame runtime.Frame) StackFrame {
+	function := runtimeFrame.Function
+	file := runtimeFrame.File
+	if function == "" {
+		function = "?"
+	} else {
+		file = trimFile(file, function)
+		registerLocalFile(file, runtimeFrame.File)
+		if p := strings.LastIndex(shortFunctionPrefix(function), "/"); p >= 0 {
 			function = function[p+1:]
 		}
+		registerLocalFunction(file, function, runtimeFrame.Function)
+	}
+	return StackFrame{
+		File:     file,
+		Function: function,
+		Line:     runtimeFrame.Line,
+	}
+}
+
+// localFunction identifies the function of a locally captured stack frame by its file and short name.
+type localFunction struct {
+	file     string
+	function string
+}
+
+// localFunctions maps the functions of locally captured stack frames to their names qualified by their full import path,
+// which the stack frames do not retain.
+var localFunctions registry[localFunction, string]
+
+// registerLocalFunction records the full name of the function of a locally captured stack frame.
+func registerLocalFunction(file string, function string, fullFunction string) {
+	localFunctions.register(localFunction{file: file, function: function}, fullFunction)
+}
+
+// fullFunction returns the name of the function of the stack frame qualified by its full import path,
+// or an empty string if the function was not captured locally.
+func (t *StackFrame) fullFunction() string {
+	fullFunction, _ := localFunctions.lookup(localFunction{file: t.File, function: t.Function})
+	return fullFunction
+}
+
+// registry is a map that is read on every captured stack frame but written only the first time a key is seen.
+// Reads load an immutable snapshot of the map without locking, and writes replace the snapshot with an updated copy.
+type registry[K comparable, V any] struct {
+	snapshot atomic.Pointer[map[K]V]
+	mux      sync.Mutex
+}
+
+// lookup returns the value registered for the key.
+func (r *registry[K, V]) lookup(key K) (value V, ok bool) {
+	if m := r.snapshot.Load(); m != nil {
+		value, ok = (*m)[key]
+	}
+	return value, ok
+}
+
+// register records the value of the key, unless the key is already registered.
+func (r *registry[K, V]) register(key K, value V) {
+	if _, ok := r.lookup(key); ok {
+		return
+	}
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if _, ok := r.lookup(key); ok {
+		return
+	}
+	updated := map[K]V{}
+	if m := r.snapshot.Load(); m != nil {
+		updated = maps.Clone(*m)
+	}
+	updated[key] = value
+	r.snapshot.Store(&updated)
+}
+
+// shortFunctionPrefix returns the function name up to the type parameters of a generic function, if any.
+func shortFunctionPrefix(function string) string {
+	if p := strings.Index(function, "["); p >= 0 {
+		return function[:p]
+	}
+	return function
+}
+
+// runtimeTrace traces back by the amount of levels to retrieve the runtime information used for tracing.
+func runtimeTrace(levels int) (file string, function string, line int, ok bool) {
+	runtimeFrame, _ := callers(levels).Next()
+	if runtimeFrame.PC == 0 {
+		return "", "", 0, false
 	}
-	return file, function, line, ok
+	frame := newStackFrame(runtimeFrame)
+	return frame.File, frame.Function, frame.Line, true
 }
@@ -18,6 +18,8 @@ package errors
 
 import (
 	stderrors "errors"
+	"strconv"
+	"sync"
 	"testing"
 )
 
@@ -53,3 +55,74 @@ func TestErrors_TraceCaller(t *testing.T) {
 	err0 := traceCaller(err)
 	assertEqual(t, "errors.TestErrors_TraceCaller", Convert(err0).Stack[0].Function)
 }
+
+func TestErrors_TraceRepeated(t *testing.T) {
+	t.Parallel()
+
+	err := New("oops")
+	original := Convert(err).Stack[0]
+	for range 5 {
+		err = Trace(err)
+	}
+	err = Trace(err)
+	stack := Convert(err).Stack
+	assertEqual(t, 3, len(stack))
+	assertEqual(t, 0, stack[0].Repeat)
+	assertEqual(t, 5, stack[1].Repeat)
+	assertEqual(t, 0, stack[2].Repeat)
+	assertContains(t, stack[1].String(), "(x5)")
+	assertEqual(t, original, stack[0])
+
+	// Tracing the same error twice does not interfere
+	base := New("base")
+	e1 := Trace(base)
+	e2 := Trace(base, 400)
+	assertEqual(t, 2, len(Convert(e1).Stack))
+	assertEqual(t, 2, len(Convert(e2).Stack))
+	assertNotEqual(t, Convert(e1).Stack[1].Line, Convert(e2).Stack[1].Line)
+}
+
+func TestErrors_TraceInlined(t *testing.T) {
+	t.Parallel()
+
+	// The closure is small enough to be inlined by the compiler
+	newErr := func() error {
+		return New("inlined")
+	}
+	err := newErr()
+	assertEqual(t, "errors.TestErrors_TraceInlined.func1", Convert(err).Stack[0].Function)
+
+	err = traceFull(stderrors.New("full"), 0)
+	stack := Convert(err).Stack
+	assertEqual(t, "errors.TestErrors_TraceInlined", stack[0].Function)
+	assertEqual(t, "testing.tRunner", stack[1].Function)
+}
+
+func TestErrors_Registry(t *testing.T) {
+	t.Parallel()
+
+	var r registry[string, int]
+	_, ok := r.lookup("a")
+	assertTrue(t, !ok)
+	r.register("a", 1)
+	r.register("a", 2)
+	v, ok := r.lookup("a")
+	assertTrue(t, ok)
+	assertEqual(t, 1, v)
+
+	var wg sync.WaitGroup
+	for i := range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.register(strconv.Itoa(i), i)
+			r.lookup("a")
+		}()
+	}
+	wg.Wait()
+	for i := range 16 {
+		v, ok := r.lookup(strconv.Itoa(i))
+		assertTrue(t, ok)
+		assertEqual(t, i, v)
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Headers used to propagate the trace context, per W3C Trace Context and Zipkin B3.
+const (
+	HeaderTraceparent = "Traceparent"
+	HeaderB3          = "B3"
+	HeaderB3TraceID   = "X-B3-Traceid"
+	HeaderB3SpanID    = "X-B3-Spanid"
+)
+
+/*
+RequestTrace returns the trace ID and span ID of the incoming request, as indicated by its traceparent header,
+its single b3 header, or its multiple X-B3-* headers, in that order of precedence.
+Malformed or all-zero IDs are ignored. 64-bit B3 trace IDs are left-padded with zeros.
+
+	traceID, spanID := errors.RequestTrace(r)
+*/
+func RequestTrace(r *http.Request) (traceID string, spanID string) {
+	if r == nil {
+		return "", ""
+	}
+	h := r.Header
+	if tp := strings.TrimSpace(h.Get(HeaderTraceparent)); isTraceparent(tp) {
+		traceID, spanID, _ = traceContextArg(tp)
+		return validTraceContext(traceID, spanID)
+	}
+	if b3 := strings.TrimSpace(h.Get(HeaderB3)); b3 != "" {
+		parts := strings.Split(b3, "-")
+		if len(parts) >= 2 {
+			return validTraceContext(b3TraceID(parts[0]), strings.ToLower(parts[1]))
+		}
+	}
+	return validTraceContext(b3TraceID(strings.TrimSpace(h.Get(HeaderB3TraceID))), strings.ToLower(strings.TrimSpace(h.Get(HeaderB3SpanID))))
+}
+
+// b3TraceID normalizes a B3 trace ID, which may be either 64 or 128 bits long, to the 128-bit form of a trace ID.
+func b3TraceID(s string) string {
+	if isSpanID(s) {
+		s = "0000000000000000" + s
+	}
+	return strings.ToLower(s)
+}
+
+// validTraceContext returns the trace ID and span ID, blanking those that are malformed or all zeros.
+func validTraceContext(traceID string, spanID string) (string, string) {
+	if !IsValidTraceID(traceID) {
+		traceID = ""
+	}
+	if !isSpanID(spanID) || spanID == zeroSpan {
+		spanID = ""
+	}
+	return traceID, spanID
+}
+
+/*
+WithRequestTrace populates the trace ID and span ID of the error from the trace context headers of the incoming request,
+for services that do not run a full OpenTelemetry SDK. IDs that are already set on the error are retained.
+A nil error is returned as nil.
+
+	err = errors.WithRequestTrace(err, r)
+*/
+func WithRequestTrace(err error, r *http.Request) error {
+	if err == nil {
+		return nil
+	}
+	traceID, spanID := RequestTrace(r)
+	if TraceID(err) != "" {
+		traceID = ""
+	}
+	if SpanID(err) != "" {
+		spanID = ""
+	}
+	if traceID == "" && spanID == "" {
+		return err
+	}
+	derived := deriveTraced(err)
+	if traceID != "" {
+		derived.Trace = traceID
+	}
+	if spanID != "" {
+		derived.Span = spanID
+	}
+	return derived
+}
+
+/*
+ToTraceHeaders writes the trace ID and span ID of the error to the traceparent and b3 headers of a response,
+so that callers that do not run a full OpenTelemetry SDK can correlate the error with their trace.
+The headers are written only if the error has both a trace ID and a span ID.
+The sampling decision is not indicated because it is not known to the error.
+
+	errors.ToTraceHeaders(w.Header(), err)
+	errors.WriteHTTP(w, err)
+*/
+func ToTraceHeaders(h http.Header, err error) {
+	if err == nil || h == nil {
+		return
+	}
+	traceID, spanID := TraceID(err), SpanID(err)
+	if traceID == "" || spanID == "" {
+		return
+	}
+	h.Set(HeaderTraceparent, "00-"+traceID+"-"+spanID+"-00")
+	h.Set(HeaderB3, traceID+"-"+spanID)
+}
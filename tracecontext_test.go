@@ -0,0 +1,108 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrors_RequestTrace(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest("GET", "/", nil)
+	traceID, spanID := RequestTrace(r)
+	assertEqual(t, "", traceID)
+	assertEqual(t, "", spanID)
+
+	// traceparent
+	r.Header.Set(HeaderTraceparent, "00-0AF7651916CD43DD8448EB211C80319C-b7ad6b7169203331-01")
+	traceID, spanID = RequestTrace(r)
+	assertEqual(t, "0af7651916cd43dd8448eb211c80319c", traceID)
+	assertEqual(t, "b7ad6b7169203331", spanID)
+
+	// traceparent takes precedence over b3
+	r.Header.Set(HeaderB3, "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1-05e3ac9a4f6e3b90")
+	traceID, _ = RequestTrace(r)
+	assertEqual(t, "0af7651916cd43dd8448eb211c80319c", traceID)
+
+	// Single b3 header
+	r.Header.Del(HeaderTraceparent)
+	traceID, spanID = RequestTrace(r)
+	assertEqual(t, "80f198ee56343ba864fe8b2a57d3eff7", traceID)
+	assertEqual(t, "e457b5a2e4d86bd1", spanID)
+
+	// Sampling-only b3 header is ignored in favor of the multiple headers, and 64-bit trace IDs are padded
+	r.Header.Set(HeaderB3, "1")
+	r.Header.Set(HeaderB3TraceID, "64fe8b2a57d3eff7")
+	r.Header.Set(HeaderB3SpanID, "E457B5A2E4D86BD1")
+	traceID, spanID = RequestTrace(r)
+	assertEqual(t, "000000000000000064fe8b2a57d3eff7", traceID)
+	assertEqual(t, "e457b5a2e4d86bd1", spanID)
+
+	// Malformed and all-zero IDs are ignored
+	r = httptest.NewRequest("GET", "/", nil)
+	r.Header.Set(HeaderB3, "00000000000000000000000000000000-xyz")
+	traceID, spanID = RequestTrace(r)
+	assertEqual(t, "", traceID)
+	assertEqual(t, "", spanID)
+}
+
+func TestErrors_WithRequestTrace(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set(HeaderTraceparent, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+	assertNil(t, WithRequestTrace(nil, r))
+
+	original := New("oops")
+	err := WithRequestTrace(original, r)
+	assertEqual(t, "0af7651916cd43dd8448eb211c80319c", TraceID(err))
+	assertEqual(t, "b7ad6b7169203331", SpanID(err))
+	assertEqual(t, "", TraceID(original))
+
+	// IDs already set are retained
+	err = WithRequestTrace(New("oops", "11111111111111111111111111111111"), r)
+	assertEqual(t, "11111111111111111111111111111111", TraceID(err))
+	assertEqual(t, "b7ad6b7169203331", SpanID(err))
+
+	// No headers
+	original = New("oops")
+	err = WithRequestTrace(original, httptest.NewRequest("GET", "/", nil))
+	assertEqual(t, original, err)
+}
+
+func TestErrors_ToTraceHeaders(t *testing.T) {
+	t.Parallel()
+
+	h := http.Header{}
+	ToTraceHeaders(h, New("oops", "0af7651916cd43dd8448eb211c80319c"))
+	assertEqual(t, "", h.Get(HeaderTraceparent))
+
+	ToTraceHeaders(h, New("oops", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"))
+	assertEqual(t, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00", h.Get(HeaderTraceparent))
+	assertEqual(t, "0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331", h.Get(HeaderB3))
+
+	// Round trip
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header = h
+	traceID, spanID := RequestTrace(r)
+	assertEqual(t, "0af7651916cd43dd8448eb211c80319c", traceID)
+	assertEqual(t, "b7ad6b7169203331", spanID)
+}
@@ -17,15 +17,22 @@ limitations under the License.
 package errors
 
 import (
+	"context"
 	"encoding/json"
 	stderrors "errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"maps"
+	"slices"
+	"strconv"
 	"strings"
 )
 
-const zeroTrace = "00000000000000000000000000000000"
+const (
+	zeroTrace = "00000000000000000000000000000000"
+	zeroSpan  = "0000000000000000"
+)
 
 // Ensure interfaces
 var (
@@ -36,13 +43,28 @@ var (
 	_ = json.Unmarshaler(&TracedError{})
 )
 
+// StackDepth is an argument of New or Trace that captures up to the indicated number of frames of the full stack.
+type StackDepth int
+
 // TracedError is a standard Go error augmented with a stack trace, status code and property bag.
 type TracedError struct {
 	Err        error
 	Stack      []*StackFrame
 	StatusCode int
 	Trace      string
+	// Span is the ID of the span in which the error occurred, within the trace
+	Span       string
 	Properties map[string]any
+	Suppressed []error
+	// Origin indicates whether the error originated locally or in an upstream dependency
+	Origin Origin
+	// RawPanicStack is the unfiltered output of debug.Stack at the time a panic was recovered.
+	// It is captured only in debug mode, for postmortems in case the filtered stack trace omits relevant frames.
+	// It is included in String but is not serialized.
+	RawPanicStack string
+
+	layer  *chainLayer // The own annotation of the message composed by New, apart from the errors it wraps
+	pooled bool        // Allocated from the pool and may be released
 }
 
 /*
@@ -77,60 +99,114 @@ An unnamed error is interpreted to be the original source of the error. The new
 
 	fmt.Errorf(errorMessage+": %w", originalError)
 
+The properties of the original error are carried over to the new error.
+Conflicts with properties of the new error are resolved per SetPropertyMergeStrategy, by default in favor of the new error.
+
 An unnamed integer is interpreted to be an HTTP status code to associate with the error. If the pattern is empty, the status text is set by default.
+If no status code is provided, it is inherited from the original error, or determined by the registered status mappers.
+
+An unnamed 32-character long hex string is interpreted to be a trace ID, and is normalized to lower case.
+An unnamed 16-character long hex string is interpreted to be the ID of the span in which the error occurred.
+An unnamed W3C traceparent, e.g. "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", sets both.
+All-zero IDs are ignored.
+
+A StackDepth captures up to the indicated number of frames of the full stack, rather than only the location of the caller.
+
+	New("unexpected state", errors.StackDepth(16))
+
+A PropertyGroup created by Group namespaces its properties under its name, merging with a group of the same name, if any.
+
+	New("failed to query", err, errors.Group("db", "query", q, "rows", n))
 
-An unnamed 32-character long hex string is interpreted to be a trace ID.
+A slog.Attr is added to the property bag, with group attributes becoming grouped properties.
+
+	New("failed to query", err, slog.String("query", q), slog.Group("conn", "host", host))
+
+An unnamed context.Context is not added to the property bag but is used by enrichments such as EnablePprofLabelCapture.
+The time remaining until the deadline of the context, if it has one, and the error of the context, if it is done,
+are attached in the "deadlineIn" and "ctxErr" properties, so that timeouts can be triaged from the error alone.
+
+	New("failed to process request", ctx)
 */
 func New(pattern string, args ...any) error {
 	pctArgs := strings.Count(pattern, `%`) - 2*strings.Count(pattern, `%%`)
 	pctArgs = min(pctArgs, len(args))
-	err := &TracedError{}
+	err := newTracedError()
+	var wrapped error
+	var own error                // The message of the pattern or status text, apart from the errors it wraps
+	var patternCauses []error    // The errors wrapped by the pattern with %w
+	var additionalCauses []error // The errors wrapped after the first error argument
+	var depth int
+	var ctx context.Context
+	var inherited map[string]any
 	if pattern != "" {
 		// Important: Trace expects that an empty pattern will not wrap followup error objects
 		err.Err = fmt.Errorf(pattern, args[:pctArgs]...)
+		own = err.Err
+		if strings.Contains(pattern, "%w") {
+			patternCauses = unwrapCauses(err.Err)
+		}
 	}
 	i := pctArgs
 	for i < len(args) {
-		if err.Properties == nil {
-			err.Properties = make(map[string]any, len(args)-pctArgs)
+		if err.Properties == nil && isPropertyArg(args[i]) {
+			// Preallocated for the remaining arguments, which are mostly key-value pairs
+			err.Properties = make(map[string]any, (len(args)-i+1)/2)
 		}
 		switch k := args[i].(type) {
 		case int:
 			err.StatusCode = k
 			if err.Err == nil {
 				err.Err = stderrors.New(statusText[k])
+				own = err.Err
 			}
 			i++
 		case error:
+			if wrapped == nil {
+				wrapped = k
+			} else {
+				additionalCauses = append(additionalCauses, k)
+			}
 			if err.Err == nil {
 				// Important: Trace expects that an empty pattern will not wrap followup error objects
 				err.Err = k
 			} else {
 				err.Err = fmt.Errorf("%w: %w", err.Err, k)
 			}
-			if tracedErr, ok := k.(*TracedError); ok {
+			if tracedErr := findTraced(k); tracedErr != nil {
 				if err.StatusCode == 0 {
 					err.StatusCode = tracedErr.StatusCode
 				}
 				if err.Trace == "" || err.Trace == zeroTrace {
 					err.Trace = tracedErr.Trace
+					if err.Span == "" || err.Span == zeroSpan {
+						err.Span = tracedErr.Span
+					}
+				}
+				if len(tracedErr.Properties) > 0 {
+					if inherited == nil {
+						inherited = make(map[string]any, len(tracedErr.Properties))
+					}
+					maps.Copy(inherited, tracedErr.Properties)
+				}
+				err.Stack = slices.Clip(tracedErr.Stack)
+				err.Suppressed = append(err.Suppressed, tracedErr.Suppressed...)
+				if err.RawPanicStack == "" {
+					err.RawPanicStack = tracedErr.RawPanicStack
+				}
+				if err.Origin == OriginLocal {
+					err.Origin = tracedErr.Origin
 				}
-				maps.Copy(err.Properties, tracedErr.Properties)
-				err.Stack = tracedErr.Stack
 			}
 			i++
 		case string:
-			isHex := func(s string) bool {
-				for i := range s {
-					c := s[i]
-					if (c < '0' || c > '9') && (c < 'a' || c > 'f') && (c < 'A' || c > 'F') {
-						return false
-					}
+			if traceID, spanID, ok := traceContextArg(k); ok {
+				if traceID != "" && traceID != zeroTrace {
+					err.Trace = traceID
+				}
+				if spanID != "" && spanID != zeroSpan {
+					err.Span = spanID
 				}
-				return true
-			}
-			if len(k) == 32 && isHex(k) {
-				err.Trace = k
 				i++
 			} else if i < len(args)-1 {
 				err.Properties[k] = args[i+1]
@@ -139,6 +215,18 @@ func New(pattern string, args ...any) error {
 				err.Properties[k] = ""
 				i++
 			}
+		case StackDepth:
+			depth = int(k)
+			i++
+		case context.Context:
+			ctx = k
+			i++
+		case PropertyGroup:
+			mergeGroup(err.Properties, k)
+			i++
+		case slog.Attr:
+			foldSlogAttr(err.Properties, k)
+			i++
 		default:
 			err.Properties["!BADKEY"] = k
 			i++
@@ -147,15 +235,179 @@ func New(pattern string, args ...any) error {
 	if err.Err == nil {
 		err.Err = stderrors.New("unspecified error")
 	}
+	if own != nil && (wrapped != nil || len(patternCauses) > 0) || len(additionalCauses) > 0 {
+		causes := patternCauses
+		if wrapped != nil {
+			causes = append(causes, wrapped)
+		}
+		causes = append(causes, additionalCauses...)
+		err.layer = newChainLayer(err.Err, own, pattern, args[:pctArgs], causes)
+	}
 	if err.StatusCode == 0 {
-		err.StatusCode = 500
+		err.StatusCode = mapStatusCode(wrapped)
+	}
+	if len(inherited) > 0 {
+		err.Properties = mergeProperties(inherited, err.Properties, loadSettings().mergeStrategy)
+	}
+	enrich(err, ctx)
+	if sampler := loadSettings().stackSampler; sampler != nil && err.StatusCode < 500 && !sampler() {
+		return err
+	}
+	site := "new"
+	if wrapped != nil {
+		site = "wrap"
+		if pattern == "" {
+			site = "trace"
+		}
+	}
+	n := len(err.Stack)
+	if depth > 0 {
+		return markSite(traceStack(err, 0, depth), n, site)
 	}
-	return traceCaller(err)
+	return markSite(traceCaller(err), n, site)
+}
+
+// isPropertyArg indicates if the argument of New adds to the property bag of the error.
+func isPropertyArg(arg any) bool {
+	switch k := arg.(type) {
+	case int, error, StackDepth, context.Context:
+		return false
+	case string:
+		_, _, ok := traceContextArg(k)
+		return !ok
+	default:
+		return true
+	}
+}
+
+/*
+Sentinel creates a new error that is intended to be declared at the package level and compared against with Is.
+The arguments behave like those of New, but no stack location is captured.
+A sentinel error is never modified. Trace, New, With and the like wrap it with a copy of its status code and properties.
+
+	var ErrNotFound = errors.Sentinel("not found", http.StatusNotFound, "code", "NOT_FOUND")
+*/
+func Sentinel(pattern string, args ...any) error {
+	err := New(pattern, args...).(*TracedError)
+	err.Stack = nil
+	// Sentinel errors are shared and must never be released
+	err.pooled = false
+	return err
+}
+
+/*
+With attaches properties, a status code or a trace ID to the error, without capturing another stack location.
+The arguments behave like those of New, except that errors and stack depths are not accepted.
+The original error is not modified. Rather, a traced error that wraps it is returned, so that Is and As continue to match it.
+
+	return errors.With(err, "userID", userID)
+*/
+func With(err error, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	tracedErr := deriveTraced(err)
+	for i := 0; i < len(args); i++ {
+		switch k := args[i].(type) {
+		case int:
+			tracedErr.StatusCode = k
+		case string:
+			if traceID, spanID, ok := traceContextArg(k); ok {
+				if traceID != "" && traceID != zeroTrace {
+					tracedErr.Trace = traceID
+				}
+				if spanID != "" && spanID != zeroSpan {
+					tracedErr.Span = spanID
+				}
+				continue
+			}
+			if tracedErr.Properties == nil {
+				tracedErr.Properties = map[string]any{}
+			}
+			if i < len(args)-1 {
+				tracedErr.Properties[k] = args[i+1]
+				i++
+			} else {
+				tracedErr.Properties[k] = ""
+			}
+		case PropertyGroup:
+			if tracedErr.Properties == nil {
+				tracedErr.Properties = map[string]any{}
+			}
+			mergeGroup(tracedErr.Properties, k)
+		case slog.Attr:
+			if tracedErr.Properties == nil {
+				tracedErr.Properties = map[string]any{}
+			}
+			foldSlogAttr(tracedErr.Properties, k)
+		default:
+			if tracedErr.Properties == nil {
+				tracedErr.Properties = map[string]any{}
+			}
+			tracedErr.Properties["!BADKEY"] = k
+		}
+	}
+	return tracedErr
+}
+
+// derive returns a new traced error that wraps the error and carries over its metadata,
+// so that the metadata can be modified without affecting the original error.
+func (e *TracedError) derive() *TracedError {
+	derived := newTracedError()
+	derived.Err = e
+	derived.Stack = slices.Clip(e.Stack)
+	derived.StatusCode = e.StatusCode
+	derived.Trace = e.Trace
+	derived.Span = e.Span
+	derived.Properties = maps.Clone(e.Properties)
+	derived.Suppressed = slices.Clip(e.Suppressed)
+	derived.Origin = e.Origin
+	derived.RawPanicStack = e.RawPanicStack
+	return derived
 }
 
 // Error returns the error string.
+// If the error ends up wrapping itself, the messages of the errors at the leaves of the error tree are returned.
+// The number of layers of the wrap chain is limited by the configured maximum chain depth.
 func (e *TracedError) Error() string {
-	return e.Err.Error()
+	// Layers of traced errors, such as those added by Trace, are descended without recursion,
+	// so that the cost of the message does not grow quadratically with the length of the chain.
+	// A cycle among the layers is detected by advancing a second pointer at half the pace
+	inner, slow := e, e
+	for hops := 0; ; hops++ {
+		next, ok := inner.Err.(*TracedError)
+		if !ok {
+			break
+		}
+		inner = next
+		if hops%2 == 1 {
+			slow = slow.Err.(*TracedError)
+		}
+		if inner == slow {
+			return cyclicMessage(e.Err)
+		}
+	}
+	// A cycle through other errors is detected once, at the first of the layers whose message is requested
+	if reaches(inner.Err, e) {
+		return cyclicMessage(e.Err)
+	}
+	msg := inner.Err.Error()
+	if maxDepth := loadSettings().maxChainDepth; maxDepth > 0 && strings.Count(msg, ": ") >= maxDepth {
+		return elideChain(msg, e.Err, maxDepth)
+	}
+	return msg
+}
+
+// cyclicMessage returns the messages of the errors at the leaves of an error tree that wraps itself.
+func cyclicMessage(err error) string {
+	var msgs []string
+	for _, leaf := range Flatten(err) {
+		msgs = append(msgs, leaf.Error())
+	}
+	if len(msgs) == 0 {
+		return "cyclic error"
+	}
+	return strings.Join(msgs, "\n")
 }
 
 // Unwrap returns the underlying error.
@@ -163,86 +415,220 @@ func (e *TracedError) Unwrap() error {
 	return e.Err
 }
 
-// String returns a human-friendly representation of the traced error.
+// String returns a human-friendly representation of the traced error, per the render mode set by SetRenderMode.
 func (e *TracedError) String() string {
+	return e.Render(loadSettings().renderMode)
+}
+
+// string returns a human-friendly representation of the traced error, skipping suppressed errors that were already visited.
+func (e *TracedError) string(visited *visitedSet) string {
+	visited.visit(e)
+	msg := e.Error()
 	var b strings.Builder
-	b.WriteString(e.Error())
+	// Sized to avoid growing the buffer in the common case
+	size := len(msg) + len(e.Trace) + len(e.Span) + 32*len(e.Properties) + 32
+	for _, stackFrame := range e.Stack {
+		size += len(stackFrame.Function) + len(stackFrame.File) + 16
+	}
+	b.Grow(size)
+	b.WriteString(msg)
 	if e.StatusCode != 0 && e.StatusCode != 500 {
 		b.WriteString("\nstatusCode=")
-		b.WriteString(fmt.Sprintf("%d", e.StatusCode))
+		b.WriteString(strconv.Itoa(e.StatusCode))
 	}
 	if e.Trace != "" && e.Trace != zeroTrace {
 		b.WriteString("\ntrace=")
 		b.WriteString(e.Trace)
 	}
-	for k, v := range e.Properties {
+	if e.Span != "" && e.Span != zeroSpan {
+		b.WriteString("\nspan=")
+		b.WriteString(e.Span)
+	}
+	if e.Origin != OriginLocal {
+		b.WriteString("\norigin=")
+		b.WriteString(e.Origin.String())
+	}
+	for k, v := range flatProperties(e.Properties) {
 		b.WriteString("\n")
 		b.WriteString(k)
 		b.WriteString("=")
-		b.WriteString(fmt.Sprintf("%v", v))
+		fmt.Fprintf(&b, "%v", v)
 	}
 	if len(e.Stack) > 0 {
 		b.WriteString("\n")
 	}
-	for _, stackFrame := range e.Stack {
+	debugMode := loadSettings().debugMode
+	for i, stackFrame := range e.Stack {
 		b.WriteString("\n")
-		b.WriteString(stackFrame.String())
+		if stackFrame.isBoundary() || stackFrame.Site != "" && i > 0 && !e.Stack[i-1].isBoundary() {
+			// Boundaries and segments separate the stack into visually distinct sections
+			b.WriteString("\n")
+		}
+		stackFrame.writeString(&b)
+		if debugMode {
+			b.WriteString(stackFrame.snippet())
+		}
+	}
+	if e.RawPanicStack != "" && debugMode {
+		b.WriteString("\n\nraw panic stack:\n  ")
+		b.WriteString(strings.ReplaceAll(strings.TrimRight(e.RawPanicStack, "\n"), "\n", "\n  "))
+	}
+	for _, suppressed := range e.Suppressed {
+		if suppressed == nil || !visited.visit(suppressed) {
+			continue
+		}
+		b.WriteString("\n\nsuppressed: ")
+		b.WriteString(strings.ReplaceAll(Convert(suppressed).string(visited), "\n", "\n  "))
 	}
 	return b.String()
 }
 
 // MarshalJSON marshals the error to JSON.
+// Property values that cannot be marshaled, such as channels, functions or NaN,
+// are converted to strings marked with a !BADVALUE prefix rather than failing the entire error.
 func (e *TracedError) MarshalJSON() ([]byte, error) {
+	var visited visitedSet
+	m := e.jsonMap(&visited)
+	b, err := json.Marshal(m)
+	if err != nil {
+		sanitizeJSONMap(m)
+		b, err = json.Marshal(m)
+	}
+	return b, err
+}
+
+// sanitizeJSONMap replaces values of the map, and of the maps of its suppressed errors, that cannot be marshaled to JSON.
+func sanitizeJSONMap(m map[string]any) {
+	for k, v := range m {
+		if suppressed, ok := v.([]map[string]any); ok && k == "suppressed" {
+			for _, s := range suppressed {
+				sanitizeJSONMap(s)
+			}
+			continue
+		}
+		if _, err := json.Marshal(v); err != nil {
+			m[k] = badPropertyValue(v)
+		}
+	}
+}
+
+// badPropertyValue returns the string representation of a property value that cannot be marshaled to JSON, marked as such.
+func badPropertyValue(v any) string {
+	return fmt.Sprintf("!BADVALUE(%T) %v", v, v)
+}
+
+// jsonMap returns the map to marshal to JSON, skipping suppressed errors that were already visited.
+func (e *TracedError) jsonMap(visited *visitedSet) map[string]any {
+	visited.visit(e)
+	e = e.limited()
+	cfg := loadJSONConfig()
 	m := map[string]any{}
-	if len(e.Properties) > 0 {
-		maps.Copy(m, e.Properties)
+	for k, v := range e.Properties {
+		if !cfg.isReservedField(k) {
+			m[k] = v
+		}
 	}
-	m["error"] = e.Err.Error()
+	m[cfg.MessageField] = e.Error()
 	if e.StatusCode != 0 {
-		m["statusCode"] = e.StatusCode
-	} else {
-		delete(m, "statusCode")
+		m[cfg.CodeField] = e.StatusCode
 	}
 	if e.Stack != nil {
 		m["stack"] = e.Stack
-	} else {
-		delete(m, "stack")
 	}
 	if e.Trace != "" && e.Trace != zeroTrace {
 		m["trace"] = e.Trace
-	} else {
-		delete(m, "trace")
 	}
-	return json.Marshal(m)
+	if e.Span != "" && e.Span != zeroSpan {
+		m["span"] = e.Span
+	}
+	if e.Origin != OriginLocal {
+		m["origin"] = e.Origin.String()
+	}
+	suppressed := make([]map[string]any, 0, len(e.Suppressed))
+	for _, s := range e.Suppressed {
+		if s != nil && visited.visit(s) {
+			suppressed = append(suppressed, Convert(s).jsonMap(visited))
+		}
+	}
+	if len(suppressed) > 0 {
+		m["suppressed"] = suppressed
+	}
+	return m
 }
 
 // UnmarshalJSON unmarshals the error from JSON.
 // Neither the type of the error nor any errors it wraps can be restored.
+// The input is treated as untrusted and is rejected if it exceeds limits on its size, nesting depth,
+// number of stack frames, number of properties or size of a property, or if its trace ID or span ID is invalid.
 func (e *TracedError) UnmarshalJSON(data []byte) error {
-	var j StreamedError
-	err := json.Unmarshal(data, &j)
+	err := checkUnmarshalInput(data, 0)
 	if err != nil {
 		return err
 	}
-	e.Err = stderrors.New(j.Error)
-	e.Stack = j.Stack
-	e.StatusCode = j.StatusCode
-	e.Trace = j.Trace
-
-	var m map[string]any
-	err = json.Unmarshal(data, &m)
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(data, &fields)
 	if err != nil {
 		return err
 	}
-	delete(m, "error")
-	delete(m, "statusCode")
-	delete(m, "stack")
-	delete(m, "trace")
-	if len(m) > 0 {
-		e.Properties = m
-	} else {
-		e.Properties = nil
+	cfg := loadJSONConfig()
+	var msg string
+	var statusCode int
+	var trace string
+	var span string
+	var origin string
+	var stack []*StackFrame
+	var suppressed []*TracedError
+	var properties map[string]any
+	for k, v := range fields {
+		switch k {
+		case cfg.MessageField:
+			err = json.Unmarshal(v, &msg)
+		case cfg.CodeField:
+			err = json.Unmarshal(v, &statusCode)
+		case "trace":
+			err = json.Unmarshal(v, &trace)
+		case "span":
+			err = json.Unmarshal(v, &span)
+		case "origin":
+			err = json.Unmarshal(v, &origin)
+		case "stack":
+			err = json.Unmarshal(v, &stack)
+		case "suppressed":
+			err = json.Unmarshal(v, &suppressed)
+		default:
+			if len(v) > maxUnmarshalPropertyLen {
+				return New("property '%s' of %d bytes exceeds limit of %d", truncateString(k, 64), len(v), maxUnmarshalPropertyLen)
+			}
+			var value any
+			err = json.Unmarshal(v, &value)
+			if properties == nil {
+				properties = map[string]any{}
+			}
+			properties[k] = value
+		}
+		if err != nil {
+			return err
+		}
+	}
+	unmarshaled := TracedError{
+		Err:        stderrors.New(msg),
+		Stack:      stack,
+		StatusCode: statusCode,
+		Trace:      trace,
+		Span:       span,
+		Origin:     parseOrigin(origin),
+		Properties: properties,
+	}
+	for _, s := range suppressed {
+		if s != nil {
+			unmarshaled.Suppressed = append(unmarshaled.Suppressed, s)
+		}
 	}
+	err = checkUnmarshaled(&unmarshaled)
+	if err != nil {
+		return err
+	}
+	*e = unmarshaled
 	return nil
 }
 
@@ -262,10 +648,13 @@ func (e *TracedError) Format(s fmt.State, verb rune) {
 
 // StreamedError is the schema used to marshal and unmarshal the traced error.
 type StreamedError struct {
-	Error      string        `json:"error" jsonschema:"example=message"`
-	StatusCode int           `json:"statusCode,omitzero"`
-	Trace      string        `json:"trace,omitzero"`
-	Stack      []*StackFrame `json:"stack,omitzero"`
+	Error      string           `json:"error" jsonschema:"example=message"`
+	StatusCode int              `json:"statusCode,omitzero"`
+	Trace      string           `json:"trace,omitzero"`
+	Span       string           `json:"span,omitzero"`
+	Origin     string           `json:"origin,omitzero"`
+	Stack      []*StackFrame    `json:"stack,omitzero"`
+	Suppressed []*StreamedError `json:"suppressed,omitzero"`
 }
 
 // StackFrame is a single stack location.
@@ -273,9 +662,69 @@ type StackFrame struct {
 	Function string `json:"func"`
 	File     string `json:"file"`
 	Line     int    `json:"line"`
+	// Repeat is the number of consecutive times the location was traced, if more than once
+	Repeat int `json:"repeat,omitzero"`
+	// Site labels the first of the frames captured by a call to New, Trace or the like, e.g. "new", "trace" or "wrap",
+	// and so starts a new segment of the stack
+	Site string `json:"site,omitzero"`
+
+	owner *TracedError // The pooled error that captured the frame and releases it
 }
 
 // String returns a string representation of the stack frame.
 func (t *StackFrame) String() string {
-	return fmt.Sprintf("- %s\n  %s:%d", t.Function, t.File, t.Line)
+	var b strings.Builder
+	t.writeString(&b)
+	return b.String()
+}
+
+// writeString writes the string representation of the stack frame to the builder.
+func (t *StackFrame) writeString(b *strings.Builder) {
+	b.WriteString("- ")
+	b.WriteString(t.Function)
+	if t.isPseudo() {
+		return
+	}
+	if t.Repeat > 1 {
+		b.WriteString(" (x")
+		b.WriteString(strconv.Itoa(t.Repeat))
+		b.WriteString(")")
+	}
+	if t.Site != "" {
+		b.WriteString(" [")
+		b.WriteString(t.Site)
+		b.WriteString("]")
+	}
+	b.WriteString("\n  ")
+	b.WriteString(t.File)
+	b.WriteString(":")
+	b.WriteString(strconv.Itoa(t.Line))
+}
+
+// sameLocation indicates if the two frames point to the same location in the code.
+func (t *StackFrame) sameLocation(other *StackFrame) bool {
+	return t.Line == other.Line && t.File == other.File && t.Function == other.Function && !t.isPseudo()
+}
+
+// isPseudo indicates if the frame does not point to a location in the code,
+// but rather stands for omitted frames or marks a boundary in the stack.
+func (t *StackFrame) isPseudo() bool {
+	return t.isElision() || t.isBoundary()
+}
+
+// isBoundary indicates if the frame is a pseudo frame that marks a boundary in the stack, such as an async boundary.
+func (t *StackFrame) isBoundary() bool {
+	return t.File == "" && t.Line == 0 && strings.HasPrefix(t.Function, "— ")
+}
+
+// isElision indicates if the frame is a pseudo frame that stands for omitted frames.
+func (t *StackFrame) isElision() bool {
+	return t.File == "" && t.Line == 0 && strings.HasPrefix(t.Function, "... ")
+}
+
+// elided returns the number of frames omitted by an elision frame.
+func (t *StackFrame) elided() int {
+	var n int
+	fmt.Sscanf(t.Function, "... %d more", &n)
+	return n
 }
@@ -0,0 +1,92 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+)
+
+// isTraceID indicates if the string is a 32-character long hex string.
+func isTraceID(s string) bool {
+	if len(s) != 32 {
+		return false
+	}
+	return isHex(s)
+}
+
+// isHex indicates if the string consists of hex digits only.
+func isHex(s string) bool {
+	for i := range s {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') && (c < 'A' || c > 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// isTraceparent indicates if the string is a W3C traceparent, e.g. "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01".
+func isTraceparent(s string) bool {
+	return len(s) == 55 && s[2] == '-' && s[35] == '-' && s[52] == '-' &&
+		isHex(s[:2]) && !strings.EqualFold(s[:2], "ff") && isHex(s[3:35]) && isHex(s[36:52]) && isHex(s[53:])
+}
+
+// isSpanID indicates if the string is a 16-character long hex string.
+func isSpanID(s string) bool {
+	if len(s) != 16 {
+		return false
+	}
+	return isHex(s)
+}
+
+// traceContextArg returns the normalized trace ID and span ID of an argument of New or With,
+// if the argument is either a trace ID, a span ID or a W3C traceparent.
+func traceContextArg(s string) (traceID string, spanID string, ok bool) {
+	switch {
+	case isTraceID(s):
+		return strings.ToLower(s), "", true
+	case isSpanID(s):
+		return "", strings.ToLower(s), true
+	case isTraceparent(s):
+		return strings.ToLower(s[3:35]), strings.ToLower(s[36:52]), true
+	default:
+		return "", "", false
+	}
+}
+
+// IsValidTraceID indicates if the string is a valid W3C trace ID: 32 hex digits that are not all zeros.
+// Upper case hex digits are accepted, but New and With normalize trace IDs to lower case.
+func IsValidTraceID(s string) bool {
+	return isTraceID(s) && s != zeroTrace
+}
+
+/*
+GenerateTraceID returns a new random trace ID that is compatible with the W3C Trace Context.
+
+	err := errors.New("oops", errors.GenerateTraceID())
+*/
+func GenerateTraceID() string {
+	var b [16]byte
+	for {
+		rand.Read(b[:])
+		if b != [16]byte{} {
+			return hex.EncodeToString(b[:])
+		}
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestErrors_IsValidTraceID(t *testing.T) {
+	t.Parallel()
+
+	assertTrue(t, IsValidTraceID("0af7651916cd43dd8448eb211c80319c"))
+	assertTrue(t, IsValidTraceID("0AF7651916CD43DD8448EB211C80319C"))
+	assertTrue(t, !IsValidTraceID("00000000000000000000000000000000"))
+	assertTrue(t, !IsValidTraceID("0af7651916cd43dd8448eb211c80319"))
+	assertTrue(t, !IsValidTraceID("0af7651916cd43dd8448eb211c80319g"))
+	assertTrue(t, !IsValidTraceID(""))
+}
+
+func TestErrors_GenerateTraceID(t *testing.T) {
+	t.Parallel()
+
+	id1 := GenerateTraceID()
+	id2 := GenerateTraceID()
+	assertTrue(t, IsValidTraceID(id1))
+	assertTrue(t, IsValidTraceID(id2))
+	assertNotEqual(t, id1, id2)
+	assertEqual(t, strings.ToLower(id1), id1)
+}
+
+func TestErrors_TraceIDNormalization(t *testing.T) {
+	t.Parallel()
+
+	err := New("oops", "0AF7651916CD43DD8448EB211C80319C")
+	assertEqual(t, "0af7651916cd43dd8448eb211c80319c", TraceID(err))
+
+	err = Trace(err, "B7AD6B7169203331B7AD6B7169203331")
+	assertEqual(t, "b7ad6b7169203331b7ad6b7169203331", TraceID(err))
+
+	// The all-zero trace is rejected
+	err = New("oops", "00000000000000000000000000000000")
+	assertEqual(t, "", TraceID(err))
+	assertEqual(t, 0, len(err.(*TracedError).Properties))
+}
+
+func TestErrors_Traceparent(t *testing.T) {
+	t.Parallel()
+
+	err := New("oops", "00-0AF7651916CD43DD8448EB211C80319C-b7ad6b7169203331-01", "k", "v")
+	assertEqual(t, "0af7651916cd43dd8448eb211c80319c", TraceID(err))
+	assertEqual(t, 1, len(err.(*TracedError).Properties))
+
+	err = Trace(err, "00-b7ad6b7169203331b7ad6b7169203331-b7ad6b7169203331-00")
+	assertEqual(t, "b7ad6b7169203331b7ad6b7169203331", TraceID(err))
+
+	// Invalid version
+	err = New("oops", "ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", "v")
+	assertEqual(t, "", TraceID(err))
+	assertEqual(t, "v", err.(*TracedError).Properties["ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"])
+}
+
+func TestErrors_SpanID(t *testing.T) {
+	t.Parallel()
+
+	err := New("oops", "B7AD6B7169203331", "0af7651916cd43dd8448eb211c80319c")
+	assertEqual(t, "b7ad6b7169203331", SpanID(err))
+	assertEqual(t, "0af7651916cd43dd8448eb211c80319c", TraceID(err))
+	assertContains(t, Convert(err).String(), "span=b7ad6b7169203331")
+
+	// Traceparent sets both
+	err = New("oops", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	assertEqual(t, "b7ad6b7169203331", SpanID(err))
+
+	// Inherited along with the trace ID
+	wrapped := New("wrapped", err)
+	assertEqual(t, "b7ad6b7169203331", SpanID(wrapped))
+	assertEqual(t, "b7ad6b7169203331", SpanID(Trace(err)))
+	assertEqual(t, "1111111111111111", SpanID(Trace(err, "1111111111111111")))
+
+	// The all-zero span is rejected
+	err = New("oops", "0000000000000000")
+	assertEqual(t, "", SpanID(err))
+	assertEqual(t, 0, len(err.(*TracedError).Properties))
+}
+
+func TestErrors_SpanIDPropagation(t *testing.T) {
+	t.Parallel()
+
+	err := New("oops", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+	// JSON
+	b, jsonErr := json.Marshal(err)
+	assertNil(t, jsonErr)
+	assertContains(t, string(b), `"span":"b7ad6b7169203331"`)
+	var unmarshaled TracedError
+	assertNil(t, json.Unmarshal(b, &unmarshaled))
+	assertEqual(t, "b7ad6b7169203331", unmarshaled.Span)
+	assertError(t, json.Unmarshal([]byte(`{"error":"oops","span":"not-a-span"}`), &unmarshaled))
+
+	// Headers
+	h := http.Header{}
+	ToHeader(h, err)
+	assertEqual(t, "b7ad6b7169203331", h.Get(HeaderErrorSpan))
+	assertEqual(t, "b7ad6b7169203331", SpanID(FromHeader(h)))
+
+	w := httptest.NewRecorder()
+	WriteHTTP(w, err)
+	assertEqual(t, "b7ad6b7169203331", w.Header().Get(HeaderErrorSpan))
+
+	// String
+	parsed, parseErr := ParseString(Convert(err).String())
+	assertNil(t, parseErr)
+	assertEqual(t, "b7ad6b7169203331", SpanID(parsed))
+}
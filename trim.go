@@ -0,0 +1,97 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"path"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	mainModulePath = sync.OnceValue(func() string {
+		info, ok := debug.ReadBuildInfo()
+		if !ok || info.Main.Path == "command-line-arguments" {
+			return ""
+		}
+		return info.Main.Path
+	})
+	// mainModuleRoot is the location of the main module's root, learned from stack frames
+	mainModuleRoot atomic.Pointer[string]
+)
+
+// trimFile trims the file path of a stack frame per the package settings.
+// The fully-qualified function name is used to determine the package of the file.
+func trimFile(file string, qualifiedFunction string) string {
+	s := loadSettings()
+	for _, prefix := range s.stackFilePrefixTrim {
+		if strings.HasPrefix(file, prefix) {
+			return file[len(prefix):]
+		}
+	}
+	if s.stackFileAutoTrim {
+		return autoTrimFile(file, qualifiedFunction, mainModulePath())
+	}
+	return file
+}
+
+// autoTrimFile makes the file path relative to the root of the main module, the module cache, or the Go root.
+func autoTrimFile(file string, qualifiedFunction string, modulePath string) string {
+	pkgPath := packagePath(qualifiedFunction)
+	dir, base := path.Split(file)
+	dir = strings.TrimSuffix(dir, "/")
+	// Files of the main module
+	if modulePath != "" && (pkgPath == modulePath || strings.HasPrefix(pkgPath, modulePath+"/")) {
+		rel := strings.TrimPrefix(strings.TrimPrefix(pkgPath, modulePath), "/")
+		if rel == "" {
+			mainModuleRoot.CompareAndSwap(nil, &dir)
+			return base
+		}
+		if root, ok := strings.CutSuffix(dir, "/"+rel); ok {
+			mainModuleRoot.CompareAndSwap(nil, &root)
+		}
+		return rel + "/" + base
+	}
+	if root := mainModuleRoot.Load(); root != nil && strings.HasPrefix(file, *root+"/") {
+		return file[len(*root)+1:]
+	}
+	// Files in the module cache
+	if p := strings.Index(file, "/pkg/mod/"); p >= 0 {
+		return file[p+len("/pkg/mod/"):]
+	}
+	// Files of the standard library or in a GOPATH
+	if pkgPath != "" && pkgPath != "main" && (dir == pkgPath || strings.HasSuffix(dir, "/"+pkgPath)) {
+		return pkgPath + "/" + base
+	}
+	return file
+}
+
+// packagePath extracts the package path from a fully-qualified function name
+// such as github.com/microbus-io/errors.(*TracedError).Error
+func packagePath(qualifiedFunction string) string {
+	if p := strings.Index(qualifiedFunction, "["); p >= 0 {
+		qualifiedFunction = qualifiedFunction[:p]
+	}
+	slash := strings.LastIndex(qualifiedFunction, "/")
+	dot := strings.Index(qualifiedFunction[slash+1:], ".")
+	if dot < 0 {
+		return ""
+	}
+	return qualifiedFunction[:slash+1+dot]
+}
@@ -0,0 +1,62 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"testing"
+)
+
+func TestErrors_PackagePath(t *testing.T) {
+	t.Parallel()
+
+	assertEqual(t, "github.com/microbus-io/errors", packagePath("github.com/microbus-io/errors.(*TracedError).Error"))
+	assertEqual(t, "github.com/microbus-io/errors", packagePath("github.com/microbus-io/errors.New"))
+	assertEqual(t, "net/http", packagePath("net/http.(*conn).serve"))
+	assertEqual(t, "main", packagePath("main.main"))
+	assertEqual(t, "github.com/microbus-io/errors", packagePath("github.com/microbus-io/errors.Must[go.shape.struct { example.com/x.Y }]"))
+	assertEqual(t, "", packagePath("?"))
+}
+
+func TestErrors_AutoTrimFile(t *testing.T) {
+	t.Parallel()
+
+	mod := "github.com/my-org/my-app"
+
+	// Main module
+	assertEqual(t, "service/users/create.go", autoTrimFile("/home/builder/my-app/service/users/create.go", mod+"/service/users.(*Service).Create", mod))
+	assertEqual(t, "service/users/create.go", autoTrimFile(mod+"/service/users/create.go", mod+"/service/users.(*Service).Create", mod))
+	assertEqual(t, "root.go", autoTrimFile("/home/builder/my-app/root.go", mod+".Root", mod))
+
+	// Module cache
+	assertEqual(t, "github.com/dep/pkg@v1.2.3/file.go", autoTrimFile("/root/go/pkg/mod/github.com/dep/pkg@v1.2.3/file.go", "github.com/dep/pkg.Func", mod))
+
+	// Standard library
+	assertEqual(t, "net/http/server.go", autoTrimFile("/usr/local/go/src/net/http/server.go", "net/http.(*conn).serve", mod))
+
+	// Unknown
+	assertEqual(t, "/elsewhere/file.go", autoTrimFile("/elsewhere/file.go", "example.com/other.Func", mod))
+}
+
+func TestErrors_TrimFilePrefix(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	SetStackFilePrefixTrim("/home/", "/home/builder/")
+	defer SetStackFilePrefixTrim()
+
+	assertEqual(t, "my-app/main.go", trimFile("/home/builder/my-app/main.go", "main.main"))
+	assertEqual(t, "other/main.go", trimFile("/home/other/main.go", "main.main"))
+	assertEqual(t, "/usr/main.go", trimFile("/usr/main.go", "main.main"))
+}
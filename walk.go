@@ -0,0 +1,145 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"reflect"
+)
+
+/*
+Walk traverses the error tree depth-first, visiting each error before the errors it wraps.
+Errors that wrap multiple errors via Unwrap() []error are traversed in the order returned.
+Each error is visited at most once, so the traversal terminates even if an error ends up wrapping itself.
+Returning false from the visitor stops the traversal.
+
+	errors.Walk(err, func(e error) bool {
+		log.Print(e)
+		return true
+	})
+*/
+func Walk(err error, visitor func(error) bool) {
+	walkDepth(err, func(e error, depth int) bool {
+		return visitor(e)
+	})
+}
+
+// walkDepth traverses the error tree like Walk, also providing the depth of each error in the tree.
+func walkDepth(err error, visitor func(e error, depth int) bool) {
+	var visited visitedSet
+	var walk func(e error, depth int) bool
+	walk = func(e error, depth int) bool {
+		if e == nil {
+			return true
+		}
+		if !visited.visit(e) {
+			return true
+		}
+		if !visitor(e, depth) {
+			return false
+		}
+		switch x := e.(type) {
+		case interface{ Unwrap() error }:
+			return walk(x.Unwrap(), depth+1)
+		case interface{ Unwrap() []error }:
+			for _, e := range x.Unwrap() {
+				if !walk(e, depth+1) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	walk(err, 0)
+}
+
+// visitedSet tracks the errors visited during a traversal of the error tree.
+// Only errors that are pointers are tracked because a cycle must pass through a pointer.
+type visitedSet map[uintptr]struct{}
+
+// visit marks the error as visited, returning false if it was already visited.
+func (v *visitedSet) visit(err error) bool {
+	val := reflect.ValueOf(err)
+	if val.Kind() != reflect.Pointer {
+		return true
+	}
+	p := val.Pointer()
+	if _, ok := (*v)[p]; ok {
+		return false
+	}
+	if *v == nil {
+		*v = visitedSet{}
+	}
+	(*v)[p] = struct{}{}
+	return true
+}
+
+// reaches determines if the target error is reachable from the error tree, indicating a cycle.
+func reaches(err error, target *TracedError) bool {
+	// Fast path for linear chains
+	for hops := 0; hops < 16; hops++ {
+		if err == nil {
+			return false
+		}
+		if tracedErr, ok := err.(*TracedError); ok && tracedErr == target {
+			return true
+		}
+		u, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			if _, ok := err.(interface{ Unwrap() []error }); !ok {
+				return false
+			}
+			break
+		}
+		err = u.Unwrap()
+	}
+	found := false
+	Walk(err, func(e error) bool {
+		if tracedErr, ok := e.(*TracedError); ok && tracedErr == target {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// Flatten returns the leaves of the error tree, which are the errors that do not wrap other errors.
+// Leaves are returned in the order they are visited by Walk.
+func Flatten(err error) []error {
+	var leaves []error
+	Walk(err, func(e error) bool {
+		if isLeaf(e) {
+			leaves = append(leaves, e)
+		}
+		return true
+	})
+	return leaves
+}
+
+// isLeaf returns true if the error does not wrap other errors.
+func isLeaf(err error) bool {
+	switch x := err.(type) {
+	case interface{ Unwrap() error }:
+		return x.Unwrap() == nil
+	case interface{ Unwrap() []error }:
+		for _, e := range x.Unwrap() {
+			if e != nil {
+				return false
+			}
+		}
+	}
+	return true
+}
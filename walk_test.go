@@ -0,0 +1,153 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestErrors_Walk(t *testing.T) {
+	t.Parallel()
+
+	e1 := stderrors.New("E1")
+	e2 := stderrors.New("E2")
+	e3 := stderrors.New("E3")
+	wrapped := fmt.Errorf("wrapped: %w", e2)
+	joined := stderrors.Join(e1, wrapped, e3)
+	err := New("top", joined)
+
+	var visited []string
+	Walk(err, func(e error) bool {
+		visited = append(visited, e.Error())
+		return true
+	})
+	// New wraps the pattern and the joined error with fmt.Errorf
+	assertEqual(t, 8, len(visited))
+	assertEqual(t, err.Error(), visited[0])
+	assertEqual(t, "E1", visited[4])
+	assertEqual(t, "wrapped: E2", visited[5])
+	assertEqual(t, "E2", visited[6])
+	assertEqual(t, "E3", visited[7])
+
+	// Stop early
+	var count int
+	Walk(err, func(e error) bool {
+		count++
+		return e != e1
+	})
+	assertEqual(t, 5, count)
+
+	// Nil
+	Walk(nil, func(e error) bool {
+		t.Error("unexpected visit")
+		return true
+	})
+}
+
+func TestErrors_Flatten(t *testing.T) {
+	t.Parallel()
+
+	e1 := stderrors.New("E1")
+	e2 := stderrors.New("E2")
+	e3 := stderrors.New("E3")
+	err := Trace(stderrors.Join(e1, fmt.Errorf("wrapped: %w", e2), e3))
+	leaves := Flatten(err)
+	assertEqual(t, []error{e1, e2, e3}, leaves)
+
+	assertEqual(t, []error{e1}, Flatten(e1))
+	assertEqual(t, 0, len(Flatten(nil)))
+}
+
+func TestErrors_Cycles(t *testing.T) {
+	t.Parallel()
+
+	// Directly wrapping itself
+	direct := Convert(New("direct"))
+	direct.Err = direct
+	assertEqual(t, "cyclic error", direct.Error())
+	assertContains(t, direct.String(), "cyclic error")
+	_, err := direct.MarshalJSON()
+	assertNil(t, err)
+	count := 0
+	Walk(direct, func(e error) bool {
+		count++
+		return true
+	})
+	assertEqual(t, 1, count)
+	assertEqual(t, 0, len(Flatten(direct)))
+
+	// Wrapping itself via a join
+	leaf := stderrors.New("leaf")
+	viaJoin := Convert(New("via join"))
+	viaJoin.Err = stderrors.Join(leaf, viaJoin)
+	assertEqual(t, "leaf", viaJoin.Error())
+	assertEqual(t, []error{leaf}, Flatten(viaJoin))
+	_, err = viaJoin.MarshalJSON()
+	assertNil(t, err)
+	tracedErr, ok := AsTraced(viaJoin)
+	assertTrue(t, ok)
+	assertEqual(t, viaJoin, tracedErr)
+	assertEqual(t, 500, StatusCode(fmt.Errorf("wrapped: %w", viaJoin)))
+
+	// Indirect cycle through several layers
+	a := Convert(New("a"))
+	b := Convert(New("b", a))
+	c := Convert(New("c", b))
+	a.Err = fmt.Errorf("a: %w", c)
+	assertNotEqual(t, "", a.Error())
+	assertNotEqual(t, "", a.String())
+	_, err = c.MarshalJSON()
+	assertNil(t, err)
+
+	// Cycle among layers of traced errors
+	layer := Convert(New("layer"))
+	traced := Convert(Trace(Trace(layer)))
+	layer.Err = traced
+	assertEqual(t, "cyclic error", traced.Error())
+	assertEqual(t, "cyclic error", layer.Error())
+
+	// Cycle back to an intermediate layer via a join
+	mid := Convert(New("mid"))
+	top := Convert(Trace(mid))
+	mid.Err = stderrors.Join(leaf, Trace(mid))
+	assertContains(t, top.Error(), "leaf")
+	assertEqual(t, "leaf", mid.Error())
+
+	// Suppressing itself
+	self := Convert(New("self"))
+	self.Suppressed = append(self.Suppressed, self)
+	assertEqual(t, 1, strings.Count(self.String(), "self"))
+	b1, err := self.MarshalJSON()
+	assertNil(t, err)
+	assertEqual(t, 1, strings.Count(string(b1), "self"))
+
+	// Suppressing each other
+	x := Convert(New("x"))
+	y := Convert(New("y"))
+	x.Suppressed = append(x.Suppressed, y)
+	y.Suppressed = append(y.Suppressed, x)
+	assertEqual(t, 1, strings.Count(x.String(), "suppressed: "))
+	_, err = x.MarshalJSON()
+	assertNil(t, err)
+
+	// Visiting the same error twice in a join
+	dup := stderrors.New("dup")
+	assertEqual(t, []error{dup}, Flatten(stderrors.Join(dup, dup)))
+}
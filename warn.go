@@ -0,0 +1,123 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
+// warningsContextKey is the key of the warnings collector in the context.
+type warningsContextKey struct{}
+
+// warnings collects the warnings of an operation.
+type warnings struct {
+	mux  sync.Mutex
+	list []error
+}
+
+// ContextWithWarnings returns a context that collects the warnings issued by Warn,
+// for operations that complete successfully but with warnings.
+func ContextWithWarnings(ctx context.Context) context.Context {
+	return context.WithValue(ctx, warningsContextKey{}, &warnings{})
+}
+
+/*
+Warn issues a non-fatal notice of an operation that succeeded only partially, and collects it in the context,
+if the context was prepared by ContextWithWarnings.
+The message and arguments are interpreted as by New, and the warning is enriched by the context.
+Unlike New, the status code of a warning defaults to 200 because the operation did not fail,
+but a status code can be attached explicitly.
+The warning is returned so that it can be logged or inspected.
+
+	ctx = errors.ContextWithWarnings(ctx)
+	for _, row := range rows {
+		if row.Invalid() {
+			errors.Warn(ctx, "skipped invalid row %d", row.ID, "reason", row.Reason)
+			continue
+		}
+		...
+	}
+	warnings := errors.Warnings(ctx)
+*/
+func Warn(ctx context.Context, msg string, args ...any) error {
+	pctArgs := strings.Count(msg, `%`) - 2*strings.Count(msg, `%%`)
+	pctArgs = min(max(pctArgs, 0), len(args))
+	warnArgs := make([]any, 0, len(args)+2)
+	warnArgs = append(warnArgs, args[:pctArgs]...)
+	warnArgs = append(warnArgs, 200)
+	if ctx != nil {
+		warnArgs = append(warnArgs, ctx)
+	}
+	warnArgs = append(warnArgs, args[pctArgs:]...)
+	warning := New(msg, warnArgs...)
+	if ctx != nil {
+		if w, ok := ctx.Value(warningsContextKey{}).(*warnings); ok {
+			w.mux.Lock()
+			w.list = append(w.list, warning)
+			w.mux.Unlock()
+		}
+	}
+	return warning
+}
+
+// Warnings returns the warnings issued by Warn and collected in the context, in order.
+// It returns nil if there are none, or if the context was not prepared by ContextWithWarnings.
+// Join can be used to combine the warnings into a single error.
+func Warnings(ctx context.Context) []error {
+	if ctx == nil {
+		return nil
+	}
+	w, ok := ctx.Value(warningsContextKey{}).(*warnings)
+	if !ok {
+		return nil
+	}
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	if len(w.list) == 0 {
+		return nil
+	}
+	return append([]error(nil), w.list...)
+}
+
+/*
+AttachWarnings adds the warnings collected in the context to the "warnings" field of a response envelope.
+The warnings are included as WriteHTTPCtx would include errors, per the tenant of the context, the mask policy,
+the debug mode and the render mode.
+The envelope is not modified if there are no warnings.
+
+	body := map[string]any{"result": result}
+	errors.AttachWarnings(ctx, body)
+	json.NewEncoder(w).Encode(body)
+*/
+func AttachWarnings(ctx context.Context, envelope map[string]any) {
+	collected := Warnings(ctx)
+	if len(collected) == 0 || envelope == nil {
+		return
+	}
+	list := make([]json.RawMessage, 0, len(collected))
+	for _, warning := range collected {
+		b, err := json.Marshal(responseError(applyMaskPolicy(Convert(forRequestTenant(ctx, warning)))))
+		if err != nil {
+			continue
+		}
+		list = append(list, b)
+	}
+	envelope["warnings"] = list
+}
@@ -0,0 +1,79 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestErrors_Warn(t *testing.T) {
+	t.Parallel()
+
+	ctx := ContextWithWarnings(context.Background())
+	assertNil(t, Warnings(ctx))
+
+	w1 := Warn(ctx, "skipped row %d", 5, "reason", "invalid")
+	assertEqual(t, "skipped row 5", w1.Error())
+	assertEqual(t, 200, StatusCode(w1))
+	assertEqual(t, "invalid", w1.(*TracedError).Properties["reason"])
+	assertEqual(t, 1, len(w1.(*TracedError).Properties))
+	assertEqual(t, "errors.TestErrors_Warn", w1.(*TracedError).Stack[0].Function)
+
+	w2 := Warn(ctx, "quota nearly exhausted", 429)
+	assertEqual(t, 429, StatusCode(w2))
+
+	warnings := Warnings(ctx)
+	assertEqual(t, 2, len(warnings))
+	assertEqual(t, w1, warnings[0])
+	assertEqual(t, w2, warnings[1])
+	assertContains(t, Join(warnings...).Error(), "quota nearly exhausted")
+
+	// Not collected without a collector
+	w3 := Warn(context.Background(), "not collected")
+	assertEqual(t, "not collected", w3.Error())
+	assertNil(t, Warnings(context.Background()))
+	assertEqual(t, 2, len(Warnings(ctx)))
+}
+
+func TestErrors_AttachWarnings(t *testing.T) {
+	t.Parallel()
+
+	ctx := ContextWithWarnings(context.Background())
+	envelope := map[string]any{"result": 1}
+	AttachWarnings(ctx, envelope)
+	_, ok := envelope["warnings"]
+	assertTrue(t, !ok)
+
+	Warn(ctx, "partial result", "missing", 3)
+	AttachWarnings(ctx, envelope)
+	b, err := json.Marshal(envelope)
+	assertNil(t, err)
+	var decoded struct {
+		Result   int              `json:"result"`
+		Warnings []map[string]any `json:"warnings"`
+	}
+	assertNil(t, json.Unmarshal(b, &decoded))
+	assertEqual(t, 1, decoded.Result)
+	assertEqual(t, 1, len(decoded.Warnings))
+	assertEqual(t, "partial result", decoded.Warnings[0]["error"])
+	assertEqual(t, float64(200), decoded.Warnings[0]["statusCode"])
+	assertEqual(t, float64(3), decoded.Warnings[0]["missing"])
+	_, hasStack := decoded.Warnings[0]["stack"]
+	assertTrue(t, !hasStack)
+}
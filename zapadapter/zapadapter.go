@@ -0,0 +1,154 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package zapadapter logs traced errors as structured zap fields.
+
+	logger.Error("Request failed", zapadapter.Field(err))
+*/
+package zapadapter
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/microbus-io/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Option customizes the rendering of the error.
+type Option func(*options)
+
+type options struct {
+	stackString bool
+}
+
+// WithStackString renders the stack as a single multi-line string rather than as an array of frame objects.
+func WithStackString() Option {
+	return func(o *options) {
+		o.stackString = true
+	}
+}
+
+// Field returns a zap field named "error" with the message, status code, trace ID, properties and stack of the error.
+// A nil error results in a field that is skipped.
+func Field(err error, opts ...Option) zap.Field {
+	return NamedField("error", err, opts...)
+}
+
+// NamedField returns a zap field with the message, status code, trace ID, properties and stack of the error.
+// A nil error results in a field that is skipped.
+func NamedField(key string, err error, opts ...Option) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+	return zap.Object(key, Marshaler(err, opts...))
+}
+
+// Marshaler returns a zap object marshaler of the error, with the scrub rules set by errors.SetScrubRules applied.
+func Marshaler(err error, opts ...Option) zapcore.ObjectMarshaler {
+	m := &marshaler{
+		err: errors.Scrub(err),
+	}
+	for _, opt := range opts {
+		opt(&m.opts)
+	}
+	return m
+}
+
+// marshaler marshals a traced error to a zap object.
+type marshaler struct {
+	err  *errors.TracedError
+	opts options
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (m *marshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	e := m.err
+	enc.AddString("msg", e.Error())
+	if e.StatusCode != 0 {
+		enc.AddInt("statusCode", e.StatusCode)
+	}
+	if strings.Trim(e.Trace, "0") != "" {
+		enc.AddString("trace", e.Trace)
+	}
+	if strings.Trim(e.Span, "0") != "" {
+		enc.AddString("span", e.Span)
+	}
+	if len(e.Properties) > 0 {
+		err := enc.AddObject("properties", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			for _, k := range slices.Sorted(maps.Keys(e.Properties)) {
+				err := enc.AddReflected(k, e.Properties[k])
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}))
+		if err != nil {
+			return err
+		}
+	}
+	if len(e.Stack) > 0 {
+		if m.opts.stackString {
+			var b strings.Builder
+			for i, frame := range e.Stack {
+				if i > 0 {
+					b.WriteString("\n")
+				}
+				b.WriteString(frame.String())
+			}
+			enc.AddString("stack", b.String())
+		} else {
+			err := enc.AddArray("stack", zapcore.ArrayMarshalerFunc(func(arr zapcore.ArrayEncoder) error {
+				for _, frame := range e.Stack {
+					err := arr.AppendObject(zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+						enc.AddString("func", frame.Function)
+						enc.AddString("file", frame.File)
+						enc.AddInt("line", frame.Line)
+						if frame.Repeat != 0 {
+							enc.AddInt("repeat", frame.Repeat)
+						}
+						return nil
+					}))
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			}))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if len(e.Suppressed) > 0 {
+		err := enc.AddArray("suppressed", zapcore.ArrayMarshalerFunc(func(arr zapcore.ArrayEncoder) error {
+			for _, s := range e.Suppressed {
+				if s != nil {
+					arr.AppendString(s.Error())
+				}
+			}
+			return nil
+		}))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zapadapter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/microbus-io/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestZapAdapter_Field(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	logger := zap.New(core)
+
+	err := errors.New("oops", 409, "key", "value")
+	logger.Error("Request failed", Field(err), NamedField("other", err, WithStackString()), Field(nil))
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	m := entries[0].ContextMap()
+	e, ok := m["error"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected error object, got %v", m["error"])
+	}
+	if e["msg"] != "oops" {
+		t.Errorf("expected msg 'oops', got %v", e["msg"])
+	}
+	if e["statusCode"] != 409 {
+		t.Errorf("expected statusCode 409, got %v", e["statusCode"])
+	}
+	props, _ := e["properties"].(map[string]any)
+	if props["key"] != "value" {
+		t.Errorf("expected property 'value', got %v", props["key"])
+	}
+	stack, _ := e["stack"].([]any)
+	if len(stack) == 0 {
+		t.Fatalf("expected stack frames")
+	}
+	frame, _ := stack[0].(map[string]any)
+	if !strings.HasSuffix(frame["file"].(string), "zapadapter_test.go") {
+		t.Errorf("unexpected frame file %v", frame["file"])
+	}
+
+	other, _ := m["other"].(map[string]any)
+	stackString, _ := other["stack"].(string)
+	if !strings.Contains(stackString, "zapadapter_test.go") {
+		t.Errorf("expected stack string, got %v", other["stack"])
+	}
+}
+
+func TestZapAdapter_Scrub(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	errors.SetScrubRules(errors.ScrubEmails)
+	defer errors.SetScrubRules()
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	logger := zap.New(core)
+
+	err := errors.New("failed to notify jane@example.com", "email", "jane@example.com")
+	logger.Error("Request failed", Field(err))
+
+	m := logs.All()[0].ContextMap()
+	if out := fmt.Sprintf("%v", m); strings.Contains(out, "jane@example.com") {
+		t.Errorf("expected scrubbed fields, got %s", out)
+	}
+}
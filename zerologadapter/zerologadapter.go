@@ -0,0 +1,82 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package zerologadapter converts traced errors to zerolog dictionaries.
+
+	log.Error().Dict("error", zerologadapter.Dict(err)).Msg("Request failed")
+*/
+package zerologadapter
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/microbus-io/errors"
+	"github.com/rs/zerolog"
+)
+
+// Dict returns a zerolog dictionary with the message, status code, trace ID, properties, stack and suppressed errors of the error.
+// The scrub rules set by errors.SetScrubRules are applied. A nil error results in an empty dictionary.
+func Dict(err error) *zerolog.Event {
+	dict := zerolog.Dict()
+	if err == nil {
+		return dict
+	}
+	e := errors.Scrub(err)
+	dict.Str("msg", e.Error())
+	if e.StatusCode != 0 {
+		dict.Int("statusCode", e.StatusCode)
+	}
+	if strings.Trim(e.Trace, "0") != "" {
+		dict.Str("trace", e.Trace)
+	}
+	if strings.Trim(e.Span, "0") != "" {
+		dict.Str("span", e.Span)
+	}
+	if len(e.Properties) > 0 {
+		props := zerolog.Dict()
+		for _, k := range slices.Sorted(maps.Keys(e.Properties)) {
+			props.Interface(k, e.Properties[k])
+		}
+		dict.Dict("properties", props)
+	}
+	if len(e.Stack) > 0 {
+		stack := zerolog.Arr()
+		for _, frame := range e.Stack {
+			f := zerolog.Dict().
+				Str("func", frame.Function).
+				Str("file", frame.File).
+				Int("line", frame.Line)
+			if frame.Repeat != 0 {
+				f.Int("repeat", frame.Repeat)
+			}
+			stack.Dict(f)
+		}
+		dict.Array("stack", stack)
+	}
+	if len(e.Suppressed) > 0 {
+		suppressed := make([]string, 0, len(e.Suppressed))
+		for _, s := range e.Suppressed {
+			if s != nil {
+				suppressed = append(suppressed, s.Error())
+			}
+		}
+		dict.Strs("suppressed", suppressed)
+	}
+	return dict
+}
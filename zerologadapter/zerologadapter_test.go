@@ -0,0 +1,81 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zerologadapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/microbus-io/errors"
+	"github.com/rs/zerolog"
+)
+
+func TestZerologAdapter_Dict(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+
+	err := errors.New("oops", 409, "key", "value")
+	err = errors.AddSuppressed(err, errors.New("rollback failed"))
+	logger.Error().Dict("error", Dict(err)).Msg("Request failed")
+
+	var m map[string]any
+	if jsonErr := json.Unmarshal(buf.Bytes(), &m); jsonErr != nil {
+		t.Fatal(jsonErr)
+	}
+	e, ok := m["error"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected error object, got %v", m["error"])
+	}
+	if e["msg"] != "oops" {
+		t.Errorf("expected msg 'oops', got %v", e["msg"])
+	}
+	if e["statusCode"] != 409.0 {
+		t.Errorf("expected statusCode 409, got %v", e["statusCode"])
+	}
+	if props, _ := e["properties"].(map[string]any); props["key"] != "value" {
+		t.Errorf("expected property 'value', got %v", e["properties"])
+	}
+	stack, _ := e["stack"].([]any)
+	if len(stack) == 0 {
+		t.Fatalf("expected stack frames")
+	}
+	if frame, _ := stack[0].(map[string]any); !strings.HasSuffix(frame["file"].(string), "zerologadapter_test.go") {
+		t.Errorf("unexpected frame %v", stack[0])
+	}
+	if suppressed, _ := e["suppressed"].([]any); len(suppressed) != 1 || suppressed[0] != "rollback failed" {
+		t.Errorf("unexpected suppressed %v", e["suppressed"])
+	}
+}
+
+func TestZerologAdapter_Scrub(t *testing.T) {
+	// Not parallel because it modifies the package settings
+	errors.SetScrubRules(errors.ScrubEmails)
+	defer errors.SetScrubRules()
+
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+
+	err := errors.New("failed to notify jane@example.com", "email", "jane@example.com")
+	logger.Error().Dict("error", Dict(err)).Msg("Request failed")
+	if strings.Contains(buf.String(), "jane@example.com") {
+		t.Errorf("expected scrubbed fields, got %s", buf.String())
+	}
+}